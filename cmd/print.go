@@ -17,21 +17,33 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"cmp"
+	stdjson "encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/common_emitter"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	// Call init function for the providers
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/apisix"
@@ -51,11 +63,22 @@ import (
 	_ "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/standard"
 )
 
+// Supported values of the --output-mode flag.
+const (
+	objectsOutputMode = "objects"
+	listOutputMode    = "list"
+)
+
 type PrintRunner struct {
 	// outputFormat contains currently set output format. Value assigned via --output/-o flag.
 	// Defaults to YAML.
 	outputFormat string
 
+	// outputMode determines whether objects are printed one by one or wrapped
+	// in a single v1 List. Value assigned via --output-mode flag.
+	// Defaults to objects.
+	outputMode string
+
 	// inputFile contains the paths to YAML manifest files to process. Value assigned via --input-file flag.
 	inputFile []string
 
@@ -86,6 +109,11 @@ type PrintRunner struct {
 
 	// allowExperimentalGatewayAPI indicates whether Experimental Gateway API features (like URLRewrite) should be included in the output.
 	allowExperimentalGatewayAPI bool
+
+	// gatewayAddressMode determines what happens to the addresses used by the
+	// source load balancers. Value assigned via --gateway-addresses flag.
+	// Defaults to comment.
+	gatewayAddressMode string
 }
 
 // PrintGatewayAPIObjects performs necessary steps to digest and print
@@ -115,7 +143,7 @@ func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) er
 		}
 		allFiles = append(allFiles, path)
 	}
-	var gatewayResources []i2gw.GatewayResources
+	var gatewayResources i2gw.GatewayResources
 	var report *notifications.Report
 
 	var inputReader io.Reader
@@ -151,7 +179,7 @@ func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) er
 		}
 	}
 
-	gatewayResources, report, err = i2gw.ToGatewayAPIResources(cmd.Context(), pr.namespaceFilter, inputReader, pr.providers, pr.emitter, pr.getProviderSpecificFlags(), pr.allowExperimentalGatewayAPI, noColor)
+	gatewayResources, report, err = i2gw.ToGatewayAPIResources(cmd.Context(), pr.namespaceFilter, inputReader, pr.providers, pr.emitter, pr.getProviderSpecificFlags(), pr.allowExperimentalGatewayAPI, common_emitter.GatewayAddressMode(pr.gatewayAddressMode), noColor)
 
 	if err != nil {
 		return err
@@ -163,158 +191,212 @@ func (pr *PrintRunner) PrintGatewayAPIObjects(cmd *cobra.Command, _ []string) er
 	return nil
 }
 
-func (pr *PrintRunner) outputResult(gatewayResources []i2gw.GatewayResources) {
-	resourceCount := 0
+func (pr *PrintRunner) outputResult(gatewayResources i2gw.GatewayResources) {
+	// The partial Services aren't objects to apply with the other resources.
+	printServicePatches(os.Stderr, gatewayResources.ServicePatches)
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.GatewayClasses)
-		for _, gatewayClass := range r.GatewayClasses {
-			gatewayClass := gatewayClass
-			err := pr.resourcePrinter.PrintObj(&gatewayClass, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s GatewayClass: %v\n", gatewayClass.Name, err)
-			}
+	objects := sortedObjects(gatewayResources)
+
+	if len(objects) == 0 {
+		msg := "No resources found"
+		if pr.namespaceFilter != "" {
+			msg = fmt.Sprintf("%s in %s namespace", msg, pr.namespaceFilter)
 		}
+		fmt.Println(msg)
+		return
 	}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.Gateways)
-		for _, gateway := range r.Gateways {
-			gateway := gateway
-			if gateway.Annotations == nil {
-				gateway.Annotations = make(map[string]string)
-			}
-			gateway.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&gateway, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s Gateway: %v\n", gateway.Name, err)
-			}
-		}
+	if pr.outputMode == listOutputMode || pr.outputFormat == "json" {
+		// Comments can't be placed next to the objects, print them separately.
+		printGatewayComments(os.Stderr, gatewayResources.GatewayComments)
 	}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.HTTPRoutes)
-		for _, httpRoute := range r.HTTPRoutes {
-			httpRoute := httpRoute
-			if httpRoute.Annotations == nil {
-				httpRoute.Annotations = make(map[string]string)
-			}
-			httpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&httpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s HTTPRoute: %v\n", httpRoute.Name, err)
-			}
+	if pr.outputMode == listOutputMode {
+		list, err := toList(objects)
+		if err != nil {
+			fmt.Printf("# Error building List: %v\n", err)
+			return
 		}
+		if err := pr.resourcePrinter.PrintObj(list, os.Stdout); err != nil {
+			fmt.Printf("# Error printing List: %v\n", err)
+		}
+		return
 	}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.GRPCRoutes)
-		for _, grpcRoute := range r.GRPCRoutes {
-			grpcRoute := grpcRoute
-			if grpcRoute.Annotations == nil {
-				grpcRoute.Annotations = make(map[string]string)
+	for _, obj := range objects {
+		if gatewayExtension, ok := obj.(*unstructured.Unstructured); ok {
+			fmt.Println("---")
+			if err := PrintUnstructuredAsYaml(gatewayExtension); err != nil {
+				fmt.Printf("# Error printing %s gatewayExtension: %v\n", gatewayExtension.GetName(), err)
 			}
-			grpcRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&grpcRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s GRPCRoute: %v\n", grpcRoute.Name, err)
+			continue
+		}
+		if gateway, ok := obj.(*gatewayv1.Gateway); ok && pr.outputFormat != "json" {
+			comments := gatewayResources.GatewayComments[types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}]
+			if len(comments) > 0 {
+				pr.printObjWithComments(obj, comments)
+				continue
 			}
 		}
+		if err := pr.resourcePrinter.PrintObj(obj, os.Stdout); err != nil {
+			fmt.Printf("# Error printing %s %s: %v\n", obj.GetName(), obj.GetObjectKind().GroupVersionKind().Kind, err)
+		}
 	}
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.TLSRoutes)
-		for _, tlsRoute := range r.TLSRoutes {
-			tlsRoute := tlsRoute
-			if tlsRoute.Annotations == nil {
-				tlsRoute.Annotations = make(map[string]string)
-			}
-			tlsRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&tlsRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s TLSRoute: %v\n", tlsRoute.Name, err)
-			}
-		}
+// printObjWithComments prints the object with the comments placed right after
+// the document separator, so they stay attached to the object.
+func (pr *PrintRunner) printObjWithComments(obj client.Object, comments []string) {
+	var buf bytes.Buffer
+	if err := pr.resourcePrinter.PrintObj(obj, &buf); err != nil {
+		fmt.Printf("# Error printing %s %s: %v\n", obj.GetName(), obj.GetObjectKind().GroupVersionKind().Kind, err)
+		return
 	}
+	out := buf.String()
+	if rest, found := strings.CutPrefix(out, "---\n"); found {
+		fmt.Print("---\n")
+		out = rest
+	}
+	for _, comment := range comments {
+		fmt.Printf("# %s\n", comment)
+	}
+	fmt.Print(out)
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.TCPRoutes)
-		for _, tcpRoute := range r.TCPRoutes {
-			tcpRoute := tcpRoute
-			if tcpRoute.Annotations == nil {
-				tcpRoute.Annotations = make(map[string]string)
-			}
-			tcpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&tcpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s TCPRoute: %v\n", tcpRoute.Name, err)
-			}
+// printGatewayComments prints the comments of every Gateway, prefixed by the
+// Gateway they belong to.
+func printGatewayComments(w io.Writer, gatewayComments map[types.NamespacedName][]string) {
+	keys := slices.SortedFunc(maps.Keys(gatewayComments), func(a, b types.NamespacedName) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "# Gateway %s:\n", key)
+		for _, comment := range gatewayComments[key] {
+			fmt.Fprintf(w, "# %s\n", comment)
 		}
 	}
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.UDPRoutes)
-		for _, udpRoute := range r.UDPRoutes {
-			udpRoute := udpRoute
-			if udpRoute.Annotations == nil {
-				udpRoute.Annotations = make(map[string]string)
-			}
-			udpRoute.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&udpRoute, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s UDPRoute: %v\n", udpRoute.Name, err)
-			}
+// printServicePatches prints the kubectl commands patching the existing
+// Services with the fields of the partial Services.
+func printServicePatches(w io.Writer, servicePatches map[types.NamespacedName]corev1.Service) {
+	if len(servicePatches) == 0 {
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(servicePatches), func(a, b types.NamespacedName) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	fmt.Fprintln(w, "# Patch the existing Services with:")
+	for _, key := range keys {
+		patch, err := servicePatch(servicePatches[key])
+		if err != nil {
+			fmt.Fprintf(w, "# Error building the patch of Service %s: %v\n", key, err)
+			continue
 		}
+		fmt.Fprintf(w, "kubectl patch service %s --namespace %s --type strategic --patch '%s'\n", key.Name, key.Namespace, patch)
 	}
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.BackendTLSPolicies)
-		for _, backendTLSPolicy := range r.BackendTLSPolicies {
-			backendTLSPolicy := backendTLSPolicy
-			if backendTLSPolicy.Annotations == nil {
-				backendTLSPolicy.Annotations = make(map[string]string)
-			}
-			backendTLSPolicy.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&backendTLSPolicy, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s BackendTLSPolicy: %v\n", backendTLSPolicy.Name, err)
+// servicePatch returns the strategic merge patch setting the spec of the partial
+// Service. The unset targetPort of the ports isn't omitted when empty, so it is
+// removed to keep the targetPort of the Service.
+func servicePatch(service corev1.Service) ([]byte, error) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&service.Spec)
+	if err != nil {
+		return nil, err
+	}
+	ports, found, err := unstructured.NestedSlice(spec, "ports")
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for i, port := range service.Spec.Ports {
+			if port.TargetPort == (intstr.IntOrString{}) {
+				delete(ports[i].(map[string]interface{}), "targetPort")
 			}
 		}
+		if err := unstructured.SetNestedSlice(spec, ports, "ports"); err != nil {
+			return nil, err
+		}
 	}
+	return stdjson.Marshal(map[string]interface{}{"spec": spec})
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.ReferenceGrants)
-		for _, referenceGrant := range r.ReferenceGrants {
-			referenceGrant := referenceGrant
-			if referenceGrant.Annotations == nil {
-				referenceGrant.Annotations = make(map[string]string)
-			}
-			referenceGrant.Annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
-			err := pr.resourcePrinter.PrintObj(&referenceGrant, os.Stdout)
-			if err != nil {
-				fmt.Printf("# Error printing %s ReferenceGrant: %v\n", referenceGrant.Name, err)
-			}
-		}
+// sortedObjects returns the objects to print ordered by kind, then by
+// namespace and name, so the output is stable across runs. Gateway API kinds
+// come first in a fixed order, followed by the Gateway extensions.
+func sortedObjects(gatewayResources i2gw.GatewayResources) []client.Object {
+	var objects []client.Object
+	objects = append(objects, sortedTypedObjects(gatewayResources.GatewayClasses, false)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.Gateways, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.HTTPRoutes, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.GRPCRoutes, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.TLSRoutes, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.TCPRoutes, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.UDPRoutes, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.BackendTLSPolicies, true)...)
+	objects = append(objects, sortedTypedObjects(gatewayResources.ReferenceGrants, true)...)
+
+	gatewayExtensions := slices.Clone(gatewayResources.GatewayExtensions)
+	slices.SortStableFunc(gatewayExtensions, func(a, b unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(a.GroupVersionKind().Group, b.GroupVersionKind().Group),
+			cmp.Compare(a.GetKind(), b.GetKind()),
+			cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+	for i := range gatewayExtensions {
+		objects = append(objects, &gatewayExtensions[i])
 	}
+	return objects
+}
 
-	for _, r := range gatewayResources {
-		resourceCount += len(r.GatewayExtensions)
-		for _, gatewayExtension := range r.GatewayExtensions {
-			gatewayExtension := gatewayExtension
-			fmt.Println("---")
-			if err := PrintUnstructuredAsYaml(&gatewayExtension); err != nil {
-				fmt.Printf("# Error printing %s gatewayExtension: %v\n", gatewayExtension.GetName(), err)
+// sortedTypedObjects returns copies of the objects sorted by namespace and
+// name, adding the generator annotation when requested.
+func sortedTypedObjects[T any, PT interface {
+	*T
+	client.Object
+}](objects map[types.NamespacedName]T, annotate bool) []client.Object {
+	keys := slices.SortedFunc(maps.Keys(objects), func(a, b types.NamespacedName) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	result := make([]client.Object, 0, len(keys))
+	for _, key := range keys {
+		obj := objects[key]
+		objPtr := PT(&obj)
+		if annotate {
+			annotations := maps.Clone(objPtr.GetAnnotations())
+			if annotations == nil {
+				annotations = make(map[string]string)
 			}
+			annotations[i2gw.GeneratorAnnotationKey] = fmt.Sprintf("ingress2gateway-%s", i2gw.Version)
+			objPtr.SetAnnotations(annotations)
 		}
+		result = append(result, objPtr)
 	}
+	return result
+}
 
-	if resourceCount == 0 {
-		msg := "No resources found"
-		if pr.namespaceFilter != "" {
-			msg = fmt.Sprintf("%s in %s namespace", msg, pr.namespaceFilter)
+// toList wraps the objects in a single v1 List.
+func toList(objects []client.Object) (*metav1.List, error) {
+	list := &metav1.List{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+		Items:    make([]runtime.RawExtension, 0, len(objects)),
+	}
+	for _, obj := range objects {
+		u, err := i2gw.CastToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
 		}
-		fmt.Println(msg)
+		raw, err := u.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		list.Items = append(list.Items, runtime.RawExtension{Raw: raw})
 	}
+	return list, nil
 }
 
 // initializeResourcePrinter assign a specific type of printers.ResourcePrinter
@@ -378,6 +460,18 @@ func newPrintCommand() *cobra.Command {
 		Short: "Prints Gateway API objects generated from ingress and provider-specific resources.",
 		RunE:  pr.PrintGatewayAPIObjects,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if slices.Contains(pr.providers, i2gw.AutoProviders) && len(pr.providers) != 1 {
+				return fmt.Errorf("%s must be the only provider when specified", i2gw.AutoProviders)
+			}
+
+			if !slices.Contains(common_emitter.GatewayAddressModes, common_emitter.GatewayAddressMode(pr.gatewayAddressMode)) {
+				return fmt.Errorf("%s is not a supported gateway addresses mode, must be one of: %v", pr.gatewayAddressMode, common_emitter.GatewayAddressModes)
+			}
+
+			if pr.outputMode != objectsOutputMode && pr.outputMode != listOutputMode {
+				return fmt.Errorf("%s is not a supported output mode, must be one of: %s, %s", pr.outputMode, objectsOutputMode, listOutputMode)
+			}
+
 			openAPIExist := slices.Contains(pr.providers, "openapi3")
 			if openAPIExist && len(pr.providers) != 1 {
 				return fmt.Errorf("openapi3 must be the only provider when specified")
@@ -401,6 +495,9 @@ func newPrintCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&pr.outputFormat, "output", "o", "yaml",
 		"Output format. One of: (yaml, json, kyaml).")
 
+	cmd.Flags().StringVar(&pr.outputMode, "output-mode", objectsOutputMode,
+		fmt.Sprintf("Output mode. One of: (%s, %s). The %s mode wraps all the generated objects in a single v1 List.", objectsOutputMode, listOutputMode, listOutputMode))
+
 	cmd.Flags().StringSliceVar(&pr.inputFile, "input-file", []string{},
 		`Path to manifest files. When set, the tool will read ingresses from the files instead of reading from the cluster. Supported files are yaml and json.`)
 
@@ -415,7 +512,10 @@ if specified with --namespace.`)
 		fmt.Sprintf("If present, the tool will try to use the specified emitter to generate the Gateway API resources, supported values are %v. The `standard` emitter will only output Gateway API", i2gw.GetSupportedEmitters()))
 
 	cmd.Flags().StringSliceVar(&pr.providers, "providers", []string{},
-		fmt.Sprintf("If present, the tool will try to convert only resources related to the specified providers, supported values are %v.", i2gw.GetSupportedProviders()))
+		fmt.Sprintf("If present, the tool will try to convert only resources related to the specified providers, supported values are %v. Use %q to detect the providers from the input resources.", i2gw.GetSupportedProviders(), i2gw.AutoProviders))
+
+	cmd.Flags().StringVar(&pr.gatewayAddressMode, "gateway-addresses", string(common_emitter.GatewayAddressModeComment),
+		fmt.Sprintf("What to do with the addresses used by the source load balancers, such as Ingress status IPs and reserved static IPs. One of: %v. preserve sets them on Gateway spec.addresses, omit drops them, and comment prints them as comments next to the Gateway.", common_emitter.GatewayAddressModes))
 
 	cmd.Flags().BoolVar(&pr.allowExperimentalGatewayAPI, "allow-experimental-gw-api", false, "If present, the tool will include Experimental Gateway API fields (e.g. URLRewrite) in the output. Default is false.")
 
@@ -445,9 +545,14 @@ func getNamespaceInCurrentContext() (string, error) {
 // getProviderSpecificFlags returns the provider specific flags input by the user.
 // The flags are returned in a map where the key is the provider name and the value is a map of flag name to flag value.
 func (pr *PrintRunner) getProviderSpecificFlags() map[string]map[string]string {
+	providers := pr.providers
+	if slices.Contains(providers, i2gw.AutoProviders) {
+		// The providers are only known after detection, so keep the flags of all of them.
+		providers = i2gw.GetSupportedProviders()
+	}
 	providerSpecificFlags := make(map[string]map[string]string)
 	for flagName, value := range pr.providerSpecificFlags {
-		provider, found := lo.Find(pr.providers, func(p string) bool { return strings.HasPrefix(flagName, fmt.Sprintf("%s-", p)) })
+		provider, found := lo.Find(providers, func(p string) bool { return strings.HasPrefix(flagName, fmt.Sprintf("%s-", p)) })
 		if !found {
 			continue
 		}
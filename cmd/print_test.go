@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
@@ -25,7 +26,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/printers"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func Test_getResourcePrinter(t *testing.T) {
@@ -309,6 +317,19 @@ func Test_getProviderSpecificFlags(t *testing.T) {
 			providers:             []string{"provider-a", "provider-b", "provider-c"},
 			expected:              map[string]map[string]string{},
 		},
+		{
+			name: "Provider specific configuration with auto-detected providers",
+			providerSpecificFlags: map[string]*string{
+				"ingress-nginx-conf1": &value1,
+				"gce-conf2":           &value2,
+				"provider-conf":       &value1,
+			},
+			providers: []string{"auto"},
+			expected: map[string]map[string]string{
+				"ingress-nginx": {"conf1": value1},
+				"gce":           {"conf2": value2},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -324,3 +345,118 @@ func Test_getProviderSpecificFlags(t *testing.T) {
 		})
 	}
 }
+
+func Test_sortedObjects(t *testing.T) {
+	httpRoute := func(namespace, name string) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}
+	}
+	extension := func(kind, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind(kind)
+		u.SetNamespace("default")
+		u.SetName(name)
+		return u
+	}
+
+	gatewayResources := i2gw.GatewayResources{
+		Gateways: map[types.NamespacedName]gatewayv1.Gateway{
+			{Namespace: "default", Name: "gw"}: {
+				TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			},
+		},
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "b", Name: "a"}: httpRoute("b", "a"),
+			{Namespace: "a", Name: "z"}: httpRoute("a", "z"),
+			{Namespace: "a", Name: "b"}: httpRoute("a", "b"),
+		},
+		GatewayExtensions: []unstructured.Unstructured{extension("Policy", "p"), extension("Filter", "f")},
+	}
+
+	var actual []string
+	for _, obj := range sortedObjects(gatewayResources) {
+		actual = append(actual, fmt.Sprintf("%s %s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName()))
+	}
+	expected := []string{
+		"Gateway default/gw",
+		"HTTPRoute a/b",
+		"HTTPRoute a/z",
+		"HTTPRoute b/a",
+		"Filter default/f",
+		"Policy default/p",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Unexpected object order (-want +got):\n%s", diff)
+	}
+
+	if _, found := gatewayResources.HTTPRoutes[types.NamespacedName{Namespace: "a", Name: "b"}].Annotations[i2gw.GeneratorAnnotationKey]; found {
+		t.Errorf("Expected the generator annotation not to be added to the input objects")
+	}
+}
+
+func Test_printServicePatches(t *testing.T) {
+	appProtocol := "kubernetes.io/ws"
+	servicePatch := func(namespace, name string, ports ...corev1.ServicePort) corev1.Service {
+		return corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+
+	var buf bytes.Buffer
+	printServicePatches(&buf, map[types.NamespacedName]corev1.Service{
+		{Namespace: "default", Name: "ws"}: servicePatch("default", "ws",
+			corev1.ServicePort{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, AppProtocol: &appProtocol}),
+		{Namespace: "apps", Name: "chat"}: servicePatch("apps", "chat",
+			corev1.ServicePort{Port: 8080, TargetPort: intstr.FromInt32(9090), AppProtocol: &appProtocol}),
+	})
+
+	expected := `# Patch the existing Services with:
+kubectl patch service chat --namespace apps --type strategic --patch '{"spec":{"ports":[{"appProtocol":"kubernetes.io/ws","port":8080,"targetPort":9090}]}}'
+kubectl patch service ws --namespace default --type strategic --patch '{"spec":{"ports":[{"appProtocol":"kubernetes.io/ws","name":"http","port":80,"protocol":"TCP"}]}}'
+`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("Unexpected Service patches (-want +got):\n%s", diff)
+	}
+
+	buf.Reset()
+	printServicePatches(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing printed without Service patches, got %q", buf.String())
+	}
+}
+
+func Test_toList(t *testing.T) {
+	objects := sortedObjects(i2gw.GatewayResources{
+		HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{
+			{Namespace: "default", Name: "route"}: {
+				TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+			},
+		},
+	})
+
+	list, err := toList(objects)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list.Kind != "List" || list.APIVersion != "v1" {
+		t.Errorf("Expected a v1 List, got %s %s", list.APIVersion, list.Kind)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(list.Items))
+	}
+
+	item := &unstructured.Unstructured{}
+	if err := item.UnmarshalJSON(list.Items[0].Raw); err != nil {
+		t.Fatalf("Failed to decode list item: %v", err)
+	}
+	if item.GetKind() != "HTTPRoute" || item.GetName() != "route" || item.GetAnnotations()[i2gw.GeneratorAnnotationKey] == "" {
+		t.Errorf("Unexpected list item: %v", item.Object)
+	}
+}
@@ -395,6 +395,168 @@ func TestIngressNGINXCanary(t *testing.T) {
 			},
 		})
 	})
+	t.Run("canary by header pattern", func(t *testing.T) {
+		suffix, err := framework.RandString()
+		require.NoError(t, err)
+		host := fmt.Sprintf("canary-header-pattern-%s.com", suffix)
+		runTestCase(t, &framework.TestCase{
+			GatewayImplementation: implementation.IstioName,
+			Providers:             []string{ingressnginx.Name},
+			Backends: []framework.Backend{
+				{Name: framework.DummyAppName1},
+				{Name: framework.DummyAppName2},
+			},
+			ProviderFlags: map[string]map[string]string{
+				ingressnginx.Name: {
+					ingressnginx.NginxIngressClassFlag: ingressnginx.NginxIngressClass,
+				},
+			},
+			Ingresses: []*networkingv1.Ingress{
+				framework.BasicIngress().
+					WithName("prod").
+					WithHost(host).
+					WithIngressClass(ingressnginx.NginxIngressClass).
+					WithBackend(framework.DummyAppName1).
+					Build(),
+				framework.BasicIngress().
+					WithName("canary-pattern").
+					WithHost(host).
+					WithIngressClass(ingressnginx.NginxIngressClass).
+					WithAnnotation("nginx.ingress.kubernetes.io/canary", "true").
+					WithAnnotation("nginx.ingress.kubernetes.io/canary-by-header", "X-Canary").
+					WithAnnotation("nginx.ingress.kubernetes.io/canary-by-header-pattern", "beta|alpha").
+					WithBackend(framework.DummyAppName2).
+					Build(),
+			},
+			Verifiers: map[string][]framework.Verifier{
+				"prod": {
+					// Every request with a header matching the pattern should go
+					// to the canary backend.
+					&framework.CanaryVerifier{
+						Verifier: &framework.HTTPRequestVerifier{
+							Host: host,
+							Path: "/hostname",
+							RequestHeaders: map[string]string{
+								"X-Canary": "beta",
+							},
+							BodyRegex: regexp.MustCompile("^dummy-app2"),
+						},
+						Runs:         20,
+						MinSuccesses: 0.9,
+						MaxSuccesses: 1.1,
+					},
+					// A header not matching the pattern is ignored.
+					&framework.CanaryVerifier{
+						Verifier: &framework.HTTPRequestVerifier{
+							Host: host,
+							Path: "/hostname",
+							RequestHeaders: map[string]string{
+								"X-Canary": "gamma",
+							},
+							BodyRegex: regexp.MustCompile("^dummy-app2"),
+						},
+						Runs:         20,
+						MinSuccesses: -0.1,
+						MaxSuccesses: 0.1,
+					},
+				},
+			},
+		})
+	})
+	t.Run("canary by cookie and weight combined", func(t *testing.T) {
+		suffix, err := framework.RandString()
+		require.NoError(t, err)
+		host := fmt.Sprintf("canary-cookie-%s.com", suffix)
+		runTestCase(t, &framework.TestCase{
+			GatewayImplementation: implementation.IstioName,
+			Providers:             []string{ingressnginx.Name},
+			Backends: []framework.Backend{
+				{Name: framework.DummyAppName1},
+				{Name: framework.DummyAppName2},
+			},
+			ProviderFlags: map[string]map[string]string{
+				ingressnginx.Name: {
+					ingressnginx.NginxIngressClassFlag: ingressnginx.NginxIngressClass,
+				},
+			},
+			Ingresses: []*networkingv1.Ingress{
+				framework.BasicIngress().
+					WithName("prod").
+					WithHost(host).
+					WithIngressClass(ingressnginx.NginxIngressClass).
+					WithBackend(framework.DummyAppName1).
+					Build(),
+				framework.BasicIngress().
+					WithName("canary-cookie").
+					WithHost(host).
+					WithIngressClass(ingressnginx.NginxIngressClass).
+					WithAnnotation("nginx.ingress.kubernetes.io/canary", "true").
+					WithAnnotation("nginx.ingress.kubernetes.io/canary-weight", "20").
+					WithAnnotation("nginx.ingress.kubernetes.io/canary-by-header", "X-Canary").
+					WithAnnotation("nginx.ingress.kubernetes.io/canary-by-cookie", "canary").
+					WithBackend(framework.DummyAppName2).
+					Build(),
+			},
+			Verifiers: map[string][]framework.Verifier{
+				"prod": {
+					// With the canary cookie set to "always", all requests should go
+					// to the canary backend regardless of weight.
+					&framework.CanaryVerifier{
+						Verifier: &framework.HTTPRequestVerifier{
+							Host: host,
+							Path: "/hostname",
+							RequestHeaders: map[string]string{
+								"Cookie": "session=abc; canary=always",
+							},
+							BodyRegex: regexp.MustCompile("^dummy-app2"),
+						},
+						Runs:         20,
+						MinSuccesses: 0.9,
+						MaxSuccesses: 1.1,
+					},
+					// With the canary cookie set to "never", no request should go
+					// to the canary backend regardless of weight.
+					&framework.CanaryVerifier{
+						Verifier: &framework.HTTPRequestVerifier{
+							Host: host,
+							Path: "/hostname",
+							RequestHeaders: map[string]string{
+								"Cookie": "canary=never",
+							},
+							BodyRegex: regexp.MustCompile("^dummy-app2"),
+						},
+						Runs:         20,
+						MinSuccesses: -0.1,
+						MaxSuccesses: 0.1,
+					},
+					// The header takes precedence over the cookie.
+					&framework.HTTPRequestVerifier{
+						Host: host,
+						Path: "/hostname",
+						RequestHeaders: map[string]string{
+							"X-Canary": "never",
+							"Cookie":   "canary=always",
+						},
+						BodyRegex: regexp.MustCompile("^dummy-app1"),
+					},
+					// With another cookie value, the canary-weight (20%) applies.
+					&framework.CanaryVerifier{
+						Verifier: &framework.HTTPRequestVerifier{
+							Host: host,
+							Path: "/hostname",
+							RequestHeaders: map[string]string{
+								"Cookie": "canary=maybe",
+							},
+							BodyRegex: regexp.MustCompile("^dummy-app2"),
+						},
+						Runs:         200,
+						MinSuccesses: 0.1,
+						MaxSuccesses: 0.3,
+					},
+				},
+			},
+		})
+	})
 }
 
 func TestIngressNGINXCORS(t *testing.T) {
@@ -0,0 +1,423 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AutoProviders is the special --providers value that selects the providers
+// by inspecting the input instead of requiring the user to name them.
+const AutoProviders = "auto"
+
+// detectionNotifierName is the source name used for provider detection notifications.
+const detectionNotifierName = "provider-detection"
+
+// Scores contributed by each kind of detection signal. An IngressClass whose
+// controller belongs to a provider is the strongest signal, provider
+// annotations come next, and a bare ingress class name match is the weakest
+// since several providers share default class names (e.g. "nginx").
+const (
+	ingressClassControllerScore = 3
+	annotationPrefixScore       = 2
+	ingressClassNameScore       = 1
+	resourceKindScore           = 3
+)
+
+// defaultIngressClassAnnotation marks the IngressClass used by Ingresses that
+// don't specify one.
+const defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+// ProviderDetectorByName is a map of ProviderDetector functions by a provider
+// name. Providers that can be recognized from their input should add their
+// detection func at startup. Providers without a detector are never selected
+// by --providers=auto.
+var ProviderDetectorByName = map[ProviderName]ProviderDetector{}
+
+// ProviderDetector scores how likely it is that the given input belongs to a
+// provider.
+type ProviderDetector func(input DetectionInput) DetectionResult
+
+// DetectionInput is the provider-agnostic view of the input used to detect
+// providers.
+type DetectionInput struct {
+	Ingresses      []networkingv1.Ingress
+	IngressClasses []networkingv1.IngressClass
+	// Kinds contains the kinds of all objects found in the input file, or the
+	// kinds of all CRDs installed in the cluster.
+	Kinds sets.Set[schema.GroupKind]
+	// HasObjects reports whether objects of one of the Kinds exist. It is nil
+	// when the Kinds come from the objects themselves. Installed CRDs alone
+	// don't mean that the provider is used, so the cluster is checked for objects.
+	HasObjects func(schema.GroupKind) bool
+}
+
+// hasObjects reports whether objects of one of the Kinds exist.
+func (input DetectionInput) hasObjects(kind schema.GroupKind) bool {
+	return input.HasObjects == nil || input.HasObjects(kind)
+}
+
+// DetectionResult holds the score a provider assigned to the input.
+type DetectionResult struct {
+	// IngressScores holds a positive score for every Ingress the provider
+	// claims. Each Ingress is attributed to the provider with the highest score.
+	IngressScores map[types.NamespacedName]int
+	// Score is the score of non-Ingress signals, such as provider CRDs.
+	Score int
+	// Reasons describes the non-Ingress signals that were found.
+	Reasons []string
+}
+
+// DetectionRules describes the signals which identify the input of a provider.
+// Its Detect method can be registered as the ProviderDetector of the provider.
+type DetectionRules struct {
+	// IngressClasses are the default ingress class names of the provider.
+	IngressClasses []string
+	// Controllers are the IngressClass spec.controller values of the provider.
+	Controllers []string
+	// AnnotationPrefixes are prefixes of Ingress annotations owned by the provider.
+	AnnotationPrefixes []string
+	// Kinds are the custom resource kinds the provider converts.
+	Kinds []schema.GroupKind
+	// SupportingKinds are custom resource kinds which only configure the
+	// Ingresses of the provider. Their CRDs are often installed in clusters
+	// which don't use the provider, so they are only a signal together with
+	// Ingresses or IngressClasses of the provider.
+	SupportingKinds []schema.GroupKind
+}
+
+// Detect implements ProviderDetector.
+func (r DetectionRules) Detect(input DetectionInput) DetectionResult {
+	result := DetectionResult{
+		IngressScores: make(map[types.NamespacedName]int),
+	}
+
+	controllerByClass := make(map[string]string, len(input.IngressClasses))
+	defaultClass := ""
+	for _, ingressClass := range input.IngressClasses {
+		controllerByClass[ingressClass.Name] = ingressClass.Spec.Controller
+		if ingressClass.Annotations[defaultIngressClassAnnotation] == "true" {
+			defaultClass = ingressClass.Name
+		}
+	}
+
+	for _, ingress := range input.Ingresses {
+		score := 0
+
+		className := ingressClassName(ingress)
+		if className == "" {
+			className = defaultClass
+		}
+		if controller, ok := controllerByClass[className]; ok {
+			// The IngressClass is known, so its controller is authoritative
+			// and the class name alone is not a signal.
+			if slices.Contains(r.Controllers, controller) {
+				score += ingressClassControllerScore
+			}
+		} else if slices.Contains(r.IngressClasses, className) {
+			score += ingressClassNameScore
+		}
+
+		for annotation := range ingress.Annotations {
+			if hasAnyPrefix(annotation, r.AnnotationPrefixes) {
+				score += annotationPrefixScore
+				break
+			}
+		}
+
+		if score > 0 {
+			result.IngressScores[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = score
+		}
+	}
+
+	for _, kind := range r.Kinds {
+		if input.Kinds.Has(kind) && input.hasObjects(kind) {
+			result.Score += resourceKindScore
+			result.Reasons = append(result.Reasons, fmt.Sprintf("found %s resources", kind.String()))
+		}
+	}
+
+	if len(result.IngressScores) > 0 || r.hasIngressClass(input.IngressClasses) {
+		for _, kind := range r.SupportingKinds {
+			if input.Kinds.Has(kind) {
+				result.Score += resourceKindScore
+				result.Reasons = append(result.Reasons, fmt.Sprintf("found %s resources", kind.String()))
+			}
+		}
+	}
+
+	return result
+}
+
+// hasIngressClass returns true if one of the IngressClasses belongs to the provider.
+func (r DetectionRules) hasIngressClass(ingressClasses []networkingv1.IngressClass) bool {
+	for _, ingressClass := range ingressClasses {
+		if slices.Contains(r.Controllers, ingressClass.Spec.Controller) || slices.Contains(r.IngressClasses, ingressClass.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectProviders runs the registered ProviderDetectors against the input and
+// returns the sorted names of the providers which claim at least one Ingress
+// or provider resource. The decision is reported as an INFO notification, and
+// Ingresses that no provider claims, or that several providers claim equally,
+// are reported as warnings.
+func DetectProviders(input DetectionInput, notify notifications.NotifyFunc) []string {
+	providerNames := slices.Sorted(maps.Keys(ProviderDetectorByName))
+
+	results := make(map[ProviderName]DetectionResult, len(providerNames))
+	for _, name := range providerNames {
+		results[name] = ProviderDetectorByName[name](input)
+	}
+
+	selected := sets.New[ProviderName]()
+	claimedIngresses := make(map[ProviderName]int)
+	for i := range input.Ingresses {
+		ingress := &input.Ingresses[i]
+		key := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+
+		bestScore := 0
+		var candidates []ProviderName
+		for _, name := range providerNames {
+			score := results[name].IngressScores[key]
+			switch {
+			case score == 0 || score < bestScore:
+				continue
+			case score > bestScore:
+				bestScore = score
+				candidates = []ProviderName{name}
+			default:
+				candidates = append(candidates, name)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			notify(notifications.WarningNotification, "Ingress is not claimed by any provider and will not be converted", ingress)
+		case 1:
+			selected.Insert(candidates[0])
+			claimedIngresses[candidates[0]]++
+		default:
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Ingress could belong to any of the providers %v and will not be used for detection; use --providers to select the provider explicitly", candidates),
+				ingress)
+		}
+	}
+
+	var decisions []string
+	for _, name := range providerNames {
+		var reasons []string
+		if n := claimedIngresses[name]; n > 0 {
+			reasons = append(reasons, fmt.Sprintf("claimed %d Ingress(es)", n))
+		}
+		if result := results[name]; result.Score > 0 {
+			selected.Insert(name)
+			reasons = append(reasons, result.Reasons...)
+		}
+		if len(reasons) > 0 {
+			decisions = append(decisions, fmt.Sprintf("%s (%s)", name, strings.Join(reasons, ", ")))
+		}
+	}
+
+	if len(decisions) > 0 {
+		notify(notifications.InfoNotification, fmt.Sprintf("Auto-detected providers: %s", strings.Join(decisions, "; ")))
+	}
+
+	detected := make([]string, 0, selected.Len())
+	for _, name := range providerNames {
+		if selected.Has(name) {
+			detected = append(detected, string(name))
+		}
+	}
+	return detected
+}
+
+// readDetectionInputFromFile builds the DetectionInput from manifests. The
+// namespace filter applies to namespaced objects only, IngressClasses are
+// always kept.
+func readDetectionInputFromFile(data []byte, namespace string) (DetectionInput, error) {
+	input := DetectionInput{Kinds: sets.New[schema.GroupKind]()}
+
+	decoder := kubeyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return input, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if u == nil {
+			continue
+		}
+		if !u.IsList() {
+			objs = append(objs, u)
+			continue
+		}
+		err := u.EachListItem(func(object runtime.Object) error {
+			item, ok := object.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("resource list item has unexpected type")
+			}
+			objs = append(objs, item)
+			return nil
+		})
+		if err != nil {
+			return input, err
+		}
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Empty() {
+			continue
+		}
+		if gvk.GroupKind() == (schema.GroupKind{Group: networkingv1.GroupName, Kind: "IngressClass"}) {
+			var ingressClass networkingv1.IngressClass
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingressClass); err != nil {
+				return input, err
+			}
+			input.IngressClasses = append(input.IngressClasses, ingressClass)
+			continue
+		}
+		if namespace != "" && obj.GetNamespace() != namespace {
+			continue
+		}
+		input.Kinds.Insert(gvk.GroupKind())
+		if gvk.GroupKind() == (schema.GroupKind{Group: networkingv1.GroupName, Kind: "Ingress"}) {
+			var ingress networkingv1.Ingress
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &ingress); err != nil {
+				return input, err
+			}
+			input.Ingresses = append(input.Ingresses, ingress)
+		}
+	}
+
+	return input, nil
+}
+
+// readDetectionInputFromCluster builds the DetectionInput from the cluster.
+// Kinds are taken from the installed CRDs, and the objects of the kinds the
+// providers convert are listed when they are detected. IngressClasses and CRDs
+// are optional signals, so failing to list them is reported rather than fatal.
+func readDetectionInputFromCluster(ctx context.Context, cl client.Client, notify notifications.NotifyFunc) (DetectionInput, error) {
+	input := DetectionInput{Kinds: sets.New[schema.GroupKind]()}
+
+	var ingressList networkingv1.IngressList
+	if err := cl.List(ctx, &ingressList); err != nil {
+		return input, fmt.Errorf("failed to get ingresses from the cluster: %w", err)
+	}
+	input.Ingresses = ingressList.Items
+
+	var ingressClassList networkingv1.IngressClassList
+	if err := cl.List(ctx, &ingressClassList); err != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("Failed to list IngressClasses, detection will rely on ingress class names: %v", err))
+	} else {
+		input.IngressClasses = ingressClassList.Items
+	}
+
+	crdList := &unstructured.UnstructuredList{}
+	crdList.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    "CustomResourceDefinitionList",
+	})
+	if err := cl.List(ctx, crdList); err != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("Failed to list CustomResourceDefinitions, provider resources will not be used for detection: %v", err))
+		return input, nil
+	}
+	versions := map[schema.GroupKind]string{}
+	for _, crd := range crdList.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if kind != "" {
+			groupKind := schema.GroupKind{Group: group, Kind: kind}
+			input.Kinds.Insert(groupKind)
+			versions[groupKind] = crdStorageVersion(crd)
+		}
+	}
+
+	input.HasObjects = func(kind schema.GroupKind) bool {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: kind.Group, Version: versions[kind], Kind: kind.Kind + "List"})
+		if err := cl.List(ctx, list, client.Limit(1)); err != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("Failed to list %s resources, they will not be used for detection: %v", kind.String(), err))
+			return false
+		}
+		return len(list.Items) > 0
+	}
+
+	return input, nil
+}
+
+// crdStorageVersion returns the version of the CRD its objects are stored in, or
+// its first served version.
+func crdStorageVersion(crd unstructured.Unstructured) string {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	served := ""
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(version, "name")
+		if storage, _, _ := unstructured.NestedBool(version, "storage"); storage {
+			return name
+		}
+		if isServed, _, _ := unstructured.NestedBool(version, "served"); isServed && served == "" {
+			served = name
+		}
+	}
+	return served
+}
+
+func ingressClassName(ingress networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName != "" {
+		return *ingress.Spec.IngressClassName
+	}
+	return ingress.Annotations[networkingv1beta1.AnnotationIngressClass]
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	testNginxRules = DetectionRules{
+		IngressClasses:     []string{"nginx"},
+		Controllers:        []string{"k8s.io/ingress-nginx"},
+		AnnotationPrefixes: []string{"nginx.ingress.kubernetes.io/"},
+	}
+	testKongRules = DetectionRules{
+		IngressClasses:     []string{"kong"},
+		Controllers:        []string{"ingress-controllers.konghq.com/kong"},
+		AnnotationPrefixes: []string{"konghq.com/"},
+		Kinds:              []schema.GroupKind{{Group: "configuration.konghq.com", Kind: "TCPIngress"}},
+	}
+	testGCERules = DetectionRules{
+		IngressClasses:  []string{"gce"},
+		SupportingKinds: []schema.GroupKind{{Group: "cloud.google.com", Kind: "BackendConfig"}},
+	}
+)
+
+func testIngress(name string, className *string, annotations map[string]string) networkingv1.Ingress {
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec:       networkingv1.IngressSpec{IngressClassName: className},
+	}
+}
+
+func testIngressClass(name, controller string, isDefault bool) networkingv1.IngressClass {
+	ingressClass := networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       networkingv1.IngressClassSpec{Controller: controller},
+	}
+	if isDefault {
+		ingressClass.Annotations = map[string]string{defaultIngressClassAnnotation: "true"}
+	}
+	return ingressClass
+}
+
+func Test_DetectionRules_Detect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    DetectionRules
+		input    DetectionInput
+		expected DetectionResult
+	}{{
+		name:  "ingress class name without IngressClass resource",
+		rules: testNginxRules,
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{testIngress("a", ptr.To("nginx"), nil)},
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{{Namespace: "default", Name: "a"}: ingressClassNameScore},
+		},
+	}, {
+		name:  "IngressClass controller is authoritative over the class name",
+		rules: testNginxRules,
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{
+				testIngress("a", ptr.To("nginx"), nil),
+				testIngress("b", ptr.To("internal"), nil),
+			},
+			IngressClasses: []networkingv1.IngressClass{
+				testIngressClass("nginx", "example.com/other-controller", false),
+				testIngressClass("internal", "k8s.io/ingress-nginx", false),
+			},
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{{Namespace: "default", Name: "b"}: ingressClassControllerScore},
+		},
+	}, {
+		name:  "default IngressClass and annotations",
+		rules: testNginxRules,
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{
+				testIngress("a", nil, map[string]string{
+					"nginx.ingress.kubernetes.io/rewrite-target": "/",
+					"nginx.ingress.kubernetes.io/ssl-redirect":   "true",
+				}),
+			},
+			IngressClasses: []networkingv1.IngressClass{testIngressClass("default", "k8s.io/ingress-nginx", true)},
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{{Namespace: "default", Name: "a"}: ingressClassControllerScore + annotationPrefixScore},
+		},
+	}, {
+		name:  "resource kinds",
+		rules: testKongRules,
+		input: DetectionInput{
+			Kinds: sets.New(schema.GroupKind{Group: "configuration.konghq.com", Kind: "TCPIngress"}),
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{},
+			Score:         resourceKindScore,
+			Reasons:       []string{"found TCPIngress.configuration.konghq.com resources"},
+		},
+	}, {
+		name:  "resource kinds without objects",
+		rules: testKongRules,
+		input: DetectionInput{
+			Kinds:      sets.New(schema.GroupKind{Group: "configuration.konghq.com", Kind: "TCPIngress"}),
+			HasObjects: func(schema.GroupKind) bool { return false },
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{},
+		},
+	}, {
+		name:  "supporting kinds without Ingresses or IngressClasses",
+		rules: testGCERules,
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{testIngress("a", ptr.To("nginx"), nil)},
+			Kinds:     sets.New(schema.GroupKind{Group: "cloud.google.com", Kind: "BackendConfig"}),
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{},
+		},
+	}, {
+		name:  "supporting kinds with an IngressClass",
+		rules: testGCERules,
+		input: DetectionInput{
+			IngressClasses: []networkingv1.IngressClass{testIngressClass("gce", "example.com/gce", false)},
+			Kinds:          sets.New(schema.GroupKind{Group: "cloud.google.com", Kind: "BackendConfig"}),
+		},
+		expected: DetectionResult{
+			IngressScores: map[types.NamespacedName]int{},
+			Score:         resourceKindScore,
+			Reasons:       []string{"found BackendConfig.cloud.google.com resources"},
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.rules.Detect(tc.input)
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("Unexpected detection result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_DetectProviders(t *testing.T) {
+	original := ProviderDetectorByName
+	t.Cleanup(func() { ProviderDetectorByName = original })
+	ProviderDetectorByName = map[ProviderName]ProviderDetector{
+		"ingress-nginx": testNginxRules.Detect,
+		"kong":          testKongRules.Detect,
+		"nginx":         DetectionRules{IngressClasses: []string{"nginx"}, AnnotationPrefixes: []string{"nginx.org/"}}.Detect,
+	}
+
+	testCases := []struct {
+		name                  string
+		input                 DetectionInput
+		expectedProviders     []string
+		expectedNotifications map[notifications.MessageType]int
+	}{{
+		name: "annotations break a class name tie",
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{
+				testIngress("a", ptr.To("nginx"), map[string]string{"nginx.org/rewrites": "serviceName=svc rewrite=/"}),
+				testIngress("b", ptr.To("kong"), nil),
+			},
+			Kinds: sets.New[schema.GroupKind](),
+		},
+		expectedProviders:     []string{"kong", "nginx"},
+		expectedNotifications: map[notifications.MessageType]int{notifications.InfoNotification: 1},
+	}, {
+		name: "ambiguous and unclaimed Ingresses are reported",
+		input: DetectionInput{
+			Ingresses: []networkingv1.Ingress{
+				testIngress("a", ptr.To("nginx"), nil),
+				testIngress("b", ptr.To("traefik"), nil),
+			},
+			Kinds: sets.New[schema.GroupKind](),
+		},
+		expectedProviders:     []string{},
+		expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 2},
+	}, {
+		name: "provider resources select a provider without Ingresses",
+		input: DetectionInput{
+			Kinds: sets.New(schema.GroupKind{Group: "configuration.konghq.com", Kind: "TCPIngress"}),
+		},
+		expectedProviders:     []string{"kong"},
+		expectedNotifications: map[notifications.MessageType]int{notifications.InfoNotification: 1},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			actual := DetectProviders(tc.input, notify)
+			if diff := cmp.Diff(tc.expectedProviders, actual); diff != "" {
+				t.Errorf("Unexpected providers (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_readDetectionInputFromFile(t *testing.T) {
+	data := []byte(`apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: nginx
+spec:
+  controller: k8s.io/ingress-nginx
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: networking.k8s.io/v1
+  kind: Ingress
+  metadata:
+    name: a
+    namespace: default
+  spec:
+    ingressClassName: nginx
+- apiVersion: networking.k8s.io/v1
+  kind: Ingress
+  metadata:
+    name: b
+    namespace: other
+---
+apiVersion: configuration.konghq.com/v1beta1
+kind: TCPIngress
+metadata:
+  name: tcp
+  namespace: default
+`)
+
+	input, err := readDetectionInputFromFile(data, "default")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(input.IngressClasses) != 1 || input.IngressClasses[0].Spec.Controller != "k8s.io/ingress-nginx" {
+		t.Errorf("Expected the nginx IngressClass, got %+v", input.IngressClasses)
+	}
+	if len(input.Ingresses) != 1 || input.Ingresses[0].Name != "a" {
+		t.Errorf("Expected only Ingress default/a, got %+v", input.Ingresses)
+	}
+	expectedKinds := sets.New(
+		schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"},
+		schema.GroupKind{Group: "configuration.konghq.com", Kind: "TCPIngress"},
+	)
+	if !input.Kinds.Equal(expectedKinds) {
+		t.Errorf("Expected kinds %v, got %v", expectedKinds.UnsortedList(), input.Kinds.UnsortedList())
+	}
+}
+
+func Test_readDetectionInputFromCluster(t *testing.T) {
+	crd := func(group, kind string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"group": group,
+				"names": map[string]interface{}{"kind": kind},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1alpha3", "served": true, "storage": false},
+					map[string]interface{}{"name": "v1", "served": true, "storage": true},
+				},
+			},
+		}}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+		obj.SetName(strings.ToLower(kind) + "s." + group)
+		return obj
+	}
+	virtualService := &unstructured.Unstructured{}
+	virtualService.SetGroupVersionKind(schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1", Kind: "VirtualService"})
+	virtualService.SetNamespace("default")
+	virtualService.SetName("reviews")
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	restMapper.Add(schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1", Kind: "VirtualService"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1", Kind: "Gateway"}, meta.RESTScopeNamespace)
+	cl := fake.NewClientBuilder().
+		WithRESTMapper(restMapper).
+		WithObjects(crd("networking.istio.io", "VirtualService"), crd("networking.istio.io", "Gateway"), virtualService).
+		Build()
+
+	input, err := readDetectionInputFromCluster(context.Background(), cl, notifications.NoopNotify)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedKinds := sets.New(
+		schema.GroupKind{Group: "networking.istio.io", Kind: "VirtualService"},
+		schema.GroupKind{Group: "networking.istio.io", Kind: "Gateway"},
+	)
+	if !input.Kinds.Equal(expectedKinds) {
+		t.Errorf("Expected kinds %v, got %v", expectedKinds.UnsortedList(), input.Kinds.UnsortedList())
+	}
+	if !input.HasObjects(schema.GroupKind{Group: "networking.istio.io", Kind: "VirtualService"}) {
+		t.Errorf("Expected VirtualService objects to exist")
+	}
+	if input.HasObjects(schema.GroupKind{Group: "networking.istio.io", Kind: "Gateway"}) {
+		t.Errorf("Expected no Gateway objects, only their CRD is installed")
+	}
+}
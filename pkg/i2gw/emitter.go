@@ -19,6 +19,7 @@ package i2gw
 import (
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -33,6 +34,13 @@ type Emitter interface {
 	Emit(emitterir.EmitterIR) (GatewayResources, field.ErrorList)
 }
 
+// ServiceSessionAffinityEmitter is implemented by the Emitters converting the
+// session affinity of Services to their own policies, for implementations not
+// supporting the sessionPersistence of the routes.
+type ServiceSessionAffinityEmitter interface {
+	KeepsServiceSessionAffinity() bool
+}
+
 // GatewayResources contains all Gateway-API objects and provider Gateway
 // extensions.
 type GatewayResources struct {
@@ -49,6 +57,15 @@ type GatewayResources struct {
 	ReferenceGrants    map[types.NamespacedName]gatewayv1beta1.ReferenceGrant
 
 	GatewayExtensions []unstructured.Unstructured
+
+	// ServicePatches holds partial Services setting fields of the existing
+	// Services, such as the appProtocol of their ports. They aren't objects to
+	// create, so they are printed apart from the other resources.
+	ServicePatches map[types.NamespacedName]corev1.Service
+
+	// GatewayComments holds lines printed as comments next to the Gateway
+	// with the same NamespacedName.
+	GatewayComments map[types.NamespacedName][]string
 }
 
 // EmitterConstructorByName is a map of EmitterConstructor functions by a
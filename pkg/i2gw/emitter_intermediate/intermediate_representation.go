@@ -17,7 +17,12 @@ limitations under the License.
 package emitterir
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate/gce"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -72,17 +77,78 @@ type EmitterIR struct {
 
 	Services map[types.NamespacedName]ServiceContext
 
+	// ConfigMaps holds the ConfigMaps the other resources depend on. The
+	// emitters add the ones referenced by the resources they convert, such as
+	// the CA bundles of client certificate validation.
+	ConfigMaps map[types.NamespacedName]ConfigMapContext
+
+	// ServicePatches holds partial Services to apply to the existing Services,
+	// such as the appProtocol of their ports.
+	ServicePatches map[types.NamespacedName]ServicePatchContext
+
 	GceServices map[types.NamespacedName]gce.ServiceIR
 }
 
+// SessionAffinityMode is how sticky the sessions are when the endpoints of a Service change.
+type SessionAffinityMode string
+
+const (
+	// SessionAffinityBalanced lets sessions move to new endpoints when the Service scales.
+	SessionAffinityBalanced SessionAffinityMode = "Balanced"
+	// SessionAffinityPersistent keeps sessions on their endpoint while it is available.
+	SessionAffinityPersistent SessionAffinityMode = "Persistent"
+)
+
 type SessionAffinity struct {
 	Metadata     ExtensionFeatureMetadata
 	Type         string
+	Mode         SessionAffinityMode
+	CookieName   string
+	CookiePath   string
 	CookieTTLSec *int64
+	// CookieSameSite is the SameSite attribute of the cookie: Strict, Lax or None.
+	CookieSameSite string
+	CookieSecure   bool
+}
+
+// LoadBalancerAlgorithm is the algorithm used to pick the endpoint of a Service.
+type LoadBalancerAlgorithm string
+
+const (
+	LoadBalancerRoundRobin     LoadBalancerAlgorithm = "RoundRobin"
+	LoadBalancerLeastRequest   LoadBalancerAlgorithm = "LeastRequest"
+	LoadBalancerConsistentHash LoadBalancerAlgorithm = "ConsistentHash"
+)
+
+// LoadBalancerHashKeyType is the request attribute hashed by consistent hashing.
+type LoadBalancerHashKeyType string
+
+const (
+	LoadBalancerHashSourceIP LoadBalancerHashKeyType = "SourceIP"
+	LoadBalancerHashHeader   LoadBalancerHashKeyType = "Header"
+	LoadBalancerHashCookie   LoadBalancerHashKeyType = "Cookie"
+	// LoadBalancerHashURI hashes the request URI, including its query string.
+	LoadBalancerHashURI LoadBalancerHashKeyType = "URI"
+)
+
+// LoadBalancerHashKey is the key requests are hashed on.
+type LoadBalancerHashKey struct {
+	Type LoadBalancerHashKeyType
+	// Name is the name of the header or cookie.
+	Name string
+}
+
+// LoadBalancer describes how requests are balanced between the endpoints of a Service.
+type LoadBalancer struct {
+	Metadata  ExtensionFeatureMetadata
+	Algorithm LoadBalancerAlgorithm
+	// HashKey is only set for the ConsistentHash algorithm.
+	HashKey LoadBalancerHashKey
 }
 
 type ServiceContext struct {
 	SessionAffinity *SessionAffinity
+	LoadBalancer    *LoadBalancer
 }
 
 func (s *ServiceContext) UnparsedExtensions() []*ExtensionFeatureMetadata {
@@ -90,6 +156,9 @@ func (s *ServiceContext) UnparsedExtensions() []*ExtensionFeatureMetadata {
 	if s.SessionAffinity != nil {
 		unparsedExtensions = append(unparsedExtensions, &s.SessionAffinity.Metadata)
 	}
+	if s.LoadBalancer != nil {
+		unparsedExtensions = append(unparsedExtensions, &s.LoadBalancer.Metadata)
+	}
 	return unparsedExtensions
 }
 
@@ -98,6 +167,40 @@ type GatewayContext struct {
 	// Emitter IR should be provider/emitter neutral,
 	// But we have GCE for backcompatibility.
 	Gce *gce.GatewayIR
+
+	// Addresses holds the addresses the source load balancers already use.
+	// This is provider-neutral and applied by the common emitter.
+	Addresses []GatewayAddress
+
+	// Comments holds lines to print as comments next to the Gateway, for
+	// information that shouldn't be part of the Gateway spec.
+	Comments []string
+
+	// ClientCertificateValidationByListener maps Gateway listener names to
+	// client certificate validation intent.
+	// This is provider-neutral and applied by the common emitter when
+	// experimental Gateway API features are allowed, or by each custom emitter.
+	ClientCertificateValidationByListener map[gatewayv1.SectionName]*ClientCertificateValidation
+
+	// TLSOptionsByListener maps Gateway listener names to TLS options intent,
+	// such as the TLS versions, cipher suites and HTTP Strict Transport Security.
+	// It is applied by each custom emitter.
+	TLSOptionsByListener map[gatewayv1.SectionName]*ListenerTLSOptions
+}
+
+func (g *GatewayContext) UnparsedExtensions() []*ExtensionFeatureMetadata {
+	var unparsedExtensions []*ExtensionFeatureMetadata
+	for _, x := range g.ClientCertificateValidationByListener {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range g.TLSOptionsByListener {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	return unparsedExtensions
 }
 
 type HTTPRouteContext struct {
@@ -109,9 +212,9 @@ type HTTPRouteContext struct {
 	// This is provider-neutral and applied by the common emitter.
 	PathRewriteByRuleIdx map[int]*PathRewrite
 
-	// BodySizeByRuleIdx maps HTTPRoute rule indices to body size intent.
+	// BufferingByRuleIdx maps HTTPRoute rule indices to body size and buffering intent.
 	// This is provider-neutral and applied by each custom emitter.
-	BodySizeByRuleIdx map[int]*BodySize
+	BufferingByRuleIdx map[int]*Buffering
 
 	// CorsPolicyByRuleIdx maps HTTPRoute rule indices to CORS policy intent.
 	// This map is populated by providers that support CORS (e.g., via annotations) and is
@@ -122,11 +225,36 @@ type HTTPRouteContext struct {
 	// IPRangeControlByRuleIdx maps HTTPRoute rule indices to IP range control intent.
 	// This is provider-neutral and applied by each custom emitter.
 	IPRangeControlByRuleIdx map[int]*IPRangeControl
+
+	// ExternalAuthByRuleIdx maps HTTPRoute rule indices to external authentication intent.
+	// This is provider-neutral and applied by each custom emitter.
+	ExternalAuthByRuleIdx map[int]*ExternalAuth
+
+	// BasicAuthByRuleIdx maps HTTPRoute rule indices to basic authentication intent.
+	// This is provider-neutral and applied by each custom emitter.
+	BasicAuthByRuleIdx map[int]*BasicAuth
+
+	// RateLimitByRuleIdx maps HTTPRoute rule indices to rate limiting intent.
+	// This is provider-neutral and applied by each custom emitter.
+	RateLimitByRuleIdx map[int]*RateLimit
+
+	// RetryByRuleIdx maps HTTPRoute rule indices to retry intent.
+	// This is provider-neutral and applied by the common emitter when
+	// experimental Gateway API features are allowed, or by each custom emitter.
+	RetryByRuleIdx map[int]*Retry
+
+	// CustomErrorResponsesByRuleIdx maps HTTPRoute rule indices to custom error response intent.
+	// This is provider-neutral and applied by each custom emitter.
+	CustomErrorResponsesByRuleIdx map[int]*CustomErrorResponses
+
+	// LocationRewriteByRuleIdx maps HTTPRoute rule indices to Location header rewrite intent.
+	// This is implementation-specific and applied by each custom emitter.
+	LocationRewriteByRuleIdx map[int]*LocationRewrite
 }
 
 func (h *HTTPRouteContext) UnparsedExtensions() []*ExtensionFeatureMetadata {
 	var unparsedExtensions []*ExtensionFeatureMetadata
-	for _, x := range h.BodySizeByRuleIdx {
+	for _, x := range h.BufferingByRuleIdx {
 		if x != nil {
 			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
 		}
@@ -141,9 +269,50 @@ func (h *HTTPRouteContext) UnparsedExtensions() []*ExtensionFeatureMetadata {
 			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
 		}
 	}
+	for _, x := range h.ExternalAuthByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range h.BasicAuthByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range h.RateLimitByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range h.RetryByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range h.CustomErrorResponsesByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
+	for _, x := range h.LocationRewriteByRuleIdx {
+		if x != nil {
+			unparsedExtensions = append(unparsedExtensions, &x.Metadata)
+		}
+	}
 	return unparsedExtensions
 }
 
+// GatewayAddress represents provider-neutral intent to keep serving traffic on
+// an address already used by the source load balancer, such as an address
+// recorded in Ingress status or a reserved static IP.
+type GatewayAddress struct {
+	Metadata ExtensionFeatureMetadata
+	gatewayv1.GatewaySpecAddress
+	// NotReusableReason explains why the address can't be requested by a
+	// Gateway. Such addresses are reported but never set on the Gateway.
+	NotReusableReason string
+}
+
 // TCPTimeouts holds TCP-level timeout configuration for a single HTTPRoute rule.
 type TCPTimeouts struct {
 	Connect *gatewayv1.Duration
@@ -152,20 +321,77 @@ type TCPTimeouts struct {
 }
 
 // PathRewrite represents provider-neutral path rewrite intent.
-// For now it only supports full-path replacement; more fields may be added later.
+// It replaces either the full path or the prefix matched by the rule, unless the
+// rewrite references regex capture groups.
 type PathRewrite struct {
 	Metadata        ExtensionFeatureMetadata
 	ReplaceFullPath string
+	// ReplacePrefixMatch replaces the path prefix matched by the rule instead of the full path.
+	ReplacePrefixMatch string
 	// Headers to add on path rewrite.
 	Headers                     map[string]string
 	RegexCaptureGroupReferences bool
+	// Regex is the rewrite of a path with capture group references, when the pattern
+	// is known. Gateway API can't express it, so it is only applied by
+	// implementation-specific emitters.
+	Regex *RegexPathRewrite
+}
+
+// HeadersFilter returns the filter setting the request headers of the rewrite, or nil
+// when there are none.
+func (r *PathRewrite) HeadersFilter() *gatewayv1.HTTPRouteFilter {
+	if len(r.Headers) == 0 {
+		return nil
+	}
+	headerModifier := &gatewayv1.HTTPHeaderFilter{}
+	for _, headerName := range slices.Sorted(maps.Keys(r.Headers)) {
+		headerModifier.Set = append(headerModifier.Set, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(headerName), Value: r.Headers[headerName]})
+	}
+	return &gatewayv1.HTTPRouteFilter{
+		Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: headerModifier,
+	}
 }
 
-// BodySize represents provider-neutral body size intent.
-type BodySize struct {
+// RegexPathRewrite replaces the path matching Pattern, a RE2 regular expression, with
+// Substitution, which references the capture groups of the pattern as \1, \2...
+type RegexPathRewrite struct {
+	Pattern      string
+	Substitution string
+}
+
+// Buffering represents provider-neutral body size and buffering intent. Unset fields
+// keep the defaults of the implementation.
+type Buffering struct {
 	Metadata   ExtensionFeatureMetadata
 	BufferSize *resource.Quantity
 	MaxSize    *resource.Quantity
+	// RequestBuffering receives the whole request body before sending the request to the backend.
+	RequestBuffering *bool
+	// ResponseBuffering receives the whole response from the backend before sending it to the client.
+	ResponseBuffering *bool
+	// ResponseBufferSize is the size of the buffer reading the first part of the response
+	// of the backend, which holds its headers.
+	ResponseBufferSize *resource.Quantity
+	// HTTPVersion is the HTTP/1 version of the requests to the backend, "1.0" or "1.1".
+	HTTPVersion string
+}
+
+// CustomErrorResponses represents provider-neutral intent to replace the responses of
+// the backends with the given status codes by the responses of an error backend.
+type CustomErrorResponses struct {
+	Metadata    ExtensionFeatureMetadata
+	StatusCodes []int
+	// Backend is the Service serving the error responses.
+	Backend gatewayv1.BackendObjectReference
+}
+
+// LocationRewrite represents intent to rewrite the Location header of the responses
+// of the backends which starts with From, replacing that prefix with To.
+type LocationRewrite struct {
+	Metadata ExtensionFeatureMetadata
+	From     string
+	To       string
 }
 
 // IPRangeControl represents provider-neutral IP range control intent.
@@ -175,6 +401,176 @@ type IPRangeControl struct {
 	DenyList  []string
 }
 
+// ExternalAuth represents provider-neutral external authentication intent:
+// requests are first sent to an authentication service, and only forwarded
+// to the backend if that service accepts them.
+type ExternalAuth struct {
+	Metadata ExtensionFeatureMetadata
+	// URL of the authentication service, as configured in the source.
+	URL string
+	// Service is the in-cluster Service serving URL. It is nil when URL
+	// doesn't point to a Service of the cluster.
+	Service *gatewayv1.BackendObjectReference
+	// Path is the path of URL.
+	Path string
+	// Method of the authentication request. Empty keeps the method of the
+	// original request.
+	Method string
+	// RequestHeaders lists the client request headers sent to the
+	// authentication service. Nil sends all of them.
+	RequestHeaders []string
+	// ResponseHeaders lists the headers of the authentication response which
+	// are copied to the request sent to the backend.
+	ResponseHeaders []string
+	// SigninURL is where clients rejected by the authentication service are
+	// redirected to.
+	SigninURL string
+	// CacheKey and CacheDuration configure the caching of authentication
+	// responses.
+	CacheKey      string
+	CacheDuration string
+}
+
+// BasicAuth represents provider-neutral HTTP basic authentication intent.
+type BasicAuth struct {
+	Metadata ExtensionFeatureMetadata
+	// Secret references the Secret holding the credentials.
+	Secret types.NamespacedName
+	// SecretKey is the key of Secret holding the credentials in htpasswd
+	// format. It is empty when Secret holds one key per user instead.
+	SecretKey string
+	// Realm is the realm sent in the authentication challenge.
+	Realm string
+	// Users holds the "user:hashed-password" htpasswd entries read from
+	// Secret. It is nil when Secret is not available.
+	Users []string
+}
+
+// RateLimitClientKey identifies the clients a RateLimit applies to.
+type RateLimitClientKey string
+
+const (
+	// RateLimitClientIP limits each client IP address separately.
+	RateLimitClientIP RateLimitClientKey = "ClientIP"
+)
+
+// RateLimit represents provider-neutral rate limiting intent. Zero values
+// mean no limit.
+type RateLimit struct {
+	Metadata ExtensionFeatureMetadata
+	// RequestsPerSecond and RequestsPerMinute limit the requests of each client.
+	RequestsPerSecond int32
+	RequestsPerMinute int32
+	// BurstMultiplier sets the number of requests allowed in bursts, as a
+	// multiple of the limits. Zero allows no bursts above the limits.
+	BurstMultiplier int32
+	// Connections limits the concurrent connections of each client.
+	Connections int32
+	// ClientKey identifies the clients the limits apply to.
+	ClientKey RateLimitClientKey
+	// AllowList holds the CIDRs of the clients which are not limited.
+	AllowList []string
+}
+
+// RetryCondition identifies failures of backend requests which are retried.
+type RetryCondition string
+
+const (
+	// RetryOnConnectFailure retries requests which couldn't be sent to the
+	// backend, or whose connection failed before a response was received.
+	RetryOnConnectFailure RetryCondition = "ConnectFailure"
+	// RetryOnTimeout retries requests the backend didn't answer in time.
+	RetryOnTimeout RetryCondition = "Timeout"
+)
+
+// Retry represents provider-neutral retry intent.
+type Retry struct {
+	Metadata ExtensionFeatureMetadata
+	// Attempts is the maximum number of retries, not counting the initial
+	// request. Nil keeps the default of the implementation.
+	Attempts *int32
+	// Conditions lists the failures which are retried, besides StatusCodes.
+	Conditions []RetryCondition
+	// StatusCodes lists the backend response status codes which are retried.
+	StatusCodes []int32
+	// PerTryTimeout limits the duration of each attempt.
+	PerTryTimeout *gatewayv1.Duration
+	// Timeout limits the total time during which a request is retried.
+	Timeout *gatewayv1.Duration
+}
+
+// ClientCertificateValidation represents provider-neutral intent to request
+// client certificates on a listener, and to validate them against a CA bundle.
+type ClientCertificateValidation struct {
+	Metadata ExtensionFeatureMetadata
+	// CACertificateRef references the ConfigMap holding the CA bundle in its
+	// "ca.crt" key. The ConfigMap lives in the namespace of the Gateway.
+	CACertificateRef types.NamespacedName
+	// CACertificate is the ConfigMap referenced by CACertificateRef, to emit
+	// along with the resources validating the client certificates. It is nil
+	// when the ConfigMap must be created manually.
+	CACertificate *corev1.ConfigMap
+	// Optional accepts clients that don't present a valid certificate.
+	Optional bool
+	// VerifyDepth is the maximum depth of the client certificate chains.
+	// Zero keeps the default of the implementation.
+	VerifyDepth int32
+	// PassCertificateToUpstream forwards the client certificate to the
+	// backends in a request header.
+	PassCertificateToUpstream bool
+	// Hostnames lists the hosts of the listener which asked for the
+	// validation.
+	Hostnames []string
+}
+
+// ListenerTLSOptions represents provider-neutral intent to configure the TLS
+// connections of an HTTPS listener and the security headers of its responses.
+type ListenerTLSOptions struct {
+	Metadata ExtensionFeatureMetadata
+	// MinVersion and MaxVersion are the TLS versions accepted from the clients,
+	// such as "1.2", unset for the defaults of the implementation.
+	MinVersion string
+	MaxVersion string
+	// CipherSuites are the OpenSSL names of the TLS 1.2 and earlier cipher suites,
+	// such as ECDHE-RSA-AES128-GCM-SHA256, in order of preference.
+	CipherSuites []string
+	// ALPNProtocols are the application protocols negotiated with the clients,
+	// such as h2 and http/1.1, in order of preference.
+	ALPNProtocols []string
+	// HSTS is set to add a Strict-Transport-Security header to the responses.
+	HSTS *HSTS
+}
+
+// HasTLSParameters returns true when the TLS handshake is configured, as opposed to
+// the headers of the responses.
+func (o *ListenerTLSOptions) HasTLSParameters() bool {
+	return o.MinVersion != "" || o.MaxVersion != "" || len(o.CipherSuites) > 0 || len(o.ALPNProtocols) > 0
+}
+
+// HSTSHeader is the response header of HTTP Strict Transport Security.
+const HSTSHeader = "Strict-Transport-Security"
+
+// HSTS represents provider-neutral intent to add a Strict-Transport-Security
+// header to the responses of an HTTPS listener.
+type HSTS struct {
+	// MaxAge is the time, in seconds, clients only access the host over HTTPS.
+	MaxAge            int64
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+// HeaderValue returns the value of the Strict-Transport-Security header.
+func (h *HSTS) HeaderValue() string {
+	value := fmt.Sprintf("max-age=%d", h.MaxAge)
+	if h.IncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if h.Preload {
+		value += "; preload"
+	}
+	return value
+}
+
 type CORSConfig struct {
 	gatewayv1.HTTPCORSFilter
 }
@@ -206,3 +602,13 @@ type BackendTLSPolicyContext struct {
 type ReferenceGrantContext struct {
 	gatewayv1beta1.ReferenceGrant
 }
+
+type ConfigMapContext struct {
+	corev1.ConfigMap
+}
+
+// ServicePatchContext is a Service with only the fields to set on the existing
+// Service, which are applied as a strategic merge patch.
+type ServicePatchContext struct {
+	corev1.Service
+}
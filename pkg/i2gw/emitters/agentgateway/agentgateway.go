@@ -56,7 +56,8 @@ func (e *Emitter) Emit(ir emitterir.EmitterIR) (gr i2gw.GatewayResources, errs f
 	}
 
 	for nn, rc := range ir.HTTPRoutes {
-		applyBodySize(&rc, e.notify)
+		applyBuffering(&rc, e.notify)
+		applyRegexPathRewrite(&rc, e.notify)
 		ir.HTTPRoutes[nn] = rc
 	}
 
@@ -64,16 +65,33 @@ func (e *Emitter) Emit(ir emitterir.EmitterIR) (gr i2gw.GatewayResources, errs f
 	return gr, nil
 }
 
-func applyBodySize(
+func applyBuffering(
 	rc *emitterir.HTTPRouteContext,
 	notify notifications.NotifyFunc,
 ) {
-	for idx := range rc.BodySizeByRuleIdx {
+	for idx := range rc.BufferingByRuleIdx {
 		notify(
 			notifications.WarningNotification,
-			fmt.Sprintf("Body size limit is not supported for HTTPRoute targets in AgentgatewayPolicy; ignoring%s", formatRuleInfo(rc, idx)),
+			fmt.Sprintf("Body size and buffering settings are not supported for HTTPRoute targets in AgentgatewayPolicy; ignoring%s", formatRuleInfo(rc, idx)),
 			&rc.HTTPRoute,
 		)
 	}
-	rc.BodySizeByRuleIdx = nil
+	rc.BufferingByRuleIdx = nil
+}
+
+func applyRegexPathRewrite(
+	rc *emitterir.HTTPRouteContext,
+	notify notifications.NotifyFunc,
+) {
+	for idx, rewrite := range rc.PathRewriteByRuleIdx {
+		if rewrite == nil || rewrite.Regex == nil {
+			continue
+		}
+		notify(
+			notifications.WarningNotification,
+			fmt.Sprintf("Regex path rewrite %q to %q is not supported by AgentgatewayPolicy; ignoring%s", rewrite.Regex.Pattern, rewrite.Regex.Substitution, formatRuleInfo(rc, idx)),
+			&rc.HTTPRoute,
+		)
+		rc.PathRewriteByRuleIdx[idx] = nil
+	}
 }
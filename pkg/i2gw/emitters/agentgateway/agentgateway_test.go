@@ -60,7 +60,7 @@ func TestEmit_Gateway(t *testing.T) {
 	}
 }
 
-func TestEmit_BodySize(t *testing.T) {
+func TestEmit_Buffering(t *testing.T) {
 	nn := types.NamespacedName{Namespace: "default", Name: "test-http-route"}
 
 	testHTTPRoute := gatewayv1.HTTPRoute{
@@ -105,7 +105,7 @@ func TestEmit_BodySize(t *testing.T) {
 				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
 					nn: {
 						HTTPRoute: testHTTPRoute,
-						BodySizeByRuleIdx: map[int]*emitterir.BodySize{
+						BufferingByRuleIdx: map[int]*emitterir.Buffering{
 							0: {BufferSize: common.PtrTo(resource.MustParse("1Mi"))},
 						},
 					},
@@ -119,7 +119,7 @@ func TestEmit_BodySize(t *testing.T) {
 				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
 					nn: {
 						HTTPRoute: testHTTPRoute,
-						BodySizeByRuleIdx: map[int]*emitterir.BodySize{
+						BufferingByRuleIdx: map[int]*emitterir.Buffering{
 							0:                 {BufferSize: common.PtrTo(resource.MustParse("1Mi"))},
 							routeRuleAllIndex: {BufferSize: common.PtrTo(resource.MustParse("4Mi"))},
 						},
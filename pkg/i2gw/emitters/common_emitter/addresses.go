@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_emitter
+
+import (
+	"fmt"
+	"slices"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayAddressMode controls what happens to the addresses used by the
+// source load balancers.
+type GatewayAddressMode string
+
+const (
+	// GatewayAddressModePreserve sets the reusable addresses on Gateway spec.addresses.
+	GatewayAddressModePreserve GatewayAddressMode = "preserve"
+	// GatewayAddressModeOmit drops the addresses.
+	GatewayAddressModeOmit GatewayAddressMode = "omit"
+	// GatewayAddressModeComment prints the addresses as comments next to the Gateway.
+	GatewayAddressModeComment GatewayAddressMode = "comment"
+)
+
+// GatewayAddressModes lists the supported GatewayAddressMode values.
+var GatewayAddressModes = []GatewayAddressMode{
+	GatewayAddressModePreserve,
+	GatewayAddressModeOmit,
+	GatewayAddressModeComment,
+}
+
+const commonEmitterName = "common-emitter"
+
+// maxGatewayAddresses is the maximum number of addresses a Gateway can have.
+const maxGatewayAddresses = 16
+
+func (e *Emitter) applyGatewayAddresses(ir *emitterir.EmitterIR) {
+	mode := GatewayAddressModeComment
+	if e.conf != nil && e.conf.GatewayAddressMode != "" {
+		mode = e.conf.GatewayAddressMode
+	}
+
+	for key, gatewayContext := range ir.Gateways {
+		if len(gatewayContext.Addresses) == 0 {
+			continue
+		}
+
+		switch mode {
+		case GatewayAddressModePreserve:
+			for _, address := range gatewayContext.Addresses {
+				if address.NotReusableReason != "" {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("%s address %q from %s can't be preserved: %s", addressType(address), address.Value, address.Metadata.Source(), address.NotReusableReason),
+						&gatewayContext.Gateway)
+					continue
+				}
+				if containsAddress(gatewayContext.Spec.Addresses, address.GatewaySpecAddress) {
+					continue
+				}
+				if len(gatewayContext.Spec.Addresses) == maxGatewayAddresses {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("%s address %q from %s can't be preserved: a Gateway can't have more than %d addresses", addressType(address), address.Value, address.Metadata.Source(), maxGatewayAddresses),
+						&gatewayContext.Gateway)
+					continue
+				}
+				gatewayContext.Spec.Addresses = append(gatewayContext.Spec.Addresses, address.GatewaySpecAddress)
+			}
+		case GatewayAddressModeComment:
+			gatewayContext.Comments = append(gatewayContext.Comments, "Addresses used by the source load balancers. To reuse them, add them to the Gateway:")
+			gatewayContext.Comments = append(gatewayContext.Comments, "spec:", "  addresses:")
+			for _, address := range gatewayContext.Addresses {
+				if address.NotReusableReason != "" {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("%s address %q from %s can't be reused: %s", addressType(address), address.Value, address.Metadata.Source(), address.NotReusableReason),
+						&gatewayContext.Gateway)
+					gatewayContext.Comments = append(gatewayContext.Comments, fmt.Sprintf("  # not reusable: %s", address.NotReusableReason))
+				}
+				gatewayContext.Comments = append(gatewayContext.Comments,
+					fmt.Sprintf("  - type: %s", addressType(address)),
+					fmt.Sprintf("    value: %s", address.Value))
+			}
+		}
+
+		gatewayContext.Addresses = nil
+		ir.Gateways[key] = gatewayContext
+	}
+}
+
+func (e *Emitter) notify(mType notifications.MessageType, message string, callingObjects ...client.Object) {
+	if e.conf == nil {
+		return
+	}
+	e.conf.Report.Notifier(commonEmitterName)(mType, message, callingObjects...)
+}
+
+func addressType(address emitterir.GatewayAddress) gatewayv1.AddressType {
+	return ptr.Deref(address.Type, gatewayv1.IPAddressType)
+}
+
+func containsAddress(addresses []gatewayv1.GatewaySpecAddress, address gatewayv1.GatewaySpecAddress) bool {
+	return slices.ContainsFunc(addresses, func(a gatewayv1.GatewaySpecAddress) bool {
+		return ptr.Deref(a.Type, gatewayv1.IPAddressType) == ptr.Deref(address.Type, gatewayv1.IPAddressType) && a.Value == address.Value
+	})
+}
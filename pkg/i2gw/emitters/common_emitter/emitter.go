@@ -22,12 +22,19 @@ import (
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 type EmitterConf struct {
 	AllowExperimentalGatewayAPI bool
 	Report                      *notifications.Report
+	// GatewayAddressMode controls how the addresses of the source load
+	// balancers are carried over to the Gateways. Defaults to comment.
+	GatewayAddressMode GatewayAddressMode
+	// KeepServiceSessionAffinity leaves the session affinity of Services to
+	// the emitter instead of converting it to sessionPersistence.
+	KeepServiceSessionAffinity bool
 }
 
 const tcpTimeoutMultiplier = 10
@@ -46,25 +53,22 @@ func applyPathRewrites(ir *emitterir.EmitterIR) {
 			if rewrite == nil || rewrite.RegexCaptureGroupReferences {
 				continue
 			}
-			fullPath := rewrite.ReplaceFullPath
+			pathModifier := &gatewayv1.HTTPPathModifier{
+				Type:            gatewayv1.FullPathHTTPPathModifier,
+				ReplaceFullPath: ptr.To(rewrite.ReplaceFullPath),
+			}
+			if rewrite.ReplacePrefixMatch != "" {
+				pathModifier = &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: ptr.To(rewrite.ReplacePrefixMatch),
+				}
+			}
 			routeCtx.Spec.Rules[ruleIdx].Filters = append(routeCtx.Spec.Rules[ruleIdx].Filters, gatewayv1.HTTPRouteFilter{
-				Type: gatewayv1.HTTPRouteFilterURLRewrite,
-				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
-					Path: &gatewayv1.HTTPPathModifier{
-						Type:            gatewayv1.FullPathHTTPPathModifier,
-						ReplaceFullPath: &fullPath,
-					},
-				},
+				Type:       gatewayv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayv1.HTTPURLRewriteFilter{Path: pathModifier},
 			})
-			if len(rewrite.Headers) > 0 {
-				headerModifier := &gatewayv1.HTTPHeaderFilter{}
-				for headerName, headerValue := range rewrite.Headers {
-					headerModifier.Set = append(headerModifier.Set, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(headerName), Value: headerValue})
-				}
-				routeCtx.Spec.Rules[ruleIdx].Filters = append(routeCtx.Spec.Rules[ruleIdx].Filters, gatewayv1.HTTPRouteFilter{
-					Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
-					RequestHeaderModifier: headerModifier,
-				})
+			if headersFilter := rewrite.HeadersFilter(); headersFilter != nil {
+				routeCtx.Spec.Rules[ruleIdx].Filters = append(routeCtx.Spec.Rules[ruleIdx].Filters, *headersFilter)
 			}
 			routeCtx.PathRewriteByRuleIdx[ruleIdx] = nil
 		}
@@ -96,6 +100,10 @@ func (e *Emitter) Emit(ir emitterir.EmitterIR) (emitterir.EmitterIR, field.Error
 	errs := applyTCPTimeouts(&ir)
 	applyPathRewrites(&ir)
 	e.applyCorsPolicies(&ir)
+	e.applyRetries(&ir)
+	e.applySessionPersistence(&ir)
+	e.applyGatewayAddresses(&ir)
+	e.applyFrontendTLSValidation(&ir)
 	return ir, errs
 }
 
@@ -17,11 +17,16 @@ limitations under the License.
 package common_emitter
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -146,6 +151,66 @@ func TestEmitter_Emit_appliesPathRewriteReplaceFullPath(t *testing.T) {
 	}
 }
 
+func TestEmitter_Emit_appliesPathRewriteReplacePrefixMatch(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "route"}
+
+	ir := emitterir.EmitterIR{
+		HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+			key: {
+				HTTPRoute: gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+					Spec: gatewayv1.HTTPRouteSpec{
+						Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+					},
+				},
+				PathRewriteByRuleIdx: map[int]*emitterir.PathRewrite{
+					0: {ReplacePrefixMatch: "/", Headers: map[string]string{"X-Forwarded-Prefix": "/foo"}},
+					1: {
+						ReplaceFullPath:             "/$1",
+						RegexCaptureGroupReferences: true,
+						Regex:                       &emitterir.RegexPathRewrite{Pattern: "(?i)^/foo/(.*).*", Substitution: "/\\1"},
+					},
+				},
+			},
+		},
+	}
+
+	e := NewEmitter(nil)
+	gotIR, errs := e.Emit(ir)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got: %v", errs)
+	}
+
+	want := []gatewayv1.HTTPRouteFilter{
+		{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: ptr.To("/"),
+				},
+			},
+		},
+		{
+			Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set: []gatewayv1.HTTPHeader{{Name: "X-Forwarded-Prefix", Value: "/foo"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, gotIR.HTTPRoutes[key].Spec.Rules[0].Filters); diff != "" {
+		t.Errorf("unexpected filters (-want +got):\n%s", diff)
+	}
+
+	// Regex rewrites are left to implementation-specific emitters.
+	if got := gotIR.HTTPRoutes[key].Spec.Rules[1].Filters; len(got) != 0 {
+		t.Errorf("expected no filters for the regex rewrite, got: %#v", got)
+	}
+	if gotIR.HTTPRoutes[key].PathRewriteByRuleIdx[1] == nil {
+		t.Errorf("expected the regex rewrite to remain unprocessed")
+	}
+}
+
 func TestEmitCORSFiltering(t *testing.T) {
 	testCases := []struct {
 		name                 string
@@ -208,3 +273,333 @@ func TestEmitCORSFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestEmitGatewayAddresses(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "gw"}
+	ipAddress := emitterir.GatewayAddress{
+		Metadata:           emitterir.NewExtensionFeatureMetadata("Ingress default/a", nil, ""),
+		GatewaySpecAddress: gatewayv1.GatewaySpecAddress{Type: ptr.To(gatewayv1.IPAddressType), Value: "10.0.0.1"},
+	}
+	hostnameAddress := emitterir.GatewayAddress{
+		Metadata:           emitterir.NewExtensionFeatureMetadata("Ingress default/a", nil, ""),
+		GatewaySpecAddress: gatewayv1.GatewaySpecAddress{Type: ptr.To(gatewayv1.HostnameAddressType), Value: "lb.example.com"},
+		NotReusableReason:  "assigned by the load balancer",
+	}
+
+	testCases := []struct {
+		name              string
+		mode              GatewayAddressMode
+		expectedAddresses []gatewayv1.GatewaySpecAddress
+		expectedComments  []string
+		expectedWarnings  int
+	}{
+		{
+			name: "omit drops addresses",
+			mode: GatewayAddressModeOmit,
+		},
+		{
+			name:              "preserve sets reusable addresses",
+			mode:              GatewayAddressModePreserve,
+			expectedAddresses: []gatewayv1.GatewaySpecAddress{ipAddress.GatewaySpecAddress},
+			expectedWarnings:  1,
+		},
+		{
+			name: "comment by default lists all addresses",
+			expectedComments: []string{
+				"Addresses used by the source load balancers. To reuse them, add them to the Gateway:",
+				"spec:",
+				"  addresses:",
+				"  - type: IPAddress",
+				"    value: 10.0.0.1",
+				"  # not reusable: assigned by the load balancer",
+				"  - type: Hostname",
+				"    value: lb.example.com",
+			},
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := notifications.NewReport(true)
+			e := NewEmitter(&EmitterConf{Report: report, GatewayAddressMode: tc.mode})
+
+			ir := emitterir.EmitterIR{
+				Gateways: map[types.NamespacedName]emitterir.GatewayContext{
+					key: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+						},
+						Addresses: []emitterir.GatewayAddress{ipAddress, hostnameAddress},
+					},
+				},
+			}
+
+			result, _ := e.Emit(ir)
+			gatewayContext := result.Gateways[key]
+			if diff := cmp.Diff(tc.expectedAddresses, gatewayContext.Spec.Addresses); diff != "" {
+				t.Errorf("Unexpected addresses (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedComments, gatewayContext.Comments); diff != "" {
+				t.Errorf("Unexpected comments (-want +got):\n%s", diff)
+			}
+			if gatewayContext.Addresses != nil {
+				t.Errorf("Expected the address intent to be consumed, got %v", gatewayContext.Addresses)
+			}
+			if warnings := strings.Count(report.Render(), "can't be"); warnings != tc.expectedWarnings {
+				t.Errorf("Expected %d warnings, got %d", tc.expectedWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestEmitRetries(t *testing.T) {
+	retry := &emitterir.Retry{
+		Attempts:      ptr.To[int32](2),
+		Conditions:    []emitterir.RetryCondition{emitterir.RetryOnConnectFailure},
+		StatusCodes:   []int32{502, 503},
+		PerTryTimeout: ptr.To(gatewayv1.Duration("5s")),
+	}
+
+	// The backendRequest timeout of the rule is kept, rather than replaced by
+	// the per-try timeout.
+	routeTimeouts := &gatewayv1.HTTPRouteTimeouts{BackendRequest: ptr.To(gatewayv1.Duration("10s"))}
+
+	testCases := []struct {
+		name              string
+		allowExperimental bool
+		expectedRetry     *gatewayv1.HTTPRouteRetry
+		expectedTimeouts  *gatewayv1.HTTPRouteTimeouts
+		expectedIntent    bool
+	}{
+		{
+			name:              "experimental allowed -> retry set",
+			allowExperimental: true,
+			expectedRetry: &gatewayv1.HTTPRouteRetry{
+				Attempts: ptr.To(2),
+				Codes:    []gatewayv1.HTTPRouteRetryStatusCode{502, 503},
+			},
+			expectedTimeouts: routeTimeouts,
+		},
+		{
+			name:              "experimental denied -> intent left to the custom emitters",
+			allowExperimental: false,
+			expectedTimeouts:  routeTimeouts,
+			expectedIntent:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEmitter(&EmitterConf{
+				AllowExperimentalGatewayAPI: tc.allowExperimental,
+				Report:                      notifications.NewReport(true),
+			})
+
+			key := types.NamespacedName{Name: "test"}
+			ir := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{Timeouts: routeTimeouts.DeepCopy()}},
+							},
+						},
+						RetryByRuleIdx: map[int]*emitterir.Retry{0: retry},
+					},
+				},
+			}
+
+			result, _ := e.Emit(ir)
+			routeCtx := result.HTTPRoutes[key]
+			if diff := cmp.Diff(tc.expectedRetry, routeCtx.Spec.Rules[0].Retry); diff != "" {
+				t.Errorf("Unexpected retry (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedTimeouts, routeCtx.Spec.Rules[0].Timeouts); diff != "" {
+				t.Errorf("Unexpected timeouts (-want +got):\n%s", diff)
+			}
+			if hasIntent := routeCtx.RetryByRuleIdx[0] != nil; hasIntent != tc.expectedIntent {
+				t.Errorf("Expected retry intent left: %v, got %v", tc.expectedIntent, hasIntent)
+			}
+		})
+	}
+}
+
+func TestEmitSessionPersistence(t *testing.T) {
+	svcKey := types.NamespacedName{Namespace: "default", Name: "app"}
+
+	testCases := []struct {
+		name                       string
+		allowExperimental          bool
+		keepSessionAffinity        bool
+		sessionAffinity            *emitterir.SessionAffinity
+		expectedSessionPersistence *gatewayv1.SessionPersistence
+		expectedIntent             bool
+	}{
+		{
+			name:              "permanent cookie",
+			allowExperimental: true,
+			sessionAffinity: &emitterir.SessionAffinity{
+				Type:         "Cookie",
+				CookieName:   "route",
+				CookieTTLSec: ptr.To[int64](3600),
+			},
+			expectedSessionPersistence: &gatewayv1.SessionPersistence{
+				SessionName:     ptr.To("route"),
+				AbsoluteTimeout: ptr.To(gatewayv1.Duration("1h0m0s")),
+				Type:            ptr.To(gatewayv1.CookieBasedSessionPersistence),
+				CookieConfig: &gatewayv1.CookieConfig{
+					LifetimeType: ptr.To(gatewayv1.PermanentCookieLifetimeType),
+				},
+			},
+		},
+		{
+			name:              "session cookie",
+			allowExperimental: true,
+			sessionAffinity: &emitterir.SessionAffinity{
+				Type:       "Cookie",
+				CookieName: "INGRESSCOOKIE",
+			},
+			expectedSessionPersistence: &gatewayv1.SessionPersistence{
+				SessionName: ptr.To("INGRESSCOOKIE"),
+				Type:        ptr.To(gatewayv1.CookieBasedSessionPersistence),
+				CookieConfig: &gatewayv1.CookieConfig{
+					LifetimeType: ptr.To(gatewayv1.SessionCookieLifetimeType),
+				},
+			},
+		},
+		{
+			name:              "experimental denied -> intent left to the custom emitters",
+			allowExperimental: false,
+			sessionAffinity: &emitterir.SessionAffinity{
+				Type:       "Cookie",
+				CookieName: "INGRESSCOOKIE",
+			},
+			expectedIntent: true,
+		},
+		{
+			name:                "session affinity kept -> intent left to the custom emitters",
+			allowExperimental:   true,
+			keepSessionAffinity: true,
+			sessionAffinity: &emitterir.SessionAffinity{
+				Type:       "Cookie",
+				CookieName: "INGRESSCOOKIE",
+			},
+			expectedIntent: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEmitter(&EmitterConf{
+				AllowExperimentalGatewayAPI: tc.allowExperimental,
+				Report:                      notifications.NewReport(true),
+				KeepServiceSessionAffinity:  tc.keepSessionAffinity,
+			})
+
+			key := types.NamespacedName{Namespace: "default", Name: "test"}
+			ir := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{
+									BackendRefs: []gatewayv1.HTTPBackendRef{{
+										BackendRef: gatewayv1.BackendRef{
+											BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(svcKey.Name)},
+										},
+									}},
+								}},
+							},
+						},
+					},
+				},
+				Services: map[types.NamespacedName]emitterir.ServiceContext{
+					svcKey: {SessionAffinity: tc.sessionAffinity},
+				},
+			}
+
+			result, _ := e.Emit(ir)
+			if diff := cmp.Diff(tc.expectedSessionPersistence, result.HTTPRoutes[key].Spec.Rules[0].SessionPersistence); diff != "" {
+				t.Errorf("Unexpected session persistence (-want +got):\n%s", diff)
+			}
+			if hasIntent := result.Services[svcKey].SessionAffinity != nil; hasIntent != tc.expectedIntent {
+				t.Errorf("Expected session affinity intent left: %v, got %v", tc.expectedIntent, hasIntent)
+			}
+		})
+	}
+}
+
+func TestEmitFrontendTLSValidation(t *testing.T) {
+	gwKey := types.NamespacedName{Namespace: "default", Name: "gw"}
+	caKey := types.NamespacedName{Namespace: "default", Name: "ca-ca"}
+
+	testCases := []struct {
+		name              string
+		allowExperimental bool
+		expectedPorts     int
+		expectedConfigMap bool
+		expectedIntent    bool
+	}{
+		{
+			name:              "experimental allowed -> frontend validation and CA ConfigMap",
+			allowExperimental: true,
+			expectedPorts:     1,
+			expectedConfigMap: true,
+		},
+		{
+			name:              "experimental denied -> intent left to the custom emitters without the CA ConfigMap",
+			allowExperimental: false,
+			expectedIntent:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEmitter(&EmitterConf{
+				AllowExperimentalGatewayAPI: tc.allowExperimental,
+				Report:                      notifications.NewReport(true),
+			})
+
+			ir := emitterir.EmitterIR{
+				Gateways: map[types.NamespacedName]emitterir.GatewayContext{
+					gwKey: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Namespace: gwKey.Namespace, Name: gwKey.Name},
+							Spec: gatewayv1.GatewaySpec{
+								Listeners: []gatewayv1.Listener{{Name: "secure", Port: 443, Protocol: gatewayv1.HTTPSProtocolType}},
+							},
+						},
+						ClientCertificateValidationByListener: map[gatewayv1.SectionName]*emitterir.ClientCertificateValidation{
+							"secure": {
+								CACertificateRef: caKey,
+								CACertificate: &corev1.ConfigMap{
+									ObjectMeta: metav1.ObjectMeta{Namespace: caKey.Namespace, Name: caKey.Name},
+									Data:       map[string]string{"ca.crt": "-----BEGIN CERTIFICATE-----\n"},
+								},
+								Hostnames: []string{"secure.example.com"},
+							},
+						},
+					},
+				},
+			}
+
+			result, _ := e.Emit(ir)
+			gatewayContext := result.Gateways[gwKey]
+			ports := 0
+			if gatewayContext.Spec.TLS != nil && gatewayContext.Spec.TLS.Frontend != nil {
+				ports = len(gatewayContext.Spec.TLS.Frontend.PerPort)
+			}
+			if ports != tc.expectedPorts {
+				t.Errorf("Expected %d frontend TLS ports, got %d", tc.expectedPorts, ports)
+			}
+			if _, hasConfigMap := result.ConfigMaps[caKey]; hasConfigMap != tc.expectedConfigMap {
+				t.Errorf("Expected CA ConfigMap: %v, got %v", tc.expectedConfigMap, hasConfigMap)
+			}
+			if hasIntent := gatewayContext.ClientCertificateValidationByListener != nil; hasIntent != tc.expectedIntent {
+				t.Errorf("Expected client certificate validation intent left: %v, got %v", tc.expectedIntent, hasIntent)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_emitter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// applyFrontendTLSValidation converts client certificate validation intent to
+// the Gateway frontend TLS configuration. This is an experimental Gateway API
+// feature, so the intent is left to the custom emitters otherwise.
+//
+// Frontend TLS validation is configured per port rather than per listener, so
+// every HTTPS listener of the port ends up validating client certificates. The
+// CA bundle ConfigMaps are only emitted along with the ports referencing them.
+func (e *Emitter) applyFrontendTLSValidation(ir *emitterir.EmitterIR) {
+	if e.conf == nil || !e.conf.AllowExperimentalGatewayAPI {
+		return
+	}
+
+	for key, gatewayContext := range ir.Gateways {
+		if len(gatewayContext.ClientCertificateValidationByListener) == 0 {
+			continue
+		}
+
+		var ports []gatewayv1.PortNumber
+		validationByPort := map[gatewayv1.PortNumber]*emitterir.ClientCertificateValidation{}
+		for _, listener := range gatewayContext.Spec.Listeners {
+			validation := gatewayContext.ClientCertificateValidationByListener[listener.Name]
+			if validation == nil {
+				continue
+			}
+			current, found := validationByPort[listener.Port]
+			if !found {
+				validationByPort[listener.Port] = validation
+				ports = append(ports, listener.Port)
+				continue
+			}
+			if current.CACertificateRef != validation.CACertificateRef || current.Optional != validation.Optional {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Client certificate validation of %s from %s conflicts with the one of %s on port %d, which is applied to both",
+						strings.Join(validation.Hostnames, ", "), validation.Metadata.Source(), strings.Join(current.Hostnames, ", "), listener.Port),
+					&gatewayContext.Gateway)
+			}
+		}
+
+		slices.Sort(ports)
+		for _, port := range ports {
+			validation := validationByPort[port]
+			mode := gatewayv1.AllowValidOnly
+			if validation.Optional {
+				mode = gatewayv1.AllowInsecureFallback
+			}
+			if gatewayContext.Spec.TLS == nil {
+				gatewayContext.Spec.TLS = &gatewayv1.GatewayTLSConfig{}
+			}
+			if gatewayContext.Spec.TLS.Frontend == nil {
+				gatewayContext.Spec.TLS.Frontend = &gatewayv1.FrontendTLSConfig{}
+			}
+			gatewayContext.Spec.TLS.Frontend.PerPort = append(gatewayContext.Spec.TLS.Frontend.PerPort, gatewayv1.TLSPortConfig{
+				Port: port,
+				TLS: gatewayv1.TLSConfig{
+					Validation: &gatewayv1.FrontendTLSValidation{
+						CACertificateRefs: []gatewayv1.ObjectReference{{
+							Group: "",
+							Kind:  "ConfigMap",
+							Name:  gatewayv1.ObjectName(validation.CACertificateRef.Name),
+						}},
+						Mode: mode,
+					},
+				},
+			})
+			if validation.CACertificate != nil {
+				if ir.ConfigMaps == nil {
+					ir.ConfigMaps = make(map[types.NamespacedName]emitterir.ConfigMapContext)
+				}
+				ir.ConfigMaps[validation.CACertificateRef] = emitterir.ConfigMapContext{ConfigMap: *validation.CACertificate}
+			}
+
+			var otherHosts []string
+			for _, listener := range gatewayContext.Spec.Listeners {
+				if listener.Port != port || listener.Protocol != gatewayv1.HTTPSProtocolType || gatewayContext.ClientCertificateValidationByListener[listener.Name] != nil {
+					continue
+				}
+				if listener.Hostname != nil {
+					otherHosts = append(otherHosts, string(*listener.Hostname))
+				} else {
+					otherHosts = append(otherHosts, string(listener.Name))
+				}
+			}
+			if len(otherHosts) > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Client certificate validation of %s from %s applies to every HTTPS listener on port %d of the Gateway, including %s",
+						strings.Join(validation.Hostnames, ", "), validation.Metadata.Source(), port, strings.Join(otherHosts, ", ")),
+					&gatewayContext.Gateway)
+			}
+		}
+
+		for _, validation := range gatewayContext.ClientCertificateValidationByListener {
+			if validation == nil {
+				continue
+			}
+			if validation.VerifyDepth != 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The client certificate verification depth of %s from %s can't be configured, the depth is implementation specific", strings.Join(validation.Hostnames, ", "), validation.Metadata.Source()),
+					&gatewayContext.Gateway)
+			}
+			if validation.PassCertificateToUpstream {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The client certificate of %s from %s can't be passed to the backends", strings.Join(validation.Hostnames, ", "), validation.Metadata.Source()),
+					&gatewayContext.Gateway)
+			}
+		}
+
+		gatewayContext.ClientCertificateValidationByListener = nil
+		ir.Gateways[key] = gatewayContext
+	}
+}
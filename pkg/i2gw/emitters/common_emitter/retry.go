@@ -0,0 +1,74 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_emitter
+
+import (
+	"fmt"
+	"slices"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// applyRetries converts retry intent to the HTTPRoute rule retry field. This
+// is an experimental Gateway API feature, so the intent is left to the custom
+// emitters otherwise.
+func (e *Emitter) applyRetries(ir *emitterir.EmitterIR) {
+	if e.conf == nil || !e.conf.AllowExperimentalGatewayAPI {
+		return
+	}
+
+	for key, routeCtx := range ir.HTTPRoutes {
+		for ruleIdx, retry := range routeCtx.RetryByRuleIdx {
+			if retry == nil || ruleIdx < 0 || ruleIdx >= len(routeCtx.Spec.Rules) {
+				continue
+			}
+
+			rule := &routeCtx.Spec.Rules[ruleIdx]
+			rule.Retry = &gatewayv1.HTTPRouteRetry{}
+			if retry.Attempts != nil {
+				rule.Retry.Attempts = ptr.To(int(*retry.Attempts))
+			}
+			for _, code := range retry.StatusCodes {
+				rule.Retry.Codes = append(rule.Retry.Codes, gatewayv1.HTTPRouteRetryStatusCode(code))
+			}
+
+			// The backendRequest timeout of the rule is its own timeout, such as
+			// the one of proxy-read-timeout, so it isn't overwritten.
+			if retry.PerTryTimeout != nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The attempts of rule %d from %s can't be limited to %s each, the backendRequest timeout of the rule applies to each attempt", ruleIdx, retry.Metadata.Source(), *retry.PerTryTimeout),
+					&routeCtx.HTTPRoute)
+			}
+			if slices.Contains(retry.Conditions, emitterir.RetryOnConnectFailure) {
+				e.notify(notifications.InfoNotification,
+					fmt.Sprintf("Whether requests failing to reach the backend are retried is implementation specific for rule %d from %s", ruleIdx, retry.Metadata.Source()),
+					&routeCtx.HTTPRoute)
+			}
+			if retry.Timeout != nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The retries of rule %d from %s can't be limited to %s in total", ruleIdx, retry.Metadata.Source(), *retry.Timeout),
+					&routeCtx.HTTPRoute)
+			}
+		}
+
+		routeCtx.RetryByRuleIdx = nil
+		ir.HTTPRoutes[key] = routeCtx
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_emitter
+
+import (
+	"fmt"
+	"time"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// applySessionPersistence converts the cookie session affinity of Services to the
+// sessionPersistence of the HTTPRoute rules forwarding requests to them. This is an
+// experimental Gateway API feature, so the intent is left to the custom emitters
+// otherwise, or when they convert it to their own policies.
+func (e *Emitter) applySessionPersistence(ir *emitterir.EmitterIR) {
+	if e.conf == nil || !e.conf.AllowExperimentalGatewayAPI || e.conf.KeepServiceSessionAffinity {
+		return
+	}
+
+	consumed := map[types.NamespacedName]bool{}
+	for key, routeCtx := range ir.HTTPRoutes {
+		for ruleIdx := range routeCtx.Spec.Rules {
+			rule := &routeCtx.Spec.Rules[ruleIdx]
+			for _, backendRef := range rule.BackendRefs {
+				if backendRef.Group != nil && *backendRef.Group != "" || backendRef.Kind != nil && *backendRef.Kind != "Service" {
+					continue
+				}
+				svcKey := types.NamespacedName{Namespace: routeCtx.Namespace, Name: string(backendRef.Name)}
+				if backendRef.Namespace != nil {
+					svcKey.Namespace = string(*backendRef.Namespace)
+				}
+				sessionAffinity := ir.Services[svcKey].SessionAffinity
+				if sessionAffinity == nil || sessionAffinity.Type != "Cookie" {
+					continue
+				}
+				consumed[svcKey] = true
+
+				if rule.SessionPersistence != nil {
+					if ptr.Deref(rule.SessionPersistence.SessionName, "") != sessionAffinity.CookieName {
+						e.notify(notifications.WarningNotification,
+							fmt.Sprintf("Backends of rule %d use different session cookies, the session affinity from %s is ignored", ruleIdx, sessionAffinity.Metadata.Source()),
+							&routeCtx.HTTPRoute)
+					}
+					continue
+				}
+				rule.SessionPersistence = buildSessionPersistence(sessionAffinity)
+
+				if sessionAffinity.CookiePath != "" || sessionAffinity.CookieSameSite != "" || sessionAffinity.CookieSecure {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("The path, SameSite and Secure attributes of the session cookie from %s are implementation specific", sessionAffinity.Metadata.Source()),
+						&routeCtx.HTTPRoute)
+				}
+			}
+		}
+		ir.HTTPRoutes[key] = routeCtx
+	}
+
+	for svcKey := range consumed {
+		svc := ir.Services[svcKey]
+		svc.SessionAffinity = nil
+		ir.Services[svcKey] = svc
+	}
+}
+
+// buildSessionPersistence returns the cookie based session persistence of the
+// session affinity. Cookies without TTL expire with the browser session.
+func buildSessionPersistence(sessionAffinity *emitterir.SessionAffinity) *gatewayv1.SessionPersistence {
+	sessionPersistence := &gatewayv1.SessionPersistence{
+		SessionName: ptr.To(sessionAffinity.CookieName),
+		Type:        ptr.To(gatewayv1.CookieBasedSessionPersistence),
+		CookieConfig: &gatewayv1.CookieConfig{
+			LifetimeType: ptr.To(gatewayv1.SessionCookieLifetimeType),
+		},
+	}
+	if sessionAffinity.CookieTTLSec != nil && *sessionAffinity.CookieTTLSec > 0 {
+		ttl := time.Duration(*sessionAffinity.CookieTTLSec) * time.Second
+		sessionPersistence.AbsoluteTimeout = ptr.To(gatewayv1.Duration(ttl.String()))
+		sessionPersistence.CookieConfig.LifetimeType = ptr.To(gatewayv1.PermanentCookieLifetimeType)
+	}
+	return sessionPersistence
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"strings"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitBasicAuth converts the basic authentication intent into SecurityPolicy basicAuth.
+// Envoy Gateway reads the credentials from the ".htpasswd" key of a Secret in the
+// namespace of the SecurityPolicy, so the source Secret is converted to that format,
+// in a Secret named after the SecurityPolicy, as routes sharing the source Secret may
+// have different credentials.
+func (e *Emitter) EmitBasicAuth(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.BasicAuthByRuleIdx == nil {
+			continue
+		}
+
+		MergeBasicAuthIR(&ctx)
+
+		for idx, basicAuth := range ctx.BasicAuthByRuleIdx {
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			securityPolicy := e.getOrBuildSecurityPolicy(ctx, sectionName, idx)
+			secretKey := types.NamespacedName{
+				Namespace: ctx.Namespace,
+				Name:      fmt.Sprintf("%s-htpasswd", securityPolicy.Name),
+			}
+			securityPolicy.Spec.BasicAuth = &egapiv1a1.BasicAuth{
+				Users: gwapiv1.SecretObjectReference{Name: gwapiv1.ObjectName(secretKey.Name)},
+			}
+
+			if basicAuth.Users == nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Secret %s must be created with the credentials of Secret %s in htpasswd format in its %q key", secretKey, basicAuth.Secret, utils.HtpasswdSecretKey),
+					securityPolicy)
+			} else if _, exists := e.builderMap.Secrets[secretKey]; !exists {
+				e.builderMap.Secrets[secretKey] = utils.BuildHtpasswdSecret(secretKey.Namespace, secretKey.Name, basicAuth.Users)
+				if nonSHA := utils.NonSHAHtpasswdUsers(basicAuth.Users); len(nonSHA) > 0 {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("Envoy Gateway only supports SHA hashed passwords, the passwords of %s in Secret %s must be hashed again", strings.Join(nonSHA, ", "), secretKey),
+						securityPolicy)
+				}
+			}
+			if basicAuth.Realm != "" {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The basic authentication realm %q can't be set", basicAuth.Realm),
+					securityPolicy)
+			}
+		}
+
+		// mark Basic Auth IR as processed
+		ctx.BasicAuthByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
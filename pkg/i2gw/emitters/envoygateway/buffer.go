@@ -21,51 +21,79 @@ import (
 
 	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 )
 
+// EmitBuffer converts the buffering intent into BackendTrafficPolicies with a request
+// buffer limited to the body size.
 func (e *Emitter) EmitBuffer(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	// The rules of a source share its warnings, which are reported once.
+	reported := map[string]struct{}{}
+	warn := func(message string, httpRoute *gwapiv1.HTTPRoute) {
+		if _, found := reported[message]; !found {
+			reported[message] = struct{}{}
+			e.notify(notifications.WarningNotification, message, httpRoute)
+		}
+	}
+
 	for nn, ctx := range ir.HTTPRoutes {
-		if ctx.BodySizeByRuleIdx == nil {
+		if ctx.BufferingByRuleIdx == nil {
 			continue
 		}
 
-		MergeBodySizeIR(&ctx)
+		MergeBufferingIR(&ctx)
+
+		for idx, b := range ctx.BufferingByRuleIdx {
+			if b == nil {
+				continue
+			}
+			for _, message := range utils.UnsupportedBufferingMessages(b, "Envoy Gateway") {
+				warn(message, &ctx.HTTPRoute)
+			}
 
-		for idx, bs := range ctx.BodySizeByRuleIdx {
 			var sectionName *gwapiv1.SectionName
 			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
 				sectionName = ctx.Spec.Rules[idx].Name
 			}
 
-			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
-
 			// Prefer body max size if present, otherwise fall back to body buffer size.
 			var bufferVal *resource.Quantity
 
-			if bs.MaxSize != nil {
-				bufferVal = bs.MaxSize
-				if bs.BufferSize != nil {
+			if b.MaxSize != nil {
+				bufferVal = b.MaxSize
+				if b.BufferSize != nil {
 					e.notify(
 						notifications.WarningNotification,
-						fmt.Sprintf("Body max size (%s) takes precedence; buffer size (%s) will be ignored", bs.MaxSize.String(), bs.BufferSize.String()),
+						fmt.Sprintf("Body max size (%s) takes precedence; buffer size (%s) will be ignored", b.MaxSize.String(), b.BufferSize.String()),
 						&ctx.HTTPRoute,
 					)
 				}
-			} else if bs.BufferSize != nil {
-				bufferVal = bs.BufferSize
+			} else if b.BufferSize != nil {
+				bufferVal = b.BufferSize
+			}
+			// Envoy Gateway only limits the body size of buffered requests.
+			if bufferVal != nil && !ptr.Deref(b.RequestBuffering, true) {
+				warn(fmt.Sprintf("Requests of %s are not buffered, so their body size of %s is not limited", b.Metadata.Source(), bufferVal.String()), &ctx.HTTPRoute)
+				bufferVal = nil
 			}
+
+			if bufferVal == nil {
+				continue
+			}
+			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
 			backendTrafficPolicy.Spec.RequestBuffer = &egapiv1a1.RequestBuffer{
 				Limit: *bufferVal,
 			}
 		}
 
 		// mark Buffer IR as processed
-		ctx.BodySizeByRuleIdx = nil
+		ctx.BufferingByRuleIdx = nil
 		ir.HTTPRoutes[nn] = ctx
 	}
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -30,12 +31,22 @@ import (
 type BuilderMap struct {
 	BackendTrafficPolicies map[types.NamespacedName]*egapiv1a1.BackendTrafficPolicy
 	SecurityPolicies       map[types.NamespacedName]*egapiv1a1.SecurityPolicy
+	ClientTrafficPolicies  map[types.NamespacedName]*egapiv1a1.ClientTrafficPolicy
+	EnvoyExtensionPolicies map[types.NamespacedName]*egapiv1a1.EnvoyExtensionPolicy
+	HTTPRouteFilters       map[types.NamespacedName]*egapiv1a1.HTTPRouteFilter
+	Secrets                map[types.NamespacedName]*corev1.Secret
+	ConfigMaps             map[types.NamespacedName]*corev1.ConfigMap
 }
 
 func NewBuilderMap() *BuilderMap {
 	return &BuilderMap{
 		BackendTrafficPolicies: make(map[types.NamespacedName]*egapiv1a1.BackendTrafficPolicy),
 		SecurityPolicies:       make(map[types.NamespacedName]*egapiv1a1.SecurityPolicy),
+		ClientTrafficPolicies:  make(map[types.NamespacedName]*egapiv1a1.ClientTrafficPolicy),
+		EnvoyExtensionPolicies: make(map[types.NamespacedName]*egapiv1a1.EnvoyExtensionPolicy),
+		HTTPRouteFilters:       make(map[types.NamespacedName]*egapiv1a1.HTTPRouteFilter),
+		Secrets:                make(map[types.NamespacedName]*corev1.Secret),
+		ConfigMaps:             make(map[types.NamespacedName]*corev1.ConfigMap),
 	}
 }
 
@@ -118,3 +129,79 @@ func (e *Emitter) getOrBuildSecurityPolicy(ctx emitterir.HTTPRouteContext, secti
 	e.builderMap.SecurityPolicies[key] = securityPolicy
 	return securityPolicy
 }
+
+func (e *Emitter) getOrBuildEnvoyExtensionPolicy(ctx emitterir.HTTPRouteContext, sectionName *gwapiv1.SectionName, ruleIdx int) *egapiv1a1.EnvoyExtensionPolicy {
+	name := fmt.Sprintf("%s-%d", ctx.Name, ruleIdx)
+	if ruleIdx == RouteRuleAllIndex {
+		name = ctx.Name
+	}
+	key := types.NamespacedName{
+		Name:      name,
+		Namespace: ctx.Namespace,
+	}
+	policy, exist := e.builderMap.EnvoyExtensionPolicies[key]
+	if exist {
+		return policy
+	}
+
+	envoyExtensionPolicy := &egapiv1a1.EnvoyExtensionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ctx.Namespace,
+		},
+		Spec: egapiv1a1.EnvoyExtensionPolicySpec{
+			PolicyTargetReferences: egapiv1a1.PolicyTargetReferences{
+				TargetRefs: []gwapiv1.LocalPolicyTargetReferenceWithSectionName{
+					{
+						LocalPolicyTargetReference: gwapiv1.LocalPolicyTargetReference{
+							Group: gwapiv1.Group(HTTPRouteGVK.Group),
+							Kind:  gwapiv1.Kind(HTTPRouteGVK.Kind),
+							Name:  gwapiv1.ObjectName(ctx.Name),
+						},
+						SectionName: sectionName,
+					},
+				},
+			},
+		},
+	}
+	envoyExtensionPolicy.SetGroupVersionKind(EnvoyExtensionPolicyGVK)
+
+	e.builderMap.EnvoyExtensionPolicies[key] = envoyExtensionPolicy
+	return envoyExtensionPolicy
+}
+
+func (e *Emitter) getOrBuildClientTrafficPolicy(gateway gwapiv1.Gateway, sectionName gwapiv1.SectionName) *egapiv1a1.ClientTrafficPolicy {
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s", gateway.Name, sectionName),
+		Namespace: gateway.Namespace,
+	}
+	policy, exist := e.builderMap.ClientTrafficPolicies[key]
+	if exist {
+		return policy
+	}
+
+	clientTrafficPolicy := &egapiv1a1.ClientTrafficPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+		Spec: egapiv1a1.ClientTrafficPolicySpec{
+			PolicyTargetReferences: egapiv1a1.PolicyTargetReferences{
+				TargetRefs: []gwapiv1.LocalPolicyTargetReferenceWithSectionName{
+					{
+						LocalPolicyTargetReference: gwapiv1.LocalPolicyTargetReference{
+							Group: gwapiv1.Group(GatewayGVK.Group),
+							Kind:  gwapiv1.Kind(GatewayGVK.Kind),
+							Name:  gwapiv1.ObjectName(gateway.Name),
+						},
+						SectionName: &sectionName,
+					},
+				},
+			},
+		},
+	}
+	clientTrafficPolicy.SetGroupVersionKind(ClientTrafficPolicyGVK)
+
+	e.builderMap.ClientTrafficPolicies[key] = clientTrafficPolicy
+	return clientTrafficPolicy
+}
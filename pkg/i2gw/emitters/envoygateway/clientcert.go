@@ -0,0 +1,83 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"strings"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitClientCertificateValidation converts the client certificate validation intent
+// into a ClientTrafficPolicy targeting the listener, along with the CA bundle ConfigMap
+// it references.
+func (e *Emitter) EmitClientCertificateValidation(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.Gateways {
+		if ctx.ClientCertificateValidationByListener == nil {
+			continue
+		}
+
+		for sectionName, validation := range ctx.ClientCertificateValidationByListener {
+			if validation == nil {
+				continue
+			}
+
+			clientTrafficPolicy := e.getOrBuildClientTrafficPolicy(ctx.Gateway, sectionName)
+			if clientTrafficPolicy.Spec.TLS == nil {
+				clientTrafficPolicy.Spec.TLS = &egapiv1a1.ClientTLSSettings{}
+			}
+			clientTrafficPolicy.Spec.TLS.ClientValidation = &egapiv1a1.ClientValidationContext{
+				Optional: validation.Optional,
+				CACertificateRefs: []gwapiv1.SecretObjectReference{{
+					Group: ptr.To(gwapiv1.Group("")),
+					Kind:  ptr.To(gwapiv1.Kind("ConfigMap")),
+					Name:  gwapiv1.ObjectName(validation.CACertificateRef.Name),
+				}},
+			}
+			if validation.CACertificate != nil {
+				e.builderMap.ConfigMaps[validation.CACertificateRef] = validation.CACertificate
+			}
+
+			if validation.PassCertificateToUpstream {
+				if clientTrafficPolicy.Spec.Headers == nil {
+					clientTrafficPolicy.Spec.Headers = &egapiv1a1.HeaderSettings{}
+				}
+				clientTrafficPolicy.Spec.Headers.XForwardedClientCert = &egapiv1a1.XForwardedClientCert{
+					Mode:             ptr.To(egapiv1a1.XFCCForwardModeSanitizeSet),
+					CertDetailsToAdd: []egapiv1a1.XFCCCertData{egapiv1a1.XFCCCertDataCert},
+				}
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The client certificate of %s is passed to the backends in the x-forwarded-client-cert header instead of ssl-client-cert", strings.Join(validation.Hostnames, ", ")),
+					clientTrafficPolicy)
+			}
+			if validation.VerifyDepth != 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The client certificate verification depth %d of %s can't be configured", validation.VerifyDepth, strings.Join(validation.Hostnames, ", ")),
+					clientTrafficPolicy)
+			}
+		}
+
+		// mark client certificate validation IR as processed
+		ctx.ClientCertificateValidationByListener = nil
+		ir.Gateways[nn] = ctx
+	}
+}
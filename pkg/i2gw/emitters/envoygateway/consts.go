@@ -77,4 +77,10 @@ var (
 		Version: "v1alpha1",
 		Kind:    "BackendTrafficPolicy",
 	}
+
+	EnvoyExtensionPolicyGVK = schema.GroupVersionKind{
+		Group:   "gateway.envoyproxy.io",
+		Version: "v1alpha1",
+		Kind:    "EnvoyExtensionPolicy",
+	}
 )
@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// errorPageBodyKey is the key of the ConfigMaps Envoy Gateway reads the bodies of
+// the response overrides from.
+const errorPageBodyKey = "response.body"
+
+// errorPageConfigMapName returns the name of the ConfigMap holding the body of the
+// error response with the status code.
+func errorPageConfigMapName(backend gwapiv1.BackendObjectReference, code int) string {
+	return fmt.Sprintf("%s-%d", backend.Name, code)
+}
+
+// buildErrorPageConfigMap returns a ConfigMap with a placeholder error page for the
+// status code. Envoy Gateway rejects the whole BackendTrafficPolicy when a ConfigMap
+// of its response overrides is missing, so the ConfigMaps are always generated.
+func buildErrorPageConfigMap(key types.NamespacedName, code int) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Data: map[string]string{errorPageBodyKey: fmt.Sprintf("%d %s\n", code, http.StatusText(code))},
+	}
+	configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	return configMap
+}
+
+// EmitCustomErrorResponses converts the custom error response intent into
+// BackendTrafficPolicy response overrides. Envoy Gateway can't send the error
+// responses to a backend, so their bodies are read from a ConfigMap per status code,
+// generated with a placeholder to replace with the error pages served by the backend.
+func (e *Emitter) EmitCustomErrorResponses(ir emitterir.EmitterIR) {
+	// The ConfigMaps to create are reported once per source.
+	reported := map[string]struct{}{}
+
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.CustomErrorResponsesByRuleIdx == nil {
+			continue
+		}
+
+		for idx, customErrors := range ctx.CustomErrorResponsesByRuleIdx {
+			if customErrors == nil {
+				continue
+			}
+
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
+			configMaps := make([]string, 0, len(customErrors.StatusCodes))
+			for _, code := range customErrors.StatusCodes {
+				configMap := errorPageConfigMapName(customErrors.Backend, code)
+				configMaps = append(configMaps, configMap)
+				configMapKey := types.NamespacedName{Namespace: ctx.Namespace, Name: configMap}
+				if _, exists := e.builderMap.ConfigMaps[configMapKey]; !exists {
+					e.builderMap.ConfigMaps[configMapKey] = buildErrorPageConfigMap(configMapKey, code)
+				}
+				backendTrafficPolicy.Spec.ResponseOverride = append(backendTrafficPolicy.Spec.ResponseOverride, &egapiv1a1.ResponseOverride{
+					Match: egapiv1a1.CustomResponseMatch{
+						StatusCodes: []egapiv1a1.StatusCodeMatch{{
+							Type:  ptr.To(egapiv1a1.StatusCodeValueTypeValue),
+							Value: ptr.To(code),
+						}},
+					},
+					Response: &egapiv1a1.CustomResponse{
+						Body: &egapiv1a1.CustomResponseBody{
+							Type: ptr.To(egapiv1a1.ResponseValueTypeValueRef),
+							ValueRef: &gwapiv1.LocalObjectReference{
+								Kind: "ConfigMap",
+								Name: gwapiv1.ObjectName(configMap),
+							},
+						},
+					},
+				})
+			}
+
+			if _, found := reported[customErrors.Metadata.Source()]; found {
+				continue
+			}
+			reported[customErrors.Metadata.Source()] = struct{}{}
+			e.notify(notifications.WarningNotification,
+				fmt.Sprintf("Envoy Gateway can't send the error responses from %s to Service %s, the %q key of ConfigMaps %s holds a placeholder to replace with the error pages of the Service",
+					customErrors.Metadata.Source(), customErrors.Backend.Name, errorPageBodyKey, strings.Join(configMaps, ", ")),
+				backendTrafficPolicy)
+		}
+
+		// mark Custom Error Responses IR as processed
+		ctx.CustomErrorResponsesByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
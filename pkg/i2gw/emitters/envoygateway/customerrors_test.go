@@ -0,0 +1,127 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"testing"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestEmitCustomErrorResponses(t *testing.T) {
+	backend := gatewayv1.BackendObjectReference{Name: "errors"}
+	routeContext := func(name string, customErrors map[int]*emitterir.CustomErrorResponses) emitterir.HTTPRouteContext {
+		return emitterir.HTTPRouteContext{
+			HTTPRoute: gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{
+					{Name: ptr.To(gatewayv1.SectionName("rule-0"))},
+				}},
+			},
+			CustomErrorResponsesByRuleIdx: customErrors,
+		}
+	}
+
+	foo := types.NamespacedName{Namespace: "default", Name: "foo"}
+	bar := types.NamespacedName{Namespace: "default", Name: "bar"}
+	ir := emitterir.EmitterIR{HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+		foo: routeContext("foo", map[int]*emitterir.CustomErrorResponses{0: {
+			Metadata:    emitterir.NewExtensionFeatureMetadata("default/foo", nil, ""),
+			StatusCodes: []int{404, 503},
+			Backend:     backend,
+		}}),
+		bar: routeContext("bar", map[int]*emitterir.CustomErrorResponses{RouteRuleAllIndex: {
+			Metadata:    emitterir.NewExtensionFeatureMetadata("default/bar", nil, ""),
+			StatusCodes: []int{404},
+			Backend:     backend,
+		}}),
+	}}
+
+	warnings := 0
+	e := &Emitter{builderMap: NewBuilderMap(), notify: func(mType notifications.MessageType, _ string, _ ...client.Object) {
+		if mType == notifications.WarningNotification {
+			warnings++
+		}
+	}}
+	e.EmitCustomErrorResponses(ir)
+
+	// The ConfigMaps of the status codes are shared by the routes.
+	expectedBodies := map[types.NamespacedName]map[string]string{
+		{Namespace: "default", Name: "errors-404"}: {errorPageBodyKey: "404 Not Found\n"},
+		{Namespace: "default", Name: "errors-503"}: {errorPageBodyKey: "503 Service Unavailable\n"},
+	}
+	bodies := map[types.NamespacedName]map[string]string{}
+	for key, configMap := range e.builderMap.ConfigMaps {
+		if configMap.Namespace != key.Namespace || configMap.Name != key.Name {
+			t.Errorf("ConfigMap %s is named %s/%s", key, configMap.Namespace, configMap.Name)
+		}
+		bodies[key] = configMap.Data
+	}
+	if diff := cmp.Diff(expectedBodies, bodies); diff != "" {
+		t.Errorf("Unexpected ConfigMaps (-want +got):\n%s", diff)
+	}
+
+	responseOverride := func(code int, configMap string) *egapiv1a1.ResponseOverride {
+		return &egapiv1a1.ResponseOverride{
+			Match: egapiv1a1.CustomResponseMatch{
+				StatusCodes: []egapiv1a1.StatusCodeMatch{{
+					Type:  ptr.To(egapiv1a1.StatusCodeValueTypeValue),
+					Value: ptr.To(code),
+				}},
+			},
+			Response: &egapiv1a1.CustomResponse{
+				Body: &egapiv1a1.CustomResponseBody{
+					Type:     ptr.To(egapiv1a1.ResponseValueTypeValueRef),
+					ValueRef: &gatewayv1.LocalObjectReference{Kind: "ConfigMap", Name: gatewayv1.ObjectName(configMap)},
+				},
+			},
+		}
+	}
+	expectedOverrides := map[types.NamespacedName][]*egapiv1a1.ResponseOverride{
+		{Namespace: "default", Name: "foo-0"}: {responseOverride(404, "errors-404"), responseOverride(503, "errors-503")},
+		{Namespace: "default", Name: "bar"}:   {responseOverride(404, "errors-404")},
+	}
+	overrides := map[types.NamespacedName][]*egapiv1a1.ResponseOverride{}
+	for key, policy := range e.builderMap.BackendTrafficPolicies {
+		overrides[key] = policy.Spec.ResponseOverride
+	}
+	if diff := cmp.Diff(expectedOverrides, overrides); diff != "" {
+		t.Errorf("Unexpected response overrides (-want +got):\n%s", diff)
+	}
+
+	if sectionName := e.builderMap.BackendTrafficPolicies[types.NamespacedName{Namespace: "default", Name: "foo-0"}].Spec.TargetRefs[0].SectionName; sectionName == nil || *sectionName != "rule-0" {
+		t.Errorf("Expected the BackendTrafficPolicy of rule 0 to target section rule-0, got %v", sectionName)
+	}
+
+	// The ConfigMaps to create are reported once per source.
+	if warnings != 2 {
+		t.Errorf("Expected 2 warnings, got %d", warnings)
+	}
+	for key, ctx := range ir.HTTPRoutes {
+		if ctx.CustomErrorResponsesByRuleIdx != nil {
+			t.Errorf("Expected the custom error responses of HTTPRoute %s to be processed", key)
+		}
+	}
+}
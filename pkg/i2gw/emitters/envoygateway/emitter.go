@@ -58,6 +58,16 @@ func (e *Emitter) Emit(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.Err
 func (e *Emitter) ToEnvoyGatewayResources(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
 	e.EmitBuffer(ir, gwResources)
 	e.EmitIPRangeControl(ir, gwResources)
+	e.EmitExternalAuth(ir, gwResources)
+	e.EmitBasicAuth(ir)
+	e.EmitRateLimit(ir)
+	e.EmitRetry(ir)
+	e.EmitCustomErrorResponses(ir)
+	e.EmitLocationRewrite(ir)
+	e.EmitLoadBalancer(ir)
+	e.EmitClientCertificateValidation(ir)
+	e.EmitListenerTLSOptions(ir)
+	e.EmitRegexPathRewrite(ir, gwResources)
 
 	for _, backendTrafficPolicy := range e.builderMap.BackendTrafficPolicies {
 		obj, err := i2gw.CastToUnstructured(backendTrafficPolicy)
@@ -76,4 +86,49 @@ func (e *Emitter) ToEnvoyGatewayResources(ir emitterir.EmitterIR, gwResources *i
 		}
 		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
 	}
+
+	for _, clientTrafficPolicy := range e.builderMap.ClientTrafficPolicies {
+		obj, err := i2gw.CastToUnstructured(clientTrafficPolicy)
+		if err != nil {
+			e.notify(notifications.ErrorNotification, "Failed to cast ClientTrafficPolicy to unstructured", clientTrafficPolicy)
+			continue
+		}
+		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
+	}
+
+	for _, envoyExtensionPolicy := range e.builderMap.EnvoyExtensionPolicies {
+		obj, err := i2gw.CastToUnstructured(envoyExtensionPolicy)
+		if err != nil {
+			e.notify(notifications.ErrorNotification, "Failed to cast EnvoyExtensionPolicy to unstructured", envoyExtensionPolicy)
+			continue
+		}
+		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
+	}
+
+	for _, httpRouteFilter := range e.builderMap.HTTPRouteFilters {
+		obj, err := i2gw.CastToUnstructured(httpRouteFilter)
+		if err != nil {
+			e.notify(notifications.ErrorNotification, "Failed to cast HTTPRouteFilter to unstructured", httpRouteFilter)
+			continue
+		}
+		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
+	}
+
+	for _, secret := range e.builderMap.Secrets {
+		obj, err := i2gw.CastToUnstructured(secret)
+		if err != nil {
+			e.notify(notifications.ErrorNotification, "Failed to cast Secret to unstructured", secret)
+			continue
+		}
+		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
+	}
+
+	for _, configMap := range e.builderMap.ConfigMaps {
+		obj, err := i2gw.CastToUnstructured(configMap)
+		if err != nil {
+			e.notify(notifications.ErrorNotification, "Failed to cast ConfigMap to unstructured", configMap)
+			continue
+		}
+		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *obj)
+	}
 }
@@ -0,0 +1,104 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitExternalAuth converts the external authentication intent into SecurityPolicy extAuth.
+func (e *Emitter) EmitExternalAuth(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.ExternalAuthByRuleIdx == nil {
+			continue
+		}
+
+		MergeExternalAuthIR(&ctx)
+
+		for idx, extAuth := range ctx.ExternalAuthByRuleIdx {
+			if extAuth.Service == nil {
+				e.notify(notifications.ErrorNotification,
+					fmt.Sprintf("auth-url %q doesn't point to an in-cluster Service (<service>.<namespace>.svc), external authentication can't be converted", extAuth.URL),
+					&ctx.HTTPRoute)
+				continue
+			}
+
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			securityPolicy := e.getOrBuildSecurityPolicy(ctx, sectionName, idx)
+			httpService := &egapiv1a1.HTTPExtAuthService{
+				BackendCluster: egapiv1a1.BackendCluster{
+					BackendRefs: []egapiv1a1.BackendRef{{BackendObjectReference: *extAuth.Service}},
+				},
+				HeadersToBackend: extAuth.ResponseHeaders,
+			}
+			if extAuth.Path != "" && extAuth.Path != "/" {
+				httpService.Path = ptr.To(extAuth.Path)
+			}
+			securityPolicy.Spec.ExtAuth = &egapiv1a1.ExtAuth{
+				HTTP:             httpService,
+				HeadersToExtAuth: extAuth.RequestHeaders,
+			}
+			utils.AddServiceReferenceGrant(gwResources, gwapiv1.Group(SecurityPolicyGVK.Group), gwapiv1.Kind(SecurityPolicyGVK.Kind), ctx.Namespace, *extAuth.Service)
+
+			e.notifyExternalAuthDifferences(extAuth, securityPolicy)
+		}
+
+		// mark External Auth IR as processed
+		ctx.ExternalAuthByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
+
+func (e *Emitter) notifyExternalAuthDifferences(extAuth *emitterir.ExternalAuth, securityPolicy *egapiv1a1.SecurityPolicy) {
+	if extAuth.Path != "" && extAuth.Path != "/" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("Envoy Gateway appends the original request path to the auth-url path %q, the authentication service may need to be adjusted", extAuth.Path),
+			securityPolicy)
+	}
+	if extAuth.Method != "" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("auth-method %q can't be set, the authentication request keeps the method of the original request", extAuth.Method),
+			securityPolicy)
+	}
+	if extAuth.RequestHeaders == nil {
+		e.notify(notifications.WarningNotification,
+			"Only the Host, Method, Path, Content-Length and Authorization headers are sent to the authentication service, list any other needed header (e.g. Cookie) in headersToExtAuth",
+			securityPolicy)
+	}
+	if extAuth.SigninURL != "" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("auth-signin %q is not supported, unauthenticated requests are not redirected", extAuth.SigninURL),
+			securityPolicy)
+	}
+	if extAuth.CacheKey != "" || extAuth.CacheDuration != "" {
+		e.notify(notifications.WarningNotification,
+			"auth-cache-key and auth-cache-duration are not supported, authentication responses are not cached",
+			securityPolicy)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+)
+
+// pathHeader is the pseudo-header holding the request URI, including its query string.
+const pathHeader = ":path"
+
+// buildLoadBalancer converts the load balancing intent into a BackendTrafficPolicy load balancer.
+func buildLoadBalancer(loadBalancer *emitterir.LoadBalancer) *egapiv1a1.LoadBalancer {
+	switch loadBalancer.Algorithm {
+	case emitterir.LoadBalancerRoundRobin:
+		return &egapiv1a1.LoadBalancer{Type: egapiv1a1.RoundRobinLoadBalancerType}
+	case emitterir.LoadBalancerLeastRequest:
+		return &egapiv1a1.LoadBalancer{Type: egapiv1a1.LeastRequestLoadBalancerType}
+	case emitterir.LoadBalancerConsistentHash:
+		consistentHash := &egapiv1a1.ConsistentHash{}
+		switch loadBalancer.HashKey.Type {
+		case emitterir.LoadBalancerHashSourceIP:
+			consistentHash.Type = egapiv1a1.SourceIPConsistentHashType
+		case emitterir.LoadBalancerHashHeader:
+			consistentHash.Type = egapiv1a1.HeadersConsistentHashType
+			consistentHash.Headers = []*egapiv1a1.Header{{Name: loadBalancer.HashKey.Name}}
+		case emitterir.LoadBalancerHashCookie:
+			consistentHash.Type = egapiv1a1.CookieConsistentHashType
+			consistentHash.Cookie = &egapiv1a1.Cookie{Name: loadBalancer.HashKey.Name}
+		case emitterir.LoadBalancerHashURI:
+			consistentHash.Type = egapiv1a1.HeadersConsistentHashType
+			consistentHash.Headers = []*egapiv1a1.Header{{Name: pathHeader}}
+		default:
+			return nil
+		}
+		return &egapiv1a1.LoadBalancer{
+			Type:           egapiv1a1.ConsistentHashLoadBalancerType,
+			ConsistentHash: consistentHash,
+		}
+	default:
+		return nil
+	}
+}
+
+// buildSessionAffinityLoadBalancer converts the cookie session affinity into a
+// BackendTrafficPolicy load balancer hashing the cookie. Envoy only generates the
+// cookie when it has a TTL, so cookies without TTL are generated as session cookies.
+func buildSessionAffinityLoadBalancer(sessionAffinity *emitterir.SessionAffinity) *egapiv1a1.LoadBalancer {
+	cookie := &egapiv1a1.Cookie{
+		Name: sessionAffinity.CookieName,
+		TTL:  ptr.To(gwapiv1.Duration("0s")),
+	}
+	if sessionAffinity.CookieTTLSec != nil {
+		cookie.TTL = ptr.To(gwapiv1.Duration((time.Duration(*sessionAffinity.CookieTTLSec) * time.Second).String()))
+	}
+	attributes := map[string]string{}
+	if sessionAffinity.CookiePath != "" {
+		attributes["Path"] = sessionAffinity.CookiePath
+	}
+	if sessionAffinity.CookieSameSite != "" {
+		attributes["SameSite"] = sessionAffinity.CookieSameSite
+	}
+	if sessionAffinity.CookieSecure {
+		attributes["Secure"] = ""
+	}
+	if len(attributes) > 0 {
+		cookie.Attributes = attributes
+	}
+
+	return &egapiv1a1.LoadBalancer{
+		Type: egapiv1a1.ConsistentHashLoadBalancerType,
+		ConsistentHash: &egapiv1a1.ConsistentHash{
+			Type:   egapiv1a1.CookieConsistentHashType,
+			Cookie: cookie,
+		},
+	}
+}
+
+// serviceLoadBalancer is the load balancer of a Service and the source of its intent.
+type serviceLoadBalancer struct {
+	loadBalancer *egapiv1a1.LoadBalancer
+	source       string
+}
+
+// serviceLoadBalancers returns the load balancers of the Services. Like in ingress-nginx,
+// cookie session affinity takes precedence over the load balancing algorithm.
+func (e *Emitter) serviceLoadBalancers(ir emitterir.EmitterIR) map[types.NamespacedName]serviceLoadBalancer {
+	loadBalancers := map[types.NamespacedName]serviceLoadBalancer{}
+	for svcKey, svc := range ir.Services {
+		if svc.SessionAffinity != nil && svc.SessionAffinity.Type == "Cookie" {
+			if svc.LoadBalancer != nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Service %s uses session affinity, the load balancing from %s is ignored", svcKey, svc.LoadBalancer.Metadata.Source()))
+			}
+			if svc.SessionAffinity.Mode == emitterir.SessionAffinityPersistent {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Sessions of Service %s are hashed to its endpoints, so some of them move when the endpoints change, unlike with the persistent affinity mode from %s", svcKey, svc.SessionAffinity.Metadata.Source()))
+			}
+			loadBalancers[svcKey] = serviceLoadBalancer{
+				loadBalancer: buildSessionAffinityLoadBalancer(svc.SessionAffinity),
+				source:       svc.SessionAffinity.Metadata.Source(),
+			}
+			continue
+		}
+		if svc.LoadBalancer != nil {
+			if loadBalancer := buildLoadBalancer(svc.LoadBalancer); loadBalancer != nil {
+				loadBalancers[svcKey] = serviceLoadBalancer{
+					loadBalancer: loadBalancer,
+					source:       svc.LoadBalancer.Metadata.Source(),
+				}
+			}
+		}
+	}
+	return loadBalancers
+}
+
+// EmitLoadBalancer converts the load balancing and session affinity intent of Services into
+// BackendTrafficPolicy load balancers. BackendTrafficPolicies can't target Services, so they
+// target the rules of the HTTPRoutes forwarding requests to the Services instead.
+func (e *Emitter) EmitLoadBalancer(ir emitterir.EmitterIR) {
+	loadBalancers := e.serviceLoadBalancers(ir)
+	if len(loadBalancers) == 0 {
+		return
+	}
+	consumed := map[types.NamespacedName]bool{}
+
+	for _, ctx := range ir.HTTPRoutes {
+		loadBalancerByRuleIdx := map[int]*egapiv1a1.LoadBalancer{}
+		for idx, rule := range ctx.Spec.Rules {
+			var ruleLoadBalancer *serviceLoadBalancer
+			var unbalancedBackends int
+			for _, backendRef := range rule.BackendRefs {
+				if backendRef.Group != nil && *backendRef.Group != "" || backendRef.Kind != nil && *backendRef.Kind != "Service" {
+					continue
+				}
+				svcKey := types.NamespacedName{Namespace: ctx.Namespace, Name: string(backendRef.Name)}
+				if backendRef.Namespace != nil {
+					svcKey.Namespace = string(*backendRef.Namespace)
+				}
+				loadBalancer, ok := loadBalancers[svcKey]
+				if !ok {
+					unbalancedBackends++
+					continue
+				}
+				consumed[svcKey] = true
+
+				if ruleLoadBalancer == nil {
+					ruleLoadBalancer = &loadBalancer
+				} else if !reflect.DeepEqual(ruleLoadBalancer.loadBalancer, loadBalancer.loadBalancer) {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("Backends of rule %d are load balanced differently, the load balancing from %s is ignored", idx, loadBalancer.source),
+						&ctx.HTTPRoute)
+				}
+			}
+			if ruleLoadBalancer == nil {
+				continue
+			}
+			if unbalancedBackends > 0 {
+				e.notify(notifications.InfoNotification,
+					fmt.Sprintf("The load balancing from %s also applies to the other backends of rule %d", ruleLoadBalancer.source, idx),
+					&ctx.HTTPRoute)
+			}
+			loadBalancerByRuleIdx[idx] = ruleLoadBalancer.loadBalancer
+		}
+
+		for idx, loadBalancer := range mergeLoadBalancers(loadBalancerByRuleIdx, len(ctx.Spec.Rules)) {
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
+			backendTrafficPolicy.Spec.LoadBalancer = loadBalancer
+		}
+	}
+
+	// mark LoadBalancer and SessionAffinity IR as processed
+	for svcKey := range consumed {
+		svc := ir.Services[svcKey]
+		svc.LoadBalancer = nil
+		svc.SessionAffinity = nil
+		ir.Services[svcKey] = svc
+	}
+}
+
+// mergeLoadBalancers returns the load balancer of all the rules at RouteRuleAllIndex
+// when every rule is load balanced the same way.
+func mergeLoadBalancers(loadBalancerByRuleIdx map[int]*egapiv1a1.LoadBalancer, rules int) map[int]*egapiv1a1.LoadBalancer {
+	if len(loadBalancerByRuleIdx) != rules {
+		return loadBalancerByRuleIdx
+	}
+
+	var first *egapiv1a1.LoadBalancer
+	for _, loadBalancer := range loadBalancerByRuleIdx {
+		if first == nil {
+			first = loadBalancer
+			continue
+		}
+		if !reflect.DeepEqual(first, loadBalancer) {
+			return loadBalancerByRuleIdx
+		}
+	}
+
+	return map[int]*egapiv1a1.LoadBalancer{
+		RouteRuleAllIndex: first,
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// locationRewriteLua replaces the from prefix of the Location header of the responses with to.
+const locationRewriteLua = `function envoy_on_response(response_handle)
+  local from, to = %s, %s
+  local location = response_handle:headers():get("location")
+  if location ~= nil and location:sub(1, #from) == from then
+    response_handle:headers():replace("location", to .. location:sub(#from + 1))
+  end
+end
+`
+
+// EmitLocationRewrite converts the Location header rewrite intent into
+// EnvoyExtensionPolicies running a Lua filter on the responses.
+func (e *Emitter) EmitLocationRewrite(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.LocationRewriteByRuleIdx == nil {
+			continue
+		}
+
+		for idx, locationRewrite := range ctx.LocationRewriteByRuleIdx {
+			if locationRewrite == nil {
+				continue
+			}
+
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			envoyExtensionPolicy := e.getOrBuildEnvoyExtensionPolicy(ctx, sectionName, idx)
+			envoyExtensionPolicy.Spec.Lua = append(envoyExtensionPolicy.Spec.Lua, egapiv1a1.Lua{
+				Type:   egapiv1a1.LuaValueTypeInline,
+				Inline: ptr.To(fmt.Sprintf(locationRewriteLua, luaQuote(locationRewrite.From), luaQuote(locationRewrite.To))),
+			})
+		}
+
+		// mark Location Rewrite IR as processed
+		ctx.LocationRewriteByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+)
+
+// EmitRegexPathRewrite converts the path rewrites with capture group references into
+// HTTPRouteFilters replacing the regex match of the path, referenced by the rules.
+func (e *Emitter) EmitRegexPathRewrite(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	for nn, ctx := range ir.HTTPRoutes {
+		httpRoute, ok := gwResources.HTTPRoutes[nn]
+		if !ok {
+			continue
+		}
+
+		for idx, rewrite := range ctx.PathRewriteByRuleIdx {
+			if rewrite == nil || rewrite.Regex == nil || idx >= len(httpRoute.Spec.Rules) {
+				continue
+			}
+
+			filter := e.buildRegexPathRewriteFilter(ctx, idx, rewrite.Regex)
+			rule := &httpRoute.Spec.Rules[idx]
+			rule.Filters = append(rule.Filters, gwapiv1.HTTPRouteFilter{
+				Type: gwapiv1.HTTPRouteFilterExtensionRef,
+				ExtensionRef: &gwapiv1.LocalObjectReference{
+					Group: gwapiv1.Group(HTTPRouteFilterGVK.Group),
+					Kind:  gwapiv1.Kind(HTTPRouteFilterGVK.Kind),
+					Name:  gwapiv1.ObjectName(filter.Name),
+				},
+			})
+			if headersFilter := rewrite.HeadersFilter(); headersFilter != nil {
+				rule.Filters = append(rule.Filters, *headersFilter)
+			}
+
+			// mark Path Rewrite IR as processed
+			ctx.PathRewriteByRuleIdx[idx] = nil
+		}
+
+		gwResources.HTTPRoutes[nn] = httpRoute
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
+
+func (e *Emitter) buildRegexPathRewriteFilter(ctx emitterir.HTTPRouteContext, ruleIdx int, regex *emitterir.RegexPathRewrite) *egapiv1a1.HTTPRouteFilter {
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%d", ctx.Name, ruleIdx),
+		Namespace: ctx.Namespace,
+	}
+	httpRouteFilter := &egapiv1a1.HTTPRouteFilter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+		Spec: egapiv1a1.HTTPRouteFilterSpec{
+			URLRewrite: &egapiv1a1.HTTPURLRewriteFilter{
+				Path: &egapiv1a1.HTTPPathModifier{
+					Type: egapiv1a1.RegexHTTPPathModifier,
+					ReplaceRegexMatch: &egapiv1a1.ReplaceRegexMatch{
+						Pattern:      regex.Pattern,
+						Substitution: regex.Substitution,
+					},
+				},
+			},
+		},
+	}
+	httpRouteFilter.SetGroupVersionKind(HTTPRouteFilterGVK)
+
+	e.builderMap.HTTPRouteFilters[key] = httpRouteFilter
+	return httpRouteFilter
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"strings"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// clientCIDRs are the source CIDRs of the IPv4 and IPv6 clients. The selectors of a rule
+// must all match, so each address family has its own rule.
+var clientCIDRs = []string{"0.0.0.0/0", "::/0"}
+
+// buildRateLimitRules builds the local rate limit rules applying the limit to each client IP address.
+func buildRateLimitRules(requests int32, unit egapiv1a1.RateLimitUnit) []egapiv1a1.RateLimitRule {
+	rules := make([]egapiv1a1.RateLimitRule, 0, len(clientCIDRs))
+	for _, cidr := range clientCIDRs {
+		rules = append(rules, egapiv1a1.RateLimitRule{
+			ClientSelectors: []egapiv1a1.RateLimitSelectCondition{{
+				SourceCIDR: &egapiv1a1.SourceMatch{
+					Type:  ptr.To(egapiv1a1.SourceMatchDistinct),
+					Value: cidr,
+				},
+			}},
+			Limit: egapiv1a1.RateLimitValue{
+				Requests: uint(requests),
+				Unit:     unit,
+			},
+		})
+	}
+	return rules
+}
+
+// EmitRateLimit converts the rate limiting intent into BackendTrafficPolicy local rate limits.
+func (e *Emitter) EmitRateLimit(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.RateLimitByRuleIdx == nil {
+			continue
+		}
+
+		MergeRateLimitIR(&ctx)
+
+		for idx, rateLimit := range ctx.RateLimitByRuleIdx {
+			var rules []egapiv1a1.RateLimitRule
+			if rateLimit.RequestsPerSecond > 0 {
+				rules = append(rules, buildRateLimitRules(rateLimit.RequestsPerSecond, egapiv1a1.RateLimitUnitSecond)...)
+			}
+			if rateLimit.RequestsPerMinute > 0 {
+				rules = append(rules, buildRateLimitRules(rateLimit.RequestsPerMinute, egapiv1a1.RateLimitUnitMinute)...)
+			}
+			if len(rules) == 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Limiting the concurrent connections of each client to %d is not supported", rateLimit.Connections),
+					&ctx.HTTPRoute)
+				continue
+			}
+
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
+			backendTrafficPolicy.Spec.RateLimit = &egapiv1a1.RateLimitSpec{
+				Type:  ptr.To(egapiv1a1.LocalRateLimitType),
+				Local: &egapiv1a1.LocalRateLimit{Rules: rules},
+			}
+
+			e.notify(notifications.InfoNotification,
+				"Local rate limits are enforced by each Envoy replica, like ingress-nginx enforces them in each controller pod: the overall limit grows with the number of replicas",
+				backendTrafficPolicy)
+			if rateLimit.BurstMultiplier > 1 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Bursts of %d times the limits can't be allowed, bursts are limited to the requests allowed per unit of time", rateLimit.BurstMultiplier),
+					backendTrafficPolicy)
+			}
+			if rateLimit.Connections > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Limiting the concurrent connections of each client to %d is not supported", rateLimit.Connections),
+					backendTrafficPolicy)
+			}
+			if len(rateLimit.AllowList) > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Clients from %s can't be excluded from rate limiting", strings.Join(rateLimit.AllowList, ", ")),
+					backendTrafficPolicy)
+			}
+		}
+
+		// mark Rate Limit IR as processed
+		ctx.RateLimitByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	"fmt"
+	"slices"
+
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// retryTriggers returns the Envoy retry triggers matching the retry conditions.
+// Timed out requests are reset, so both conditions retry resets.
+func retryTriggers(retry *emitterir.Retry) []egapiv1a1.TriggerEnum {
+	var triggers []egapiv1a1.TriggerEnum
+	if slices.Contains(retry.Conditions, emitterir.RetryOnConnectFailure) {
+		triggers = append(triggers, egapiv1a1.ConnectFailure)
+	}
+	if len(retry.Conditions) > 0 {
+		triggers = append(triggers, egapiv1a1.Reset)
+	}
+	if len(retry.StatusCodes) > 0 {
+		triggers = append(triggers, egapiv1a1.RetriableStatusCodes)
+	}
+	return triggers
+}
+
+// EmitRetry converts the retry intent into BackendTrafficPolicy retries.
+func (e *Emitter) EmitRetry(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.RetryByRuleIdx == nil {
+			continue
+		}
+
+		MergeRetryIR(&ctx)
+
+		for idx, retry := range ctx.RetryByRuleIdx {
+			var sectionName *gwapiv1.SectionName
+			if idx != RouteRuleAllIndex && idx < len(ctx.Spec.Rules) {
+				sectionName = ctx.Spec.Rules[idx].Name
+			}
+
+			backendTrafficPolicy := e.getOrBuildBackendTrafficPolicy(ctx, sectionName, idx)
+			backendTrafficPolicy.Spec.Retry = &egapiv1a1.Retry{
+				NumRetries: retry.Attempts,
+				RetryOn: &egapiv1a1.RetryOn{
+					Triggers: retryTriggers(retry),
+				},
+			}
+			for _, code := range retry.StatusCodes {
+				backendTrafficPolicy.Spec.Retry.RetryOn.HTTPStatusCodes = append(backendTrafficPolicy.Spec.Retry.RetryOn.HTTPStatusCodes, egapiv1a1.HTTPStatus(code))
+			}
+			if retry.PerTryTimeout != nil {
+				backendTrafficPolicy.Spec.Retry.PerRetry = &egapiv1a1.PerRetryPolicy{
+					Timeout: retry.PerTryTimeout,
+				}
+			}
+
+			if retry.Timeout != nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Retries from %s can't be limited to %s in total", retry.Metadata.Source(), *retry.Timeout),
+					backendTrafficPolicy)
+			}
+		}
+
+		// mark Retry IR as processed
+		ctx.RetryByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envoygateway_emitter
+
+import (
+	egapiv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitListenerTLSOptions converts the TLS options intent into a ClientTrafficPolicy
+// targeting the listener, which configures the TLS connections of the listener and
+// sets the HSTS header on every response of the listener.
+func (e *Emitter) EmitListenerTLSOptions(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.Gateways {
+		if ctx.TLSOptionsByListener == nil {
+			continue
+		}
+
+		for sectionName, options := range ctx.TLSOptionsByListener {
+			if options == nil {
+				continue
+			}
+
+			clientTrafficPolicy := e.getOrBuildClientTrafficPolicy(ctx.Gateway, sectionName)
+			if options.HasTLSParameters() {
+				if clientTrafficPolicy.Spec.TLS == nil {
+					clientTrafficPolicy.Spec.TLS = &egapiv1a1.ClientTLSSettings{}
+				}
+				tls := &clientTrafficPolicy.Spec.TLS.TLSSettings
+				if options.MinVersion != "" {
+					tls.MinVersion = ptr.To(egapiv1a1.TLSVersion(options.MinVersion))
+				}
+				if options.MaxVersion != "" {
+					tls.MaxVersion = ptr.To(egapiv1a1.TLSVersion(options.MaxVersion))
+				}
+				tls.Ciphers = options.CipherSuites
+				for _, protocol := range options.ALPNProtocols {
+					tls.ALPNProtocols = append(tls.ALPNProtocols, egapiv1a1.ALPNProtocol(protocol))
+				}
+			}
+
+			if options.HSTS != nil {
+				if clientTrafficPolicy.Spec.Headers == nil {
+					clientTrafficPolicy.Spec.Headers = &egapiv1a1.HeaderSettings{}
+				}
+				if clientTrafficPolicy.Spec.Headers.LateResponseHeaders == nil {
+					clientTrafficPolicy.Spec.Headers.LateResponseHeaders = &egapiv1a1.HTTPHeaderFilter{}
+				}
+				clientTrafficPolicy.Spec.Headers.LateResponseHeaders.Set = append(clientTrafficPolicy.Spec.Headers.LateResponseHeaders.Set, gwapiv1.HTTPHeader{
+					Name:  emitterir.HSTSHeader,
+					Value: options.HSTS.HeaderValue(),
+				})
+			}
+		}
+
+		// mark TLS options IR as processed
+		ctx.TLSOptionsByListener = nil
+		ir.Gateways[nn] = ctx
+	}
+}
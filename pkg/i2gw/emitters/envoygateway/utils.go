@@ -17,49 +17,54 @@ limitations under the License.
 package envoygateway_emitter
 
 import (
-	"reflect"
+	"fmt"
+	"strings"
 
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
 )
 
-func MergeBodySizeIR(ctx *emitterir.HTTPRouteContext) {
-	if len(ctx.BodySizeByRuleIdx) != len(ctx.Spec.Rules) {
-		return
-	}
-
-	var first *emitterir.BodySize
-	for _, bs := range ctx.BodySizeByRuleIdx {
-		if first == nil {
-			first = bs
-			continue
-		}
-		if !reflect.DeepEqual(first, bs) {
-			return
+// luaQuote returns the Lua string literal of s. Lua doesn't understand the escapes
+// of Go string literals, like \u00e9, so the bytes other than printable ASCII are
+// escaped with their decimal value, which keeps UTF-8 strings byte for byte.
+func luaQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "\\%03d", c)
 		}
 	}
+	b.WriteByte('"')
+	return b.String()
+}
 
-	ctx.BodySizeByRuleIdx = map[int]*emitterir.BodySize{
-		RouteRuleAllIndex: first,
-	}
+func MergeBufferingIR(ctx *emitterir.HTTPRouteContext) {
+	ctx.BufferingByRuleIdx = utils.MergeByRuleIdx(ctx.BufferingByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
 }
 
 func MergeIPRangeControlIR(ctx *emitterir.HTTPRouteContext) {
-	if len(ctx.IPRangeControlByRuleIdx) != len(ctx.Spec.Rules) {
-		return
-	}
+	ctx.IPRangeControlByRuleIdx = utils.MergeByRuleIdx(ctx.IPRangeControlByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+}
 
-	var first *emitterir.IPRangeControl
-	for _, iprc := range ctx.IPRangeControlByRuleIdx {
-		if first == nil {
-			first = iprc
-			continue
-		}
-		if !reflect.DeepEqual(first, iprc) {
-			return
-		}
-	}
+func MergeExternalAuthIR(ctx *emitterir.HTTPRouteContext) {
+	ctx.ExternalAuthByRuleIdx = utils.MergeByRuleIdx(ctx.ExternalAuthByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+}
 
-	ctx.IPRangeControlByRuleIdx = map[int]*emitterir.IPRangeControl{
-		RouteRuleAllIndex: first,
-	}
+func MergeBasicAuthIR(ctx *emitterir.HTTPRouteContext) {
+	ctx.BasicAuthByRuleIdx = utils.MergeByRuleIdx(ctx.BasicAuthByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+}
+
+func MergeRateLimitIR(ctx *emitterir.HTTPRouteContext) {
+	ctx.RateLimitByRuleIdx = utils.MergeByRuleIdx(ctx.RateLimitByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+}
+
+func MergeRetryIR(ctx *emitterir.HTTPRouteContext) {
+	ctx.RetryByRuleIdx = utils.MergeByRuleIdx(ctx.RetryByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
 }
@@ -25,7 +25,7 @@ import (
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-func TestMergeBodySizeIR(t *testing.T) {
+func TestMergeBufferingIR(t *testing.T) {
 	maxSize10M := resource.MustParse("10M")
 	maxSize20M := resource.MustParse("20M")
 	bufferSize5M := resource.MustParse("5M")
@@ -34,45 +34,45 @@ func TestMergeBodySizeIR(t *testing.T) {
 	tests := []struct {
 		name         string
 		numRules     int
-		bodySizeMap  map[int]*emitterir.BodySize
+		bodySizeMap  map[int]*emitterir.Buffering
 		wantMerged   bool
-		wantBodySize *emitterir.BodySize
+		wantBodySize *emitterir.Buffering
 	}{
 		{
 			name:     "all rules have same body size - should merge",
 			numRules: 3,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
 				1: {MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
 				2: {MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
 			},
 			wantMerged:   true,
-			wantBodySize: &emitterir.BodySize{MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
+			wantBodySize: &emitterir.Buffering{MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
 		},
 		{
 			name:     "all rules have same max size only - should merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M},
 				1: {MaxSize: &maxSize10M},
 			},
 			wantMerged:   true,
-			wantBodySize: &emitterir.BodySize{MaxSize: &maxSize10M},
+			wantBodySize: &emitterir.Buffering{MaxSize: &maxSize10M},
 		},
 		{
 			name:     "all rules have same buffer size only - should merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {BufferSize: &bufferSize5M},
 				1: {BufferSize: &bufferSize5M},
 			},
 			wantMerged:   true,
-			wantBodySize: &emitterir.BodySize{BufferSize: &bufferSize5M},
+			wantBodySize: &emitterir.Buffering{BufferSize: &bufferSize5M},
 		},
 		{
 			name:     "different max size - should not merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M},
 				1: {MaxSize: &maxSize20M},
 			},
@@ -81,7 +81,7 @@ func TestMergeBodySizeIR(t *testing.T) {
 		{
 			name:     "different buffer size - should not merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {BufferSize: &bufferSize5M},
 				1: {BufferSize: &bufferSize8M},
 			},
@@ -90,7 +90,7 @@ func TestMergeBodySizeIR(t *testing.T) {
 		{
 			name:     "one has buffer size, one doesn't - should not merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M, BufferSize: &bufferSize5M},
 				1: {MaxSize: &maxSize10M},
 			},
@@ -99,7 +99,7 @@ func TestMergeBodySizeIR(t *testing.T) {
 		{
 			name:     "one has max size, one doesn't - should not merge",
 			numRules: 2,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M},
 				1: {BufferSize: &bufferSize5M},
 			},
@@ -108,7 +108,7 @@ func TestMergeBodySizeIR(t *testing.T) {
 		{
 			name:     "body size map length doesn't match rules - should not merge",
 			numRules: 3,
-			bodySizeMap: map[int]*emitterir.BodySize{
+			bodySizeMap: map[int]*emitterir.Buffering{
 				0: {MaxSize: &maxSize10M},
 				1: {MaxSize: &maxSize10M},
 			},
@@ -117,7 +117,7 @@ func TestMergeBodySizeIR(t *testing.T) {
 		{
 			name:        "empty body size map - should not merge",
 			numRules:    2,
-			bodySizeMap: map[int]*emitterir.BodySize{},
+			bodySizeMap: map[int]*emitterir.Buffering{},
 			wantMerged:  false,
 		},
 	}
@@ -135,22 +135,22 @@ func TestMergeBodySizeIR(t *testing.T) {
 						Rules: make([]gatewayv1.HTTPRouteRule, tt.numRules),
 					},
 				},
-				BodySizeByRuleIdx: tt.bodySizeMap,
+				BufferingByRuleIdx: tt.bodySizeMap,
 			}
 
-			// Call MergeBodySizeIR
-			MergeBodySizeIR(ctx)
+			// Call MergeBufferingIR
+			MergeBufferingIR(ctx)
 
 			if tt.wantMerged {
 				// Should have merged to RouteRuleAllIndex
-				if len(ctx.BodySizeByRuleIdx) != 1 {
-					t.Errorf("expected BodySizeByRuleIdx to have 1 entry, got %d", len(ctx.BodySizeByRuleIdx))
+				if len(ctx.BufferingByRuleIdx) != 1 {
+					t.Errorf("expected BufferingByRuleIdx to have 1 entry, got %d", len(ctx.BufferingByRuleIdx))
 					return
 				}
 
-				merged, ok := ctx.BodySizeByRuleIdx[RouteRuleAllIndex]
+				merged, ok := ctx.BufferingByRuleIdx[RouteRuleAllIndex]
 				if !ok {
-					t.Errorf("expected BodySizeByRuleIdx to have entry at RouteRuleAllIndex=%d", RouteRuleAllIndex)
+					t.Errorf("expected BufferingByRuleIdx to have entry at RouteRuleAllIndex=%d", RouteRuleAllIndex)
 					return
 				}
 
@@ -180,11 +180,11 @@ func TestMergeBodySizeIR(t *testing.T) {
 					}
 				}
 			} else {
-				// Should not have merged - BodySizeByRuleIdx should be unchanged
-				if len(ctx.BodySizeByRuleIdx) != len(tt.bodySizeMap) {
-					t.Errorf("expected BodySizeByRuleIdx length to remain %d, got %d", len(tt.bodySizeMap), len(ctx.BodySizeByRuleIdx))
+				// Should not have merged - BufferingByRuleIdx should be unchanged
+				if len(ctx.BufferingByRuleIdx) != len(tt.bodySizeMap) {
+					t.Errorf("expected BufferingByRuleIdx length to remain %d, got %d", len(tt.bodySizeMap), len(ctx.BufferingByRuleIdx))
 				}
-				if _, exists := ctx.BodySizeByRuleIdx[RouteRuleAllIndex]; exists {
+				if _, exists := ctx.BufferingByRuleIdx[RouteRuleAllIndex]; exists {
 					t.Errorf("expected no entry at RouteRuleAllIndex=%d, but found one", RouteRuleAllIndex)
 				}
 			}
@@ -354,3 +354,23 @@ func TestMergeIPRangeControlIR(t *testing.T) {
 		})
 	}
 }
+
+func TestLuaQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "https://example.com/", want: `"https://example.com/"`},
+		{name: "quote and backslash", in: `a"b\c`, want: `"a\"b\\c"`},
+		{name: "control characters", in: "a\nb\x7f", want: `"a\010b\127"`},
+		{name: "UTF-8", in: "/caf\u00e9", want: `"/caf\195\169"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := luaQuote(tt.in); got != tt.want {
+				t.Errorf("luaQuote(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
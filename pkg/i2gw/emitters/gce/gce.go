@@ -67,6 +67,13 @@ func NewEmitter(conf *i2gw.EmitterConf) i2gw.Emitter {
 	}
 }
 
+// KeepsServiceSessionAffinity returns true: GKE Gateways don't support the
+// sessionPersistence of the routes, so the session affinity of Services is
+// converted to GCPBackendPolicies.
+func (c *Emitter) KeepsServiceSessionAffinity() bool {
+	return true
+}
+
 func (c *Emitter) Emit(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.ErrorList) {
 	gatewayResources, errs := utils.ToGatewayResources(ir)
 	if len(errs) != 0 {
@@ -80,6 +87,7 @@ func (c *Emitter) Emit(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.Err
 
 func buildGceGatewayExtensions(notify notifications.NotifyFunc, ir emitterir.EmitterIR, gatewayResources *i2gw.GatewayResources) {
 	for gwyKey, gatewayContext := range ir.Gateways {
+		applyListenerTLSOptions(notify, gwyKey, &gatewayContext)
 		gwyPolicy := addGatewayPolicyIfConfigured(gwyKey, &gatewayContext)
 		if gwyPolicy == nil {
 			continue
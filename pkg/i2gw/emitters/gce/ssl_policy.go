@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce_emitter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate/gce"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// sslPolicyFeatures maps the OpenSSL names of the cipher suites to the features of
+// the custom profile of the SSL policies.
+var sslPolicyFeatures = map[string]string{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384": "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-CHACHA20-POLY1305": "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256":   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-RSA-AES256-GCM-SHA384":   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-RSA-CHACHA20-POLY1305":   "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-ECDSA-AES256-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-AES128-SHA":          "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-RSA-AES256-SHA":          "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	"AES128-GCM-SHA256":             "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"AES256-GCM-SHA384":             "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"AES128-SHA":                    "TLS_RSA_WITH_AES_128_CBC_SHA",
+	"AES256-SHA":                    "TLS_RSA_WITH_AES_256_CBC_SHA",
+	"DES-CBC3-SHA":                  "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+}
+
+// applyListenerTLSOptions converts the TLS options intent of the listeners into the
+// SSL policy of the Gateway, which the GCPGatewayPolicy of the Gateway references.
+// SSL policies are created outside of the cluster, so the command creating it is
+// reported. The SSL policy applies to every listener of the Gateway, and can't
+// set the maximum TLS version, the ALPN protocols or the HSTS header.
+func applyListenerTLSOptions(notify notifications.NotifyFunc, gatewayKey types.NamespacedName, gatewayContext *emitterir.GatewayContext) {
+	var options *emitterir.ListenerTLSOptions
+	var listenerName string
+	for _, sectionName := range slices.Sorted(maps.Keys(gatewayContext.TLSOptionsByListener)) {
+		listenerOptions := gatewayContext.TLSOptionsByListener[sectionName]
+		if listenerOptions == nil {
+			continue
+		}
+		if listenerOptions.HSTS != nil {
+			notify(notifications.WarningNotification, fmt.Sprintf("Failed to apply HSTS of listener %s of Gateway %s from %s: HTTP Strict Transport Security is not supported by GKE Gateways, the Strict-Transport-Security header is not sent",
+				sectionName, gatewayKey, listenerOptions.Metadata.Source()))
+		}
+		if len(listenerOptions.ALPNProtocols) > 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("ALPN protocols %s of listener %s of Gateway %s from %s are not supported by SSL policies, the default ones are negotiated",
+				strings.Join(listenerOptions.ALPNProtocols, ", "), sectionName, gatewayKey, listenerOptions.Metadata.Source()))
+		}
+		if listenerOptions.MinVersion == "" && len(listenerOptions.CipherSuites) == 0 {
+			if listenerOptions.MaxVersion != "" {
+				notify(notifications.WarningNotification, fmt.Sprintf("Maximum TLS version %s of listener %s of Gateway %s from %s is not supported by SSL policies",
+					listenerOptions.MaxVersion, sectionName, gatewayKey, listenerOptions.Metadata.Source()))
+			}
+			continue
+		}
+		switch {
+		case options == nil:
+			options, listenerName = listenerOptions, string(sectionName)
+		case options.MinVersion != listenerOptions.MinVersion || !slices.Equal(options.CipherSuites, listenerOptions.CipherSuites):
+			notify(notifications.WarningNotification, fmt.Sprintf("Listeners %s and %s of Gateway %s configure different TLS options, the SSL policy of the Gateway uses the ones of %s",
+				listenerName, sectionName, gatewayKey, listenerName))
+		}
+	}
+	if options == nil {
+		return
+	}
+
+	if gatewayContext.Gce != nil && gatewayContext.Gce.SslPolicy != nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("Gateway %s already uses SSL policy %s, the TLS options from %s are not applied",
+			gatewayKey, gatewayContext.Gce.SslPolicy.Name, options.Metadata.Source()))
+		return
+	}
+	if options.MaxVersion != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Maximum TLS version %s of listener %s of Gateway %s from %s is not supported by SSL policies",
+			options.MaxVersion, listenerName, gatewayKey, options.Metadata.Source()))
+	}
+
+	name := fmt.Sprintf("%s-%s", gatewayKey.Namespace, gatewayKey.Name)
+	command := []string{"gcloud", "compute", "ssl-policies", "create", name}
+	if options.MinVersion != "" {
+		command = append(command, "--min-tls-version="+options.MinVersion)
+	}
+	var features, unsupported []string
+	for _, cipherSuite := range options.CipherSuites {
+		if feature, ok := sslPolicyFeatures[cipherSuite]; ok {
+			features = append(features, feature)
+		} else {
+			unsupported = append(unsupported, cipherSuite)
+		}
+	}
+	if len(unsupported) > 0 {
+		notify(notifications.WarningNotification, fmt.Sprintf("Cipher suites %s of listener %s of Gateway %s from %s are not supported by SSL policies",
+			strings.Join(unsupported, ", "), listenerName, gatewayKey, options.Metadata.Source()))
+	}
+	if len(features) > 0 {
+		command = append(command, "--profile=CUSTOM", "--custom-features="+strings.Join(features, ","))
+	} else {
+		command = append(command, "--profile=MODERN")
+	}
+	notify(notifications.InfoNotification, fmt.Sprintf("Gateway %s uses SSL policy %s for the TLS options from %s, create it with: %s",
+		gatewayKey, name, options.Metadata.Source(), strings.Join(command, " ")))
+
+	if gatewayContext.Gce == nil {
+		gatewayContext.Gce = &gce.GatewayIR{}
+	}
+	gatewayContext.Gce.SslPolicy = &gce.SslPolicyConfig{Name: name}
+}
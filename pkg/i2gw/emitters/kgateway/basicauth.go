@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitBasicAuth processes BasicAuthByRuleIdx from emitterIR and creates TrafficPolicies with basic auth configuration.
+// Source Secrets holding an htpasswd file in the namespace of the route are referenced as is,
+// other Secrets are converted to an htpasswd Secret named after the TrafficPolicy.
+func (e *Emitter) EmitBasicAuth(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.BasicAuthByRuleIdx == nil {
+			continue
+		}
+
+		ctx.BasicAuthByRuleIdx = utils.MergeByRuleIdx(ctx.BasicAuthByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+
+		for idx, basicAuth := range ctx.BasicAuthByRuleIdx {
+			sectionName := e.getSectionName(ctx, idx)
+			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+
+			secretRef := &kgateway.SecretReference{
+				Name: gatewayv1.ObjectName(basicAuth.Secret.Name),
+				Key:  ptr.To(basicAuth.SecretKey),
+			}
+			if basicAuth.SecretKey == "" || basicAuth.Secret.Namespace != ctx.Namespace {
+				secretKey := types.NamespacedName{
+					Namespace: ctx.Namespace,
+					Name:      fmt.Sprintf("%s-htpasswd", trafficPolicy.Name),
+				}
+				secretRef = &kgateway.SecretReference{
+					Name: gatewayv1.ObjectName(secretKey.Name),
+					Key:  ptr.To(utils.HtpasswdSecretKey),
+				}
+				if basicAuth.Users == nil {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("Secret %s must be created with the credentials of Secret %s in htpasswd format in its %q key", secretKey, basicAuth.Secret, utils.HtpasswdSecretKey),
+						trafficPolicy)
+				} else if _, exists := e.builderMap.Secrets[secretKey]; !exists {
+					e.builderMap.Secrets[secretKey] = utils.BuildHtpasswdSecret(secretKey.Namespace, secretKey.Name, basicAuth.Users)
+				}
+			}
+			trafficPolicy.Spec.BasicAuth = &kgateway.BasicAuthPolicy{SecretRef: secretRef}
+
+			// The passwords are only known when the users of the Secret were parsed.
+			if len(basicAuth.Users) > 0 {
+				if nonSHA := utils.NonSHAHtpasswdUsers(basicAuth.Users); len(nonSHA) > 0 {
+					e.notify(notifications.WarningNotification,
+						fmt.Sprintf("kgateway only supports SHA hashed passwords, the passwords of %s in Secret %s must be hashed again", strings.Join(nonSHA, ", "), basicAuth.Secret),
+						trafficPolicy)
+				}
+			}
+			if basicAuth.Realm != "" {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("The basic authentication realm %q can't be set", basicAuth.Realm),
+					trafficPolicy)
+			}
+		}
+
+		// mark Basic Auth IR as processed
+		ctx.BasicAuthByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -21,34 +21,59 @@ import (
 
 	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
-	envoygateway_emitter "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/envoygateway"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-// EmitBuffer processes BodySizeByRuleIdx from emitterIR and creates TrafficPolicies with buffer configuration.
+// EmitBuffer processes BufferingByRuleIdx from emitterIR and creates TrafficPolicies with buffer
+// configuration.
 func (e *Emitter) EmitBuffer(ir emitterir.EmitterIR) {
-	for _, ctx := range ir.HTTPRoutes {
-		envoygateway_emitter.MergeBodySizeIR(&ctx)
+	// The rules of a source share its warnings, which are reported once.
+	reported := map[string]struct{}{}
+	warn := func(message string, obj client.Object) {
+		if _, found := reported[message]; !found {
+			reported[message] = struct{}{}
+			e.notify(notifications.WarningNotification, message, obj)
+		}
+	}
 
-		for idx, bs := range ctx.BodySizeByRuleIdx {
-			if bs == nil {
-				continue
-			}
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.BufferingByRuleIdx == nil {
+			continue
+		}
 
-			sectionName := e.getSectionName(ctx, idx)
-			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+		ctx.BufferingByRuleIdx = utils.MergeByRuleIdx(ctx.BufferingByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
 
-			bufferVal := e.selectBufferValue(bs, &ctx.HTTPRoute)
-			if bufferVal == nil {
+		for idx, b := range ctx.BufferingByRuleIdx {
+			if b == nil {
 				continue
 			}
+			for _, message := range utils.UnsupportedBufferingMessages(b, "kgateway") {
+				warn(message, &ctx.HTTPRoute)
+			}
 
-			trafficPolicy.Spec.Buffer = &kgateway.Buffer{
-				MaxRequestSize: bufferVal,
+			sectionName := e.getSectionName(ctx, idx)
+			bufferVal := e.selectBufferValue(b, &ctx.HTTPRoute)
+			// Requests are streamed without the buffer filter, which limits their body size.
+			if bufferVal != nil && !ptr.Deref(b.RequestBuffering, true) {
+				warn(fmt.Sprintf("Requests of %s are not buffered, so their body size of %s is not limited", b.Metadata.Source(), bufferVal.String()), &ctx.HTTPRoute)
+				bufferVal = nil
+			}
+			if bufferVal != nil {
+				trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+				trafficPolicy.Spec.Buffer = &kgateway.Buffer{
+					MaxRequestSize: bufferVal,
+				}
 			}
 		}
+
+		// mark Buffer IR as processed
+		ctx.BufferingByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
 	}
 }
 
@@ -61,7 +86,7 @@ func (e *Emitter) getSectionName(ctx emitterir.HTTPRouteContext, idx int) *gatew
 }
 
 // selectBufferValue selects the buffer value, preferring MaxSize over BufferSize, and emits a warning if both are present.
-func (e *Emitter) selectBufferValue(bs *emitterir.BodySize, httpRoute *gatewayv1.HTTPRoute) *resource.Quantity {
+func (e *Emitter) selectBufferValue(bs *emitterir.Buffering, httpRoute *gatewayv1.HTTPRoute) *resource.Quantity {
 	if bs.MaxSize != nil {
 		if bs.BufferSize != nil {
 			e.notify(
@@ -21,6 +21,7 @@ import (
 
 	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
 	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/shared"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -29,12 +30,18 @@ import (
 )
 
 type BuilderMap struct {
-	TrafficPolicies map[types.NamespacedName]*kgateway.TrafficPolicy
+	TrafficPolicies       map[types.NamespacedName]*kgateway.TrafficPolicy
+	BackendConfigPolicies map[types.NamespacedName]*kgateway.BackendConfigPolicy
+	GatewayExtensions     map[types.NamespacedName]*kgateway.GatewayExtension
+	Secrets               map[types.NamespacedName]*corev1.Secret
 }
 
 func NewBuilderMap() *BuilderMap {
 	return &BuilderMap{
-		TrafficPolicies: make(map[types.NamespacedName]*kgateway.TrafficPolicy),
+		TrafficPolicies:       make(map[types.NamespacedName]*kgateway.TrafficPolicy),
+		BackendConfigPolicies: make(map[types.NamespacedName]*kgateway.BackendConfigPolicy),
+		GatewayExtensions:     make(map[types.NamespacedName]*kgateway.GatewayExtension),
+		Secrets:               make(map[types.NamespacedName]*corev1.Secret),
 	}
 }
 
@@ -75,3 +82,65 @@ func (e *Emitter) getOrBuildTrafficPolicy(ctx emitterir.HTTPRouteContext, sectio
 	e.builderMap.TrafficPolicies[key] = trafficPolicy
 	return trafficPolicy
 }
+
+func (e *Emitter) getOrBuildBackendConfigPolicy(service types.NamespacedName) *kgateway.BackendConfigPolicy {
+	policy, exist := e.builderMap.BackendConfigPolicies[service]
+	if exist {
+		return policy
+	}
+
+	backendConfigPolicy := &kgateway.BackendConfigPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+		},
+		Spec: kgateway.BackendConfigPolicySpec{
+			TargetRefs: []shared.LocalPolicyTargetReference{
+				{
+					Group: gatewayv1.Group(""),
+					Kind:  gatewayv1.Kind("Service"),
+					Name:  gatewayv1.ObjectName(service.Name),
+				},
+			},
+		},
+	}
+	backendConfigPolicy.SetGroupVersionKind(BackendConfigPolicyGVK)
+
+	e.builderMap.BackendConfigPolicies[service] = backendConfigPolicy
+	return backendConfigPolicy
+}
+
+// getOrBuildListenerTrafficPolicy returns the TrafficPolicy targeting the listener of the Gateway.
+func (e *Emitter) getOrBuildListenerTrafficPolicy(gateway gatewayv1.Gateway, sectionName gatewayv1.SectionName) *kgateway.TrafficPolicy {
+	key := types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s", gateway.Name, sectionName),
+		Namespace: gateway.Namespace,
+	}
+	policy, exist := e.builderMap.TrafficPolicies[key]
+	if exist {
+		return policy
+	}
+
+	trafficPolicy := &kgateway.TrafficPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+		Spec: kgateway.TrafficPolicySpec{
+			TargetRefs: []shared.LocalPolicyTargetReferenceWithSectionName{
+				{
+					LocalPolicyTargetReference: shared.LocalPolicyTargetReference{
+						Group: gatewayv1.Group("gateway.networking.k8s.io"),
+						Kind:  gatewayv1.Kind("Gateway"),
+						Name:  gatewayv1.ObjectName(gateway.Name),
+					},
+					SectionName: &sectionName,
+				},
+			},
+		},
+	}
+	trafficPolicy.SetGroupVersionKind(TrafficPolicyGVK)
+
+	e.builderMap.TrafficPolicies[key] = trafficPolicy
+	return trafficPolicy
+}
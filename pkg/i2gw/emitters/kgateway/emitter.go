@@ -17,6 +17,7 @@ limitations under the License.
 package kgateway
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
@@ -70,12 +71,29 @@ func (e *Emitter) Emit(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.Err
 // ToKgatewayResources processes emitterIR and adds kgateway-specific extensions to gatewayResources
 func (e *Emitter) ToKgatewayResources(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
 	e.EmitBuffer(ir)
-
-	// Collect all TrafficPolicies and convert to unstructured
+	e.EmitExternalAuth(ir, gwResources)
+	e.EmitBasicAuth(ir)
+	e.EmitRateLimit(ir)
+	e.EmitRetry(ir)
+	e.EmitLoadBalancer(ir)
+	e.EmitSessionAffinity(ir)
+	e.EmitListenerTLSOptions(ir, gwResources)
+	e.EmitRegexPathRewrite(ir, gwResources)
+
+	// Collect all TrafficPolicies, BackendConfigPolicies, GatewayExtensions and Secrets and convert to unstructured
 	var kgatewayObjs []client.Object
 	for _, trafficPolicy := range e.builderMap.TrafficPolicies {
 		kgatewayObjs = append(kgatewayObjs, trafficPolicy)
 	}
+	for _, backendConfigPolicy := range e.builderMap.BackendConfigPolicies {
+		kgatewayObjs = append(kgatewayObjs, backendConfigPolicy)
+	}
+	for _, gatewayExtension := range e.builderMap.GatewayExtensions {
+		kgatewayObjs = append(kgatewayObjs, gatewayExtension)
+	}
+	for _, secret := range e.builderMap.Secrets {
+		kgatewayObjs = append(kgatewayObjs, secret)
+	}
 
 	// Sort by Kind, then Namespace, then Name to make output deterministic for testing
 	sort.SliceStable(kgatewayObjs, func(i, j int) bool {
@@ -101,7 +119,7 @@ func (e *Emitter) ToKgatewayResources(ir emitterir.EmitterIR, gwResources *i2gw.
 	for _, obj := range kgatewayObjs {
 		u, err := i2gw.CastToUnstructured(obj)
 		if err != nil {
-			e.notify(notifications.ErrorNotification, "Failed to cast TrafficPolicy to unstructured", obj)
+			e.notify(notifications.ErrorNotification, fmt.Sprintf("Failed to cast %s to unstructured", obj.GetObjectKind().GroupVersionKind().Kind), obj)
 			continue
 		}
 		gwResources.GatewayExtensions = append(gwResources.GatewayExtensions, *u)
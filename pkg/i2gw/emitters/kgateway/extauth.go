@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/shared"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitExternalAuth processes ExternalAuthByRuleIdx from emitterIR and creates ExtAuth GatewayExtensions
+// referenced by the TrafficPolicies of the matching rules.
+func (e *Emitter) EmitExternalAuth(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.ExternalAuthByRuleIdx == nil {
+			continue
+		}
+
+		ctx.ExternalAuthByRuleIdx = utils.MergeByRuleIdx(ctx.ExternalAuthByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+
+		for idx, extAuth := range ctx.ExternalAuthByRuleIdx {
+			if extAuth.Service == nil {
+				e.notify(notifications.ErrorNotification,
+					fmt.Sprintf("auth-url %q doesn't point to an in-cluster Service (<service>.<namespace>.svc), external authentication can't be converted", extAuth.URL),
+					&ctx.HTTPRoute)
+				continue
+			}
+
+			sectionName := e.getSectionName(ctx, idx)
+			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+			gatewayExtension := e.buildExtAuthGatewayExtension(trafficPolicy, extAuth)
+			trafficPolicy.Spec.ExtAuth = &kgateway.ExtAuthPolicy{
+				ExtensionRef: &shared.NamespacedObjectReference{
+					Name: gatewayv1.ObjectName(gatewayExtension.Name),
+				},
+			}
+			utils.AddServiceReferenceGrant(gwResources, gatewayv1.Group(GatewayExtensionGVK.Group), gatewayv1.Kind(GatewayExtensionGVK.Kind), ctx.Namespace, *extAuth.Service)
+
+			e.notifyExternalAuthDifferences(extAuth, gatewayExtension)
+		}
+
+		// mark External Auth IR as processed
+		ctx.ExternalAuthByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
+
+// buildExtAuthGatewayExtension builds the ExtAuth GatewayExtension used by the given TrafficPolicy.
+func (e *Emitter) buildExtAuthGatewayExtension(trafficPolicy *kgateway.TrafficPolicy, extAuth *emitterir.ExternalAuth) *kgateway.GatewayExtension {
+	httpService := &kgateway.ExtHttpService{
+		BackendRef: gatewayv1.BackendRef{BackendObjectReference: *extAuth.Service},
+	}
+	if extAuth.Path != "" && extAuth.Path != "/" {
+		httpService.PathPrefix = extAuth.Path
+	}
+	if len(extAuth.ResponseHeaders) > 0 {
+		httpService.AuthorizationResponse = &kgateway.AuthorizationResponse{
+			HeadersToBackend: extAuth.ResponseHeaders,
+		}
+	}
+
+	gatewayExtension := &kgateway.GatewayExtension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-ext-auth", trafficPolicy.Name),
+			Namespace: trafficPolicy.Namespace,
+		},
+		Spec: kgateway.GatewayExtensionSpec{
+			Type: ptr.To(kgateway.GatewayExtensionTypeExtAuth),
+			ExtAuth: &kgateway.ExtAuthProvider{
+				HttpService:      httpService,
+				HeadersToForward: extAuth.RequestHeaders,
+			},
+		},
+	}
+	gatewayExtension.SetGroupVersionKind(GatewayExtensionGVK)
+
+	e.builderMap.GatewayExtensions[types.NamespacedName{Namespace: gatewayExtension.Namespace, Name: gatewayExtension.Name}] = gatewayExtension
+	return gatewayExtension
+}
+
+func (e *Emitter) notifyExternalAuthDifferences(extAuth *emitterir.ExternalAuth, gatewayExtension *kgateway.GatewayExtension) {
+	if extAuth.Path != "" && extAuth.Path != "/" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("kgateway prefixes the original request path with the auth-url path %q, the authentication service may need to be adjusted", extAuth.Path),
+			gatewayExtension)
+	}
+	if extAuth.Method != "" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("auth-method %q can't be set, the authentication request keeps the method of the original request", extAuth.Method),
+			gatewayExtension)
+	}
+	if extAuth.RequestHeaders == nil {
+		e.notify(notifications.WarningNotification,
+			"Only the Host, Method, Path, Content-Length and Authorization headers are sent to the authentication service, list any other needed header (e.g. Cookie) in headersToForward",
+			gatewayExtension)
+	}
+	if extAuth.SigninURL != "" {
+		e.notify(notifications.WarningNotification,
+			fmt.Sprintf("auth-signin %q is not supported, unauthenticated requests are not redirected", extAuth.SigninURL),
+			gatewayExtension)
+	}
+	if extAuth.CacheKey != "" || extAuth.CacheDuration != "" {
+		e.notify(notifications.WarningNotification,
+			"auth-cache-key and auth-cache-duration are not supported, authentication responses are not cached",
+			gatewayExtension)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// pathHeader is the pseudo-header holding the request URI, including its query string.
+const pathHeader = ":path"
+
+// EmitLoadBalancer processes the LoadBalancer of Services from emitterIR and creates
+// BackendConfigPolicies targeting the Services. Consistent hashing uses a ring hash,
+// like ingress-nginx.
+func (e *Emitter) EmitLoadBalancer(ir emitterir.EmitterIR) {
+	for svcKey, svc := range ir.Services {
+		if svc.LoadBalancer == nil {
+			continue
+		}
+
+		loadBalancer := &kgateway.LoadBalancer{}
+		switch svc.LoadBalancer.Algorithm {
+		case emitterir.LoadBalancerRoundRobin:
+			loadBalancer.RoundRobin = &kgateway.LoadBalancerRoundRobinConfig{}
+		case emitterir.LoadBalancerLeastRequest:
+			loadBalancer.LeastRequest = &kgateway.LoadBalancerLeastRequestConfig{}
+		case emitterir.LoadBalancerConsistentHash:
+			hashPolicy := kgateway.HashPolicy{}
+			switch svc.LoadBalancer.HashKey.Type {
+			case emitterir.LoadBalancerHashSourceIP:
+				hashPolicy.SourceIP = &kgateway.SourceIP{}
+			case emitterir.LoadBalancerHashHeader:
+				hashPolicy.Header = &kgateway.Header{Name: svc.LoadBalancer.HashKey.Name}
+			case emitterir.LoadBalancerHashCookie:
+				hashPolicy.Cookie = &kgateway.Cookie{Name: svc.LoadBalancer.HashKey.Name}
+			case emitterir.LoadBalancerHashURI:
+				hashPolicy.Header = &kgateway.Header{Name: pathHeader}
+			default:
+				continue
+			}
+			loadBalancer.RingHash = &kgateway.LoadBalancerRingHashConfig{
+				HashPolicies: []kgateway.HashPolicy{hashPolicy},
+			}
+		default:
+			continue
+		}
+
+		backendConfigPolicy := e.getOrBuildBackendConfigPolicy(svcKey)
+		backendConfigPolicy.Spec.LoadBalancer = loadBalancer
+
+		// mark LoadBalancer IR as processed
+		svc.LoadBalancer = nil
+		ir.Services[svcKey] = svc
+	}
+}
+
+// EmitSessionAffinity processes the cookie SessionAffinity of Services from emitterIR and
+// sets a ring hash of the cookie on the BackendConfigPolicies targeting the Services. Like
+// in ingress-nginx, session affinity takes precedence over the load balancing algorithm.
+func (e *Emitter) EmitSessionAffinity(ir emitterir.EmitterIR) {
+	for svcKey, svc := range ir.Services {
+		if svc.SessionAffinity == nil || svc.SessionAffinity.Type != "Cookie" {
+			continue
+		}
+		sessionAffinity := svc.SessionAffinity
+
+		// A session cookie is generated when the TTL is zero.
+		cookie := &kgateway.Cookie{
+			Name: sessionAffinity.CookieName,
+			TTL:  &metav1.Duration{},
+		}
+		if sessionAffinity.CookieTTLSec != nil {
+			cookie.TTL.Duration = time.Duration(*sessionAffinity.CookieTTLSec) * time.Second
+		}
+		if sessionAffinity.CookiePath != "" {
+			cookie.Path = ptr.To(sessionAffinity.CookiePath)
+		}
+		if sessionAffinity.CookieSameSite != "" {
+			cookie.SameSite = ptr.To(sessionAffinity.CookieSameSite)
+		}
+		if sessionAffinity.CookieSecure {
+			cookie.Secure = ptr.To(true)
+		}
+
+		backendConfigPolicy := e.getOrBuildBackendConfigPolicy(svcKey)
+		if backendConfigPolicy.Spec.LoadBalancer != nil {
+			e.notify(notifications.WarningNotification,
+				fmt.Sprintf("Service %s uses session affinity, its load balancing is ignored", svcKey),
+				backendConfigPolicy)
+		}
+		if sessionAffinity.Mode == emitterir.SessionAffinityPersistent {
+			e.notify(notifications.WarningNotification,
+				fmt.Sprintf("Sessions of Service %s are hashed to its endpoints, so some of them move when the endpoints change, unlike with the persistent affinity mode from %s", svcKey, sessionAffinity.Metadata.Source()),
+				backendConfigPolicy)
+		}
+		backendConfigPolicy.Spec.LoadBalancer = &kgateway.LoadBalancer{
+			RingHash: &kgateway.LoadBalancerRingHashConfig{
+				HashPolicies: []kgateway.HashPolicy{{Cookie: cookie}},
+			},
+		}
+
+		// mark SessionAffinity IR as processed
+		svc.SessionAffinity = nil
+		ir.Services[svcKey] = svc
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+)
+
+// EmitRegexPathRewrite processes the path rewrites with capture group references from
+// emitterIR and creates TrafficPolicies replacing the regex match of the path.
+func (e *Emitter) EmitRegexPathRewrite(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	for nn, ctx := range ir.HTTPRoutes {
+		httpRoute, ok := gwResources.HTTPRoutes[nn]
+		if !ok {
+			continue
+		}
+
+		for idx, rewrite := range ctx.PathRewriteByRuleIdx {
+			if rewrite == nil || rewrite.Regex == nil || idx >= len(httpRoute.Spec.Rules) {
+				continue
+			}
+
+			sectionName := e.getSectionName(ctx, idx)
+			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+			trafficPolicy.Spec.UrlRewrite = &kgateway.URLRewrite{
+				PathRegex: &kgateway.PathRegexRewrite{
+					Pattern:      rewrite.Regex.Pattern,
+					Substitution: rewrite.Regex.Substitution,
+				},
+			}
+			if headersFilter := rewrite.HeadersFilter(); headersFilter != nil {
+				httpRoute.Spec.Rules[idx].Filters = append(httpRoute.Spec.Rules[idx].Filters, *headersFilter)
+			}
+
+			// mark Path Rewrite IR as processed
+			ctx.PathRewriteByRuleIdx[idx] = nil
+		}
+
+		gwResources.HTTPRoutes[nn] = httpRoute
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// EmitRateLimit processes RateLimitByRuleIdx from emitterIR and creates TrafficPolicies with local rate limits.
+func (e *Emitter) EmitRateLimit(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.RateLimitByRuleIdx == nil {
+			continue
+		}
+
+		ctx.RateLimitByRuleIdx = utils.MergeByRuleIdx(ctx.RateLimitByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+
+		for idx, rateLimit := range ctx.RateLimitByRuleIdx {
+			// A route has a single token bucket, the limit per second takes precedence.
+			requests, fillInterval := rateLimit.RequestsPerSecond, time.Second
+			if requests == 0 {
+				requests, fillInterval = rateLimit.RequestsPerMinute, time.Minute
+			}
+			if requests == 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Limiting the concurrent connections of each client to %d is not supported", rateLimit.Connections),
+					&ctx.HTTPRoute)
+				continue
+			}
+
+			sectionName := e.getSectionName(ctx, idx)
+			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+
+			burstMultiplier := max(rateLimit.BurstMultiplier, 1)
+			trafficPolicy.Spec.RateLimit = &kgateway.RateLimit{
+				Local: &kgateway.LocalRateLimitPolicy{
+					TokenBucket: &kgateway.TokenBucket{
+						MaxTokens:     requests * burstMultiplier,
+						TokensPerFill: ptr.To(requests),
+						FillInterval:  metav1.Duration{Duration: fillInterval},
+					},
+				},
+			}
+
+			e.notify(notifications.WarningNotification,
+				"kgateway local rate limits are shared by all the clients of the route and enforced by each replica, while ingress-nginx limits each client IP address in each controller pod",
+				trafficPolicy)
+			if rateLimit.RequestsPerSecond > 0 && rateLimit.RequestsPerMinute > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Only the limit of %d requests per second is applied, the limit of %d requests per minute is ignored", rateLimit.RequestsPerSecond, rateLimit.RequestsPerMinute),
+					trafficPolicy)
+			}
+			if rateLimit.Connections > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Limiting the concurrent connections of each client to %d is not supported", rateLimit.Connections),
+					trafficPolicy)
+			}
+			if len(rateLimit.AllowList) > 0 {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Clients from %s can't be excluded from rate limiting", strings.Join(rateLimit.AllowList, ", ")),
+					trafficPolicy)
+			}
+		}
+
+		// mark Rate Limit IR as processed
+		ctx.RateLimitByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/kgateway"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitRetry processes RetryByRuleIdx from emitterIR and creates TrafficPolicies with retries.
+func (e *Emitter) EmitRetry(ir emitterir.EmitterIR) {
+	for nn, ctx := range ir.HTTPRoutes {
+		if ctx.RetryByRuleIdx == nil {
+			continue
+		}
+
+		ctx.RetryByRuleIdx = utils.MergeByRuleIdx(ctx.RetryByRuleIdx, len(ctx.Spec.Rules), RouteRuleAllIndex)
+
+		for idx, retry := range ctx.RetryByRuleIdx {
+			sectionName := e.getSectionName(ctx, idx)
+			trafficPolicy := e.getOrBuildTrafficPolicy(ctx, sectionName, idx)
+
+			trafficPolicy.Spec.Retry = &kgateway.Retry{}
+			if retry.Attempts != nil {
+				trafficPolicy.Spec.Retry.Attempts = *retry.Attempts
+			}
+			// Timed out requests are reset, so both conditions retry resets.
+			if slices.Contains(retry.Conditions, emitterir.RetryOnConnectFailure) {
+				trafficPolicy.Spec.Retry.RetryOn = append(trafficPolicy.Spec.Retry.RetryOn, "connect-failure")
+			}
+			if len(retry.Conditions) > 0 {
+				trafficPolicy.Spec.Retry.RetryOn = append(trafficPolicy.Spec.Retry.RetryOn, "reset")
+			}
+			for _, code := range retry.StatusCodes {
+				trafficPolicy.Spec.Retry.StatusCodes = append(trafficPolicy.Spec.Retry.StatusCodes, gatewayv1.HTTPRouteRetryStatusCode(code))
+			}
+			if retry.PerTryTimeout != nil {
+				if perTryTimeout, err := time.ParseDuration(string(*retry.PerTryTimeout)); err == nil {
+					trafficPolicy.Spec.Retry.PerTryTimeout = &metav1.Duration{Duration: perTryTimeout}
+				}
+			}
+
+			if retry.Timeout != nil {
+				e.notify(notifications.WarningNotification,
+					fmt.Sprintf("Retries from %s can't be limited to %s in total", retry.Metadata.Source(), *retry.Timeout),
+					trafficPolicy)
+			}
+		}
+
+		// mark Retry IR as processed
+		ctx.RetryByRuleIdx = nil
+		ir.HTTPRoutes[nn] = ctx
+	}
+}
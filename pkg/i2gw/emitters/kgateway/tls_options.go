@@ -0,0 +1,82 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kgateway
+
+import (
+	"strings"
+
+	"github.com/kgateway-dev/kgateway/v2/api/annotations"
+	"github.com/kgateway-dev/kgateway/v2/api/v1alpha1/shared"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/utils"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// EmitListenerTLSOptions converts the TLS options intent into the kgateway TLS
+// options of the listener, and into a TrafficPolicy targeting the listener which
+// sets the HSTS header on the responses of the listener.
+func (e *Emitter) EmitListenerTLSOptions(ir emitterir.EmitterIR, gwResources *i2gw.GatewayResources) {
+	for nn, ctx := range ir.Gateways {
+		if ctx.TLSOptionsByListener == nil {
+			continue
+		}
+
+		gateway := gwResources.Gateways[nn]
+		for sectionName, options := range ctx.TLSOptionsByListener {
+			if options == nil {
+				continue
+			}
+
+			tlsOptions := map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{}
+			if options.MinVersion != "" {
+				tlsOptions[annotations.MinTLSVersion] = gatewayv1.AnnotationValue(options.MinVersion)
+			}
+			if options.MaxVersion != "" {
+				tlsOptions[annotations.MaxTLSVersion] = gatewayv1.AnnotationValue(options.MaxVersion)
+			}
+			if len(options.CipherSuites) > 0 {
+				tlsOptions[annotations.CipherSuites] = gatewayv1.AnnotationValue(strings.Join(options.CipherSuites, ","))
+			}
+			if len(options.ALPNProtocols) > 0 {
+				tlsOptions[annotations.AlpnProtocols] = gatewayv1.AnnotationValue(strings.Join(options.ALPNProtocols, ","))
+			}
+			if len(tlsOptions) > 0 {
+				utils.SetListenerTLSOptions(&gateway, sectionName, tlsOptions)
+			}
+
+			if options.HSTS != nil {
+				trafficPolicy := e.getOrBuildListenerTrafficPolicy(ctx.Gateway, sectionName)
+				if trafficPolicy.Spec.HeaderModifiers == nil {
+					trafficPolicy.Spec.HeaderModifiers = &shared.HeaderModifiers{}
+				}
+				if trafficPolicy.Spec.HeaderModifiers.Response == nil {
+					trafficPolicy.Spec.HeaderModifiers.Response = &gatewayv1.HTTPHeaderFilter{}
+				}
+				trafficPolicy.Spec.HeaderModifiers.Response.Set = append(trafficPolicy.Spec.HeaderModifiers.Response.Set, gatewayv1.HTTPHeader{
+					Name:  emitterir.HSTSHeader,
+					Value: options.HSTS.HeaderValue(),
+				})
+			}
+		}
+		gwResources.Gateways[nn] = gateway
+
+		// mark TLS options IR as processed
+		ctx.TLSOptionsByListener = nil
+		ir.Gateways[nn] = ctx
+	}
+}
@@ -30,4 +30,18 @@ var (
 		Version: "v1alpha1",
 		Kind:    "TrafficPolicy",
 	}
+
+	// BackendConfigPolicyGVK is the GroupVersionKind for BackendConfigPolicy.
+	BackendConfigPolicyGVK = schema.GroupVersionKind{
+		Group:   "gateway.kgateway.dev",
+		Version: "v1alpha1",
+		Kind:    "BackendConfigPolicy",
+	}
+
+	// GatewayExtensionGVK is the GroupVersionKind for GatewayExtension.
+	GatewayExtensionGVK = schema.GroupVersionKind{
+		Group:   "gateway.kgateway.dev",
+		Version: "v1alpha1",
+		Kind:    "GatewayExtension",
+	}
 )
@@ -0,0 +1,58 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HtpasswdSecretKey is the key holding the credentials of the Secrets built
+// by BuildHtpasswdSecret.
+const HtpasswdSecretKey = ".htpasswd"
+
+// BuildHtpasswdSecret builds an Opaque Secret holding the "user:hashed-password"
+// entries in htpasswd format.
+func BuildHtpasswdSecret(namespace, name string, users []string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			HtpasswdSecretKey: []byte(strings.Join(users, "\n") + "\n"),
+		},
+	}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secret
+}
+
+// NonSHAHtpasswdUsers returns the users whose password is not hashed with
+// SHA-1, the only hash supported by Envoy based implementations.
+func NonSHAHtpasswdUsers(users []string) []string {
+	var nonSHA []string
+	for _, entry := range users {
+		user, password, _ := strings.Cut(entry, ":")
+		if !strings.HasPrefix(password, "{SHA}") {
+			nonSHA = append(nonSHA, user)
+		}
+	}
+	return nonSHA
+}
@@ -18,6 +18,8 @@ package utils
 
 import (
 	"fmt"
+	"maps"
+	"reflect"
 	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
@@ -57,6 +59,9 @@ type uniqueBackendRefsKey struct {
 // removeBackendRefsDuplicates removes duplicate backendRefs from a list of backendRefs.
 func removeBackendRefsDuplicates(backendRefs []gatewayv1.HTTPBackendRef) []gatewayv1.HTTPBackendRef {
 	uniqueBackendRefs := map[uniqueBackendRefsKey]*gatewayv1.HTTPBackendRef{}
+	// keys keeps the order in which the backendRefs were first seen, so the
+	// result doesn't depend on map iteration order.
+	var keys []uniqueBackendRefsKey
 
 	for _, backendRef := range backendRefs {
 		var k uniqueBackendRefsKey
@@ -86,11 +91,12 @@ func removeBackendRefsDuplicates(backendRefs []gatewayv1.HTTPBackendRef) []gatew
 			}
 		} else {
 			uniqueBackendRefs[k] = backendRef.DeepCopy()
+			keys = append(keys, k)
 		}
 	}
 	result := make([]gatewayv1.HTTPBackendRef, 0, len(uniqueBackendRefs))
-	for _, backendRef := range uniqueBackendRefs {
-		result = append(result, *backendRef)
+	for _, k := range keys {
+		result = append(result, *uniqueBackendRefs[k])
 	}
 	return result
 }
@@ -111,6 +117,12 @@ func ToGatewayResources(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.Er
 	}
 	for key, gatewayContext := range ir.Gateways {
 		gatewayResources.Gateways[key] = gatewayContext.Gateway
+		if len(gatewayContext.Comments) > 0 {
+			if gatewayResources.GatewayComments == nil {
+				gatewayResources.GatewayComments = make(map[types.NamespacedName][]string)
+			}
+			gatewayResources.GatewayComments[key] = gatewayContext.Comments
+		}
 	}
 	for key, httpRouteContext := range ir.HTTPRoutes {
 		gatewayResources.HTTPRoutes[key] = httpRouteContext.HTTPRoute
@@ -141,11 +153,104 @@ func ToGatewayResources(ir emitterir.EmitterIR) (i2gw.GatewayResources, field.Er
 	for key, val := range ir.ReferenceGrants {
 		gatewayResources.ReferenceGrants[key] = val.ReferenceGrant
 	}
-	return gatewayResources, nil
+	var errs field.ErrorList
+	for key, val := range ir.ConfigMaps {
+		obj, err := i2gw.CastToUnstructured(&val.ConfigMap)
+		if err != nil {
+			errs = append(errs, field.InternalError(field.NewPath("ConfigMap", key.String()), err))
+			continue
+		}
+		gatewayResources.GatewayExtensions = append(gatewayResources.GatewayExtensions, *obj)
+	}
+	for key, val := range ir.ServicePatches {
+		if gatewayResources.ServicePatches == nil {
+			gatewayResources.ServicePatches = make(map[types.NamespacedName]corev1.Service)
+		}
+		gatewayResources.ServicePatches[key] = val.Service
+	}
+	return gatewayResources, errs
+}
+
+// SetListenerTLSOptions sets implementation-specific options in the TLS configuration
+// of the listener of the Gateway. It returns false when the listener isn't found.
+func SetListenerTLSOptions(gateway *gatewayv1.Gateway, sectionName gatewayv1.SectionName, options map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue) bool {
+	for i := range gateway.Spec.Listeners {
+		listener := &gateway.Spec.Listeners[i]
+		if listener.Name != sectionName {
+			continue
+		}
+		if listener.TLS == nil {
+			listener.TLS = &gatewayv1.ListenerTLSConfig{}
+		}
+		if listener.TLS.Options == nil {
+			listener.TLS.Options = make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, len(options))
+		}
+		maps.Copy(listener.TLS.Options, options)
+		return true
+	}
+	return false
+}
+
+// AddServiceReferenceGrant allows objects of the given kind in fromNamespace to
+// reference the Service. Nothing is added when the Service lives in fromNamespace.
+func AddServiceReferenceGrant(gwResources *i2gw.GatewayResources, fromGroup gatewayv1.Group, fromKind gatewayv1.Kind, fromNamespace string, service gatewayv1.BackendObjectReference) {
+	if service.Namespace == nil || string(*service.Namespace) == fromNamespace {
+		return
+	}
+	key := types.NamespacedName{
+		Namespace: string(*service.Namespace),
+		Name:      fmt.Sprintf("from-%s-%s-to-service-%s", fromNamespace, strings.ToLower(string(fromKind)), service.Name),
+	}
+	if _, ok := gwResources.ReferenceGrants[key]; ok {
+		return
+	}
+	referenceGrant := gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     fromGroup,
+				Kind:      fromKind,
+				Namespace: gatewayv1.Namespace(fromNamespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: "",
+				Kind:  "Service",
+				Name:  ptr.To(service.Name),
+			}},
+		},
+	}
+	referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	if gwResources.ReferenceGrants == nil {
+		gwResources.ReferenceGrants = make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant)
+	}
+	gwResources.ReferenceGrants[key] = referenceGrant
 }
 
 func LogUnparsedErrors(ir emitterir.EmitterIR, notify notifications.NotifyFunc) {
-	// currently, we only really have unparsed errors in the HTTPRouteContext, but we can expand this function as needed if we have unparsed errors in other contexts in the future.
+	for _, gatewayContext := range ir.Gateways {
+		for _, unparsedExtension := range gatewayContext.UnparsedExtensions() {
+			if unparsedExtension == nil {
+				continue
+			}
+			source := unparsedExtension.Source()
+			paths := strings.Builder{}
+			for _, p := range unparsedExtension.Paths() {
+				paths.WriteString(p.String())
+				paths.WriteString(", ")
+			}
+
+			message := unparsedExtension.FailureMessage()
+
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Failed to apply %s from %s: %s", strings.TrimSuffix(paths.String(), ", "), source, message),
+				&gatewayContext.Gateway,
+			)
+		}
+	}
+
 	for _, httpRouteContext := range ir.HTTPRoutes {
 		for _, unparsedExtension := range httpRouteContext.UnparsedExtensions() {
 			if unparsedExtension == nil {
@@ -201,3 +306,43 @@ func LogUnparsedErrors(ir emitterir.EmitterIR, notify notifications.NotifyFunc)
 		}
 	}
 }
+
+// MergeByRuleIdx returns the intents of the rules of a route stored at allRulesIdx
+// when every rule has an equal intent, so that a single policy targets the whole
+// route. Otherwise, the intents are returned unchanged.
+func MergeByRuleIdx[T any](m map[int]*T, numRules int, allRulesIdx int) map[int]*T {
+	if len(m) != numRules {
+		return m
+	}
+
+	var first *T
+	for _, v := range m {
+		if first == nil {
+			first = v
+			continue
+		}
+		if !reflect.DeepEqual(first, v) {
+			return m
+		}
+	}
+
+	return map[int]*T{
+		allRulesIdx: first,
+	}
+}
+
+// UnsupportedBufferingMessages returns the warnings about the buffering intent which
+// Envoy-based implementations can't apply.
+func UnsupportedBufferingMessages(b *emitterir.Buffering, implementation string) []string {
+	var messages []string
+	if ptr.Deref(b.ResponseBuffering, false) {
+		messages = append(messages, fmt.Sprintf("Responses of %s are buffered by ingress-nginx, %s streams them to the clients", b.Metadata.Source(), implementation))
+	}
+	if b.ResponseBufferSize != nil {
+		messages = append(messages, fmt.Sprintf("The response buffer size of %s (%s) is not converted, %s has no buffer for the response headers of the backends", b.Metadata.Source(), b.ResponseBufferSize.String(), implementation))
+	}
+	if b.HTTPVersion == "1.0" {
+		messages = append(messages, fmt.Sprintf("Requests of %s are sent to the backends with HTTP/1.0 by ingress-nginx, %s sends them with HTTP/1.1", b.Metadata.Source(), implementation))
+	}
+	return messages
+}
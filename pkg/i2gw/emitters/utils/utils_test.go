@@ -0,0 +1,59 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+func TestUnsupportedBufferingMessages(t *testing.T) {
+	metadata := emitterir.NewExtensionFeatureMetadata("default/ing", nil, "")
+
+	tests := []struct {
+		name      string
+		buffering *emitterir.Buffering
+		want      []string
+	}{
+		{
+			name:      "request buffering and HTTP/1.1 are supported",
+			buffering: &emitterir.Buffering{Metadata: metadata, RequestBuffering: ptr.To(true), ResponseBuffering: ptr.To(false), HTTPVersion: "1.1"},
+		},
+		{
+			name:      "response buffering, response buffer size and HTTP/1.0 are not supported",
+			buffering: &emitterir.Buffering{Metadata: metadata, ResponseBuffering: ptr.To(true), ResponseBufferSize: ptr.To(resource.MustParse("16Ki")), HTTPVersion: "1.0"},
+			want: []string{
+				"Responses of default/ing are buffered by ingress-nginx, Envoy Gateway streams them to the clients",
+				"The response buffer size of default/ing (16Ki) is not converted, Envoy Gateway has no buffer for the response headers of the backends",
+				"Requests of default/ing are sent to the backends with HTTP/1.0 by ingress-nginx, Envoy Gateway sends them with HTTP/1.1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnsupportedBufferingMessages(tt.buffering, "Envoy Gateway")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnsupportedBufferingMessages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
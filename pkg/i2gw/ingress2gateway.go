@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 
 	common_emitter "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitters/common_emitter"
@@ -39,41 +40,76 @@ const GeneratorAnnotationKey = "gateway.networking.k8s.io/generator"
 // Examples: "v0.4.0", "v0.4.0-5-gabcdef", "v0.4.0-5-gabcdef-dirty"
 var Version = "dev" // Default value if not built with linker flags
 
-func ToGatewayAPIResources(ctx context.Context, namespace string, reader io.Reader, providers []string, emitterName string, providerSpecificFlags map[string]map[string]string, allowExperimentalGatewayAPI bool, noColor bool) ([]GatewayResources, *notifications.Report, error) {
-	var clusterClient client.Client
+func ToGatewayAPIResources(ctx context.Context, namespace string, reader io.Reader, providers []string, emitterName string, providerSpecificFlags map[string]map[string]string, allowExperimentalGatewayAPI bool, gatewayAddressMode common_emitter.GatewayAddressMode, noColor bool) (GatewayResources, *notifications.Report, error) {
+	var clusterClient, clusterWideClient client.Client
 
 	if reader == nil {
 		conf, err := config.GetConfig()
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get client config: %w", err)
+			return GatewayResources{}, nil, fmt.Errorf("failed to get client config: %w", err)
 		}
 
 		cl, err := client.New(conf, client.Options{})
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create client: %w", err)
+			return GatewayResources{}, nil, fmt.Errorf("failed to create client: %w", err)
 		}
 		clusterClient = client.NewNamespacedClient(cl, namespace)
+		clusterWideClient = cl
 	}
 
 	report := notifications.NewReport(noColor)
 
+	if slices.Contains(providers, AutoProviders) {
+		var input DetectionInput
+		var err error
+		if reader != nil {
+			data, readErr := io.ReadAll(reader)
+			if readErr != nil {
+				return GatewayResources{}, nil, fmt.Errorf("failed to read input manifests: %w", readErr)
+			}
+			reader = bytes.NewReader(data)
+			input, err = readDetectionInputFromFile(data, namespace)
+		} else {
+			input, err = readDetectionInputFromCluster(ctx, clusterClient, report.Notifier(detectionNotifierName))
+		}
+		if err != nil {
+			return GatewayResources{}, nil, fmt.Errorf("failed to read input for provider detection: %w", err)
+		}
+
+		providers = DetectProviders(input, report.Notifier(detectionNotifierName))
+		if len(providers) == 0 {
+			return GatewayResources{}, report, fmt.Errorf("--providers=%s could not detect any provider from the input, use --providers to select them explicitly", AutoProviders)
+		}
+
+		// The gce provider requires the gce emitter, mirroring the check done
+		// when --providers=gce is passed explicitly.
+		if slices.Contains(providers, "gce") && emitterName != "gce" {
+			if emitterName != "standard" {
+				return GatewayResources{}, report, fmt.Errorf("the gce provider was detected, which requires the 'gce' emitter (got '%s')", emitterName)
+			}
+			emitterName = "gce"
+			report.Notifier(detectionNotifierName)(notifications.InfoNotification, "Using the gce emitter since the gce provider was detected")
+		}
+	}
+
 	providerByName, err := constructProviders(&ProviderConf{
 		Client:                clusterClient,
+		ClusterClient:         clusterWideClient,
 		Namespace:             namespace,
 		ProviderSpecificFlags: providerSpecificFlags,
 		Report:                report,
 	}, providers)
 	if err != nil {
-		return nil, nil, err
+		return GatewayResources{}, nil, err
 	}
 
 	if reader != nil {
 		if err = readProviderResourcesFromFile(ctx, providerByName, reader); err != nil {
-			return nil, nil, err
+			return GatewayResources{}, nil, err
 		}
 	} else {
 		if err = readProviderResourcesFromCluster(ctx, providerByName); err != nil {
-			return nil, nil, err
+			return GatewayResources{}, nil, err
 		}
 	}
 
@@ -83,19 +119,22 @@ func ToGatewayAPIResources(ctx context.Context, namespace string, reader io.Read
 	}
 	newEmitterFunc, ok := EmitterConstructorByName[EmitterName(emitterName)]
 	if !ok {
-		return nil, nil, fmt.Errorf("%s is not a supported emitter", emitterName)
+		return GatewayResources{}, nil, fmt.Errorf("%s is not a supported emitter", emitterName)
 	}
 	emitter := newEmitterFunc(emitterConf)
+	sessionAffinityEmitter, ok := emitter.(ServiceSessionAffinityEmitter)
 	commonEmitter := common_emitter.NewEmitter(&common_emitter.EmitterConf{
 		AllowExperimentalGatewayAPI: emitterConf.AllowExperimentalGatewayAPI,
 		Report:                      report,
+		GatewayAddressMode:          gatewayAddressMode,
+		KeepServiceSessionAffinity:  ok && sessionAffinityEmitter.KeepsServiceSessionAffinity(),
 	})
 
 	var (
-		gatewayResources []GatewayResources
-		errs             field.ErrorList
+		gatewayResourcesByProvider = make(map[ProviderName]GatewayResources, len(providerByName))
+		errs                       field.ErrorList
 	)
-	for _, provider := range providerByName {
+	for name, provider := range providerByName {
 		ir, conversionErrs := provider.ToIR()
 		errs = append(errs, conversionErrs...)
 
@@ -104,13 +143,13 @@ func ToGatewayAPIResources(ctx context.Context, namespace string, reader io.Read
 
 		providerGatewayResources, conversionErrs := emitter.Emit(ir)
 		errs = append(errs, conversionErrs...)
-		gatewayResources = append(gatewayResources, providerGatewayResources)
+		gatewayResourcesByProvider[name] = providerGatewayResources
 	}
 	if len(errs) > 0 {
-		return nil, report, aggregatedErrs(errs)
+		return GatewayResources{}, report, aggregatedErrs(errs)
 	}
 
-	return gatewayResources, report, nil
+	return MergeGatewayResources(gatewayResourcesByProvider, report.Notifier(mergeNotifierName)), report, nil
 }
 
 func readProviderResourcesFromFile(ctx context.Context, providerByName map[ProviderName]Provider, reader io.Reader) error {
@@ -0,0 +1,247 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// mergeNotifierName is the source name used for merge notifications.
+const mergeNotifierName = "merge"
+
+// MergeGatewayResources combines the GatewayResources generated by each
+// provider into a single set of resources.
+//
+// Providers are merged in name order, so the result doesn't depend on map
+// iteration order. Objects with the same kind and NamespacedName are
+// de-duplicated when they are identical. Gateways that only differ in their
+// listeners are merged into one Gateway holding the listeners of all
+// providers. Any other difference is a conflict: it is reported as an error
+// notification and the object of the provider which comes first is kept. A
+// conflicting Gateway still gets the listeners of the later provider which
+// don't conflict, so that its routes keep a listener to attach to.
+func MergeGatewayResources(resourcesByProvider map[ProviderName]GatewayResources, notify notifications.NotifyFunc) GatewayResources {
+	merged := GatewayResources{
+		Gateways:           make(map[types.NamespacedName]gatewayv1.Gateway),
+		GatewayClasses:     make(map[types.NamespacedName]gatewayv1.GatewayClass),
+		HTTPRoutes:         make(map[types.NamespacedName]gatewayv1.HTTPRoute),
+		GRPCRoutes:         make(map[types.NamespacedName]gatewayv1.GRPCRoute),
+		TLSRoutes:          make(map[types.NamespacedName]gatewayv1.TLSRoute),
+		TCPRoutes:          make(map[types.NamespacedName]gatewayv1alpha2.TCPRoute),
+		UDPRoutes:          make(map[types.NamespacedName]gatewayv1alpha2.UDPRoute),
+		BackendTLSPolicies: make(map[types.NamespacedName]gatewayv1.BackendTLSPolicy),
+		ReferenceGrants:    make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant),
+		ServicePatches:     make(map[types.NamespacedName]corev1.Service),
+		GatewayComments:    make(map[types.NamespacedName][]string),
+	}
+
+	var (
+		gatewayOwners          = make(map[types.NamespacedName]ProviderName)
+		gatewayClassOwners     = make(map[types.NamespacedName]ProviderName)
+		httpRouteOwners        = make(map[types.NamespacedName]ProviderName)
+		grpcRouteOwners        = make(map[types.NamespacedName]ProviderName)
+		tlsRouteOwners         = make(map[types.NamespacedName]ProviderName)
+		tcpRouteOwners         = make(map[types.NamespacedName]ProviderName)
+		udpRouteOwners         = make(map[types.NamespacedName]ProviderName)
+		backendTLSPolicyOwners = make(map[types.NamespacedName]ProviderName)
+		referenceGrantOwners   = make(map[types.NamespacedName]ProviderName)
+		servicePatchOwners     = make(map[types.NamespacedName]ProviderName)
+		extensionOwners        = make(map[extensionKey]ProviderName)
+		extensionIndex         = make(map[extensionKey]int)
+	)
+
+	for _, provider := range slices.Sorted(maps.Keys(resourcesByProvider)) {
+		resources := resourcesByProvider[provider]
+
+		mergeObjects("Gateway", merged.Gateways, gatewayOwners, provider, resources.Gateways, mergeGateways, gatewayConflictMessage, notify)
+		mergeObjects("GatewayClass", merged.GatewayClasses, gatewayClassOwners, provider, resources.GatewayClasses, mergeIdentical, conflictMessage, notify)
+		mergeObjects("HTTPRoute", merged.HTTPRoutes, httpRouteOwners, provider, resources.HTTPRoutes, mergeIdentical, conflictMessage, notify)
+		mergeObjects("GRPCRoute", merged.GRPCRoutes, grpcRouteOwners, provider, resources.GRPCRoutes, mergeIdentical, conflictMessage, notify)
+		mergeObjects("TLSRoute", merged.TLSRoutes, tlsRouteOwners, provider, resources.TLSRoutes, mergeIdentical, conflictMessage, notify)
+		mergeObjects("TCPRoute", merged.TCPRoutes, tcpRouteOwners, provider, resources.TCPRoutes, mergeIdentical, conflictMessage, notify)
+		mergeObjects("UDPRoute", merged.UDPRoutes, udpRouteOwners, provider, resources.UDPRoutes, mergeIdentical, conflictMessage, notify)
+		mergeObjects("BackendTLSPolicy", merged.BackendTLSPolicies, backendTLSPolicyOwners, provider, resources.BackendTLSPolicies, mergeIdentical, conflictMessage, notify)
+		mergeObjects("ReferenceGrant", merged.ReferenceGrants, referenceGrantOwners, provider, resources.ReferenceGrants, mergeIdentical, conflictMessage, notify)
+		mergeObjects("Service", merged.ServicePatches, servicePatchOwners, provider, resources.ServicePatches, mergeIdentical, conflictMessage, notify)
+
+		for _, key := range slices.SortedFunc(maps.Keys(resources.GatewayComments), compareNamespacedNames) {
+			if comments := resources.GatewayComments[key]; !slices.Equal(merged.GatewayComments[key], comments) {
+				merged.GatewayComments[key] = append(merged.GatewayComments[key], comments...)
+			}
+		}
+
+		for _, extension := range resources.GatewayExtensions {
+			key := extensionKey{
+				GroupKind:      extension.GroupVersionKind().GroupKind(),
+				NamespacedName: types.NamespacedName{Namespace: extension.GetNamespace(), Name: extension.GetName()},
+			}
+			idx, found := extensionIndex[key]
+			if !found {
+				extensionIndex[key] = len(merged.GatewayExtensions)
+				extensionOwners[key] = provider
+				merged.GatewayExtensions = append(merged.GatewayExtensions, extension)
+				continue
+			}
+			if owner := extensionOwners[key]; !apiequality.Semantic.DeepEqual(merged.GatewayExtensions[idx].Object, extension.Object) {
+				notify(notifications.ErrorNotification,
+					conflictMessage(extension.GetKind(), key.NamespacedName, owner, provider, []string{"the objects differ"}),
+					&merged.GatewayExtensions[idx])
+			}
+		}
+	}
+
+	slices.SortStableFunc(merged.GatewayExtensions, func(a, b unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(a.GroupVersionKind().Group, b.GroupVersionKind().Group),
+			cmp.Compare(a.GetKind(), b.GetKind()),
+			cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+
+	return merged
+}
+
+// extensionKey identifies a Gateway extension regardless of its API version.
+type extensionKey struct {
+	schema.GroupKind
+	types.NamespacedName
+}
+
+// mergeFunc merges an incoming object into the existing object with the same
+// NamespacedName and returns the descriptions of the conflicts it found.
+type mergeFunc[T any] func(existing, incoming T) (T, []string)
+
+// conflictMessageFunc describes the conflicts found when merging an object and
+// how they were resolved.
+type conflictMessageFunc func(kind string, key types.NamespacedName, owner, provider ProviderName, conflicts []string) string
+
+// mergeObjects merges the objects of one kind generated by a provider into
+// the merged objects, reporting the conflicts with the providers merged
+// before it.
+func mergeObjects[T any, PT interface {
+	*T
+	client.Object
+}](kind string, merged map[types.NamespacedName]T, owners map[types.NamespacedName]ProviderName, provider ProviderName, objects map[types.NamespacedName]T, merge mergeFunc[T], message conflictMessageFunc, notify notifications.NotifyFunc) {
+	for _, key := range slices.SortedFunc(maps.Keys(objects), compareNamespacedNames) {
+		incoming := objects[key]
+		existing, found := merged[key]
+		if !found {
+			merged[key] = incoming
+			owners[key] = provider
+			continue
+		}
+
+		result, conflicts := merge(existing, incoming)
+		merged[key] = result
+		if len(conflicts) > 0 {
+			notify(notifications.ErrorNotification, message(kind, key, owners[key], provider, conflicts), PT(&result))
+		}
+	}
+}
+
+// mergeIdentical accepts the incoming object only if it is identical to the
+// existing one.
+func mergeIdentical[T any](existing, incoming T) (T, []string) {
+	if apiequality.Semantic.DeepEqual(existing, incoming) {
+		return existing, nil
+	}
+	return existing, []string{"the objects differ"}
+}
+
+// mergeGateways adds the listeners of the incoming Gateway to the existing
+// one. Listeners are matched by name, and a listener which differs from the
+// existing listener with the same name, or which collides with an existing
+// listener on port, protocol and hostname, is a conflict and is dropped.
+func mergeGateways(existing, incoming gatewayv1.Gateway) (gatewayv1.Gateway, []string) {
+	var conflicts []string
+
+	if existing.Spec.GatewayClassName != incoming.Spec.GatewayClassName {
+		conflicts = append(conflicts, fmt.Sprintf("gatewayClassName %q differs from %q", incoming.Spec.GatewayClassName, existing.Spec.GatewayClassName))
+	}
+
+	existingSpec := existing.Spec.DeepCopy()
+	incomingSpec := incoming.Spec.DeepCopy()
+	existingSpec.GatewayClassName, incomingSpec.GatewayClassName = "", ""
+	existingSpec.Listeners, incomingSpec.Listeners = nil, nil
+	if !apiequality.Semantic.DeepEqual(existingSpec, incomingSpec) {
+		conflicts = append(conflicts, "the Gateway specs differ in fields other than listeners")
+	}
+
+	result := *existing.DeepCopy()
+	for _, listener := range incoming.Spec.Listeners {
+		idx := slices.IndexFunc(result.Spec.Listeners, func(l gatewayv1.Listener) bool {
+			return l.Name == listener.Name
+		})
+		if idx != -1 {
+			if !apiequality.Semantic.DeepEqual(result.Spec.Listeners[idx], listener) {
+				conflicts = append(conflicts, fmt.Sprintf("listener %q differs", listener.Name))
+			}
+			continue
+		}
+
+		idx = slices.IndexFunc(result.Spec.Listeners, func(l gatewayv1.Listener) bool {
+			return l.Port == listener.Port && l.Protocol == listener.Protocol && ptrEqual(l.Hostname, listener.Hostname)
+		})
+		if idx != -1 {
+			conflicts = append(conflicts, fmt.Sprintf("listener %q collides with listener %q", listener.Name, result.Spec.Listeners[idx].Name))
+			continue
+		}
+
+		result.Spec.Listeners = append(result.Spec.Listeners, listener)
+	}
+
+	return result, conflicts
+}
+
+func conflictMessage(kind string, key types.NamespacedName, owner, provider ProviderName, conflicts []string) string {
+	return fmt.Sprintf("%s %s generated by providers %s and %s conflicts (%s), keeping the version generated by %s",
+		kind, key, owner, provider, strings.Join(conflicts, "; "), owner)
+}
+
+// gatewayConflictMessage describes the conflicts of a Gateway, whose listeners
+// which don't conflict are merged anyway.
+func gatewayConflictMessage(kind string, key types.NamespacedName, owner, provider ProviderName, conflicts []string) string {
+	return fmt.Sprintf("%s %s generated by providers %s and %s conflicts (%s), keeping the version generated by %s and adding the listeners of %s which don't conflict",
+		kind, key, owner, provider, strings.Join(conflicts, "; "), owner, provider)
+}
+
+func compareNamespacedNames(a, b types.NamespacedName) int {
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
+
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,167 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i2gw
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func testGateway(className string, listeners ...gatewayv1.Listener) gatewayv1.Gateway {
+	return gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gatewayv1.ObjectName(className),
+			Listeners:        listeners,
+		},
+	}
+}
+
+func testListener(name string, port gatewayv1.PortNumber, hostname string) gatewayv1.Listener {
+	h := gatewayv1.Hostname(hostname)
+	return gatewayv1.Listener{Name: gatewayv1.SectionName(name), Port: port, Protocol: gatewayv1.HTTPProtocolType, Hostname: &h}
+}
+
+func testHTTPRoute(name, hostname string) gatewayv1.HTTPRoute {
+	return gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(hostname)}},
+	}
+}
+
+func testExtension(kind, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	return u
+}
+
+func Test_MergeGatewayResources(t *testing.T) {
+	gwKey := types.NamespacedName{Namespace: "default", Name: "gw"}
+
+	testCases := []struct {
+		name                string
+		resourcesByProvider map[ProviderName]GatewayResources
+		expectedGateways    map[types.NamespacedName]gatewayv1.Gateway
+		expectedHTTPRoutes  map[types.NamespacedName]gatewayv1.HTTPRoute
+		expectedExtensions  []unstructured.Unstructured
+		expectedErrors      []string
+	}{{
+		name: "identical objects are de-duplicated",
+		resourcesByProvider: map[ProviderName]GatewayResources{
+			"a": {
+				Gateways:          map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx", testListener("http", 80, "foo.com"))},
+				HTTPRoutes:        map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "foo.com")},
+				GatewayExtensions: []unstructured.Unstructured{testExtension("Policy", "p")},
+			},
+			"b": {
+				Gateways:          map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx", testListener("http", 80, "foo.com"))},
+				HTTPRoutes:        map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "foo.com")},
+				GatewayExtensions: []unstructured.Unstructured{testExtension("Policy", "p")},
+			},
+		},
+		expectedGateways:   map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx", testListener("http", 80, "foo.com"))},
+		expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "foo.com")},
+		expectedExtensions: []unstructured.Unstructured{testExtension("Policy", "p")},
+	}, {
+		name: "Gateway listeners are merged",
+		resourcesByProvider: map[ProviderName]GatewayResources{
+			"b": {Gateways: map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx", testListener("bar", 80, "bar.com"))}},
+			"a": {Gateways: map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx", testListener("foo", 80, "foo.com"))}},
+		},
+		expectedGateways: map[types.NamespacedName]gatewayv1.Gateway{
+			gwKey: testGateway("nginx", testListener("foo", 80, "foo.com"), testListener("bar", 80, "bar.com")),
+		},
+		expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{},
+	}, {
+		name: "conflicts keep the object of the first provider",
+		resourcesByProvider: map[ProviderName]GatewayResources{
+			"a": {
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("nginx",
+					testListener("foo", 80, "foo.com"),
+					testListener("bar", 80, "bar.com"))},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "foo.com")},
+			},
+			"b": {
+				Gateways: map[types.NamespacedName]gatewayv1.Gateway{gwKey: testGateway("kong",
+					testListener("foo", 8080, "foo.com"),
+					testListener("bar-http", 80, "bar.com"),
+					testListener("baz", 80, "baz.com"))},
+				HTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "bar.com")},
+			},
+		},
+		expectedGateways: map[types.NamespacedName]gatewayv1.Gateway{
+			gwKey: testGateway("nginx",
+				testListener("foo", 80, "foo.com"),
+				testListener("bar", 80, "bar.com"),
+				testListener("baz", 80, "baz.com")),
+		},
+		expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{{Namespace: "default", Name: "r"}: testHTTPRoute("r", "foo.com")},
+		expectedErrors: []string{
+			"Gateway default/gw generated by providers a and b conflicts (gatewayClassName \"kong\" differs from \"nginx\"; listener \"foo\" differs; listener \"bar-http\" collides with listener \"bar\"), keeping the version generated by a and adding the listeners of b which don't conflict",
+			"HTTPRoute default/r generated by providers a and b conflicts (the objects differ), keeping the version generated by a",
+		},
+	}, {
+		name: "Gateway extensions are sorted",
+		resourcesByProvider: map[ProviderName]GatewayResources{
+			"a": {GatewayExtensions: []unstructured.Unstructured{testExtension("Policy", "z"), testExtension("Filter", "a")}},
+			"b": {GatewayExtensions: []unstructured.Unstructured{testExtension("Policy", "b")}},
+		},
+		expectedGateways:   map[types.NamespacedName]gatewayv1.Gateway{},
+		expectedHTTPRoutes: map[types.NamespacedName]gatewayv1.HTTPRoute{},
+		expectedExtensions: []unstructured.Unstructured{
+			testExtension("Filter", "a"),
+			testExtension("Policy", "b"),
+			testExtension("Policy", "z"),
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var errors []string
+			notify := func(mType notifications.MessageType, message string, _ ...client.Object) {
+				if mType == notifications.ErrorNotification {
+					errors = append(errors, message)
+				}
+			}
+
+			merged := MergeGatewayResources(tc.resourcesByProvider, notify)
+
+			if diff := cmp.Diff(tc.expectedGateways, merged.Gateways); diff != "" {
+				t.Errorf("Unexpected Gateways (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedHTTPRoutes, merged.HTTPRoutes); diff != "" {
+				t.Errorf("Unexpected HTTPRoutes (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedExtensions, merged.GatewayExtensions); diff != "" {
+				t.Errorf("Unexpected Gateway extensions (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedErrors, errors); diff != "" {
+				t.Errorf("Unexpected conflict errors (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
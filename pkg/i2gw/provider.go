@@ -46,7 +46,11 @@ type ProviderConstructor func(conf *ProviderConf) Provider
 // ProviderConf contains all the configuration required for every concrete
 // Provider implementation.
 type ProviderConf struct {
-	Client                client.Client
+	Client client.Client
+	// ClusterClient isn't scoped to the Namespace like Client, it reads the
+	// resources explicitly referenced in other namespaces, such as the
+	// ConfigMaps configuring a controller. It is nil when reading from a file.
+	ClusterClient         client.Client
 	Namespace             string
 	ProviderSpecificFlags map[string]map[string]string
 	Report                *notifications.Report
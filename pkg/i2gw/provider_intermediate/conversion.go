@@ -34,11 +34,13 @@ func ToEmitterIR(pIR ProviderIR) emitterir.EmitterIR {
 		BackendTLSPolicies: make(map[types.NamespacedName]emitterir.BackendTLSPolicyContext),
 		ReferenceGrants:    make(map[types.NamespacedName]emitterir.ReferenceGrantContext),
 		Services:           make(map[types.NamespacedName]emitterir.ServiceContext),
+		ConfigMaps:         make(map[types.NamespacedName]emitterir.ConfigMapContext),
+		ServicePatches:     make(map[types.NamespacedName]emitterir.ServicePatchContext),
 		GceServices:        make(map[types.NamespacedName]gce.ServiceIR),
 	}
 
 	for k, v := range pIR.Gateways {
-		ctx := emitterir.GatewayContext{Gateway: v.Gateway}
+		ctx := emitterir.GatewayContext{Gateway: v.Gateway, Addresses: v.Addresses}
 		if v.ProviderSpecificIR.Gce != nil {
 			ctx.Gce = v.ProviderSpecificIR.Gce
 		}
@@ -72,6 +74,7 @@ func ToEmitterIR(pIR ProviderIR) emitterir.EmitterIR {
 	for k, v := range pIR.Services {
 		eIR.Services[k] = emitterir.ServiceContext{
 			SessionAffinity: v.SessionAffinity,
+			LoadBalancer:    v.LoadBalancer,
 		}
 		if v.Gce != nil {
 			eIR.GceServices[k] = *v.Gce
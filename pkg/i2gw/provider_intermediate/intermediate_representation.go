@@ -53,6 +53,9 @@ type ProviderIR struct {
 type GatewayContext struct {
 	gatewayv1.Gateway
 	ProviderSpecificIR ProviderSpecificGatewayIR
+
+	// Addresses holds the addresses the source load balancers already use.
+	Addresses []emitterir.GatewayAddress
 }
 
 type ProviderSpecificGatewayIR struct {
@@ -90,6 +93,7 @@ type GRPCRouteContext struct {
 // extension features on Service.
 type ProviderSpecificServiceIR struct {
 	SessionAffinity *emitterir.SessionAffinity
+	LoadBalancer    *emitterir.LoadBalancer
 	Gce             *gce.ServiceIR
 }
 
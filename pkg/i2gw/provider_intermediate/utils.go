@@ -78,8 +78,9 @@ func mergeGatewayContexts(irs []ProviderIR) (map[types.NamespacedName]GatewayCon
 			if existingGatewayContext, ok := newGatewayContexts[nn]; ok {
 				g.Gateway.Spec.Listeners = append(g.Gateway.Spec.Listeners, existingGatewayContext.Gateway.Spec.Listeners...)
 				g.Gateway.Spec.Addresses = append(g.Gateway.Spec.Addresses, existingGatewayContext.Gateway.Spec.Addresses...)
+				g.Addresses = append(g.Addresses, existingGatewayContext.Addresses...)
 			}
-			newGatewayContexts[nn] = GatewayContext{Gateway: g.Gateway}
+			newGatewayContexts[nn] = GatewayContext{Gateway: g.Gateway, Addresses: g.Addresses}
 			// 64 is the maximum number of listeners a Gateway can have
 			if len(g.Spec.Listeners) > 64 {
 				fieldPath := field.NewPath(fmt.Sprintf("%s/%s", nn.Namespace, nn.Name)).Child("spec").Child("listeners")
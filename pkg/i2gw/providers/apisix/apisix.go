@@ -34,6 +34,11 @@ const ApisixIngressClass = "apisix"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.ProviderDetectorByName[Name] = i2gw.DetectionRules{
+		IngressClasses:     []string{ApisixIngressClass},
+		Controllers:        []string{"apisix.apache.org/apisix-ingress-controller"},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+	}.Detect
 }
 
 // Provider implements the i2gw.Provider interface.
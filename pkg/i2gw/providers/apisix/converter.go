@@ -56,6 +56,7 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (providerir.Provi
 	if len(errs) > 0 {
 		return providerir.ProviderIR{}, errs
 	}
+	common.InspectTLSSecrets(c.notify, &ir, storage.Secrets)
 
 	for _, parseFeatureFunc := range c.featureParsers {
 		// Apply the feature parsing function to the gateway resources, one by one.
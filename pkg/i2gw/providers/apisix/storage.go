@@ -17,6 +17,7 @@ limitations under the License.
 package apisix
 
 import (
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -24,6 +25,7 @@ import (
 type storage struct {
 	Ingresses    map[types.NamespacedName]*networkingv1.Ingress
 	ServicePorts map[types.NamespacedName]map[string]int32
+	Secrets      map[types.NamespacedName]*apiv1.Secret
 }
 
 func newResourcesStorage() *storage {
@@ -34,6 +34,11 @@ const CiliumIngressClass = "cilium"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.ProviderDetectorByName[Name] = i2gw.DetectionRules{
+		IngressClasses:     []string{CiliumIngressClass},
+		Controllers:        []string{"cilium.io/ingress-controller"},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+	}.Detect
 }
 
 // Provider implements the i2gw.Provider interface.
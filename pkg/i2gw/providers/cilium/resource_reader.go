@@ -52,6 +52,12 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 		return nil, err
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
+
+	secrets, err := common.ReadTLSSecretsFromCluster(ctx, r.conf.Client, r.conf.Report.Notifier(Name))
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
 	return storage, nil
 }
 
@@ -70,5 +76,11 @@ func (r *resourceReader) readResourcesFromFile(reader io.Reader) (*storage, erro
 		return nil, err
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
+
+	secrets, err := common.ReadTLSSecretsFromFile(reader, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
 	return storage, nil
 }
@@ -0,0 +1,92 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// IngressStatusAddresses returns the load balancer addresses recorded in the
+// Ingress status as Gateway address intent. Hostnames are assigned by the
+// load balancer itself, so they are marked as not reusable.
+func IngressStatusAddresses(ingress networkingv1.Ingress) []emitterir.GatewayAddress {
+	source := fmt.Sprintf("Ingress %s/%s", ingress.Namespace, ingress.Name)
+	statusPath := field.NewPath("status", "loadBalancer", "ingress")
+
+	var addresses []emitterir.GatewayAddress
+	for i, lb := range ingress.Status.LoadBalancer.Ingress {
+		switch {
+		case lb.IP != "":
+			addresses = append(addresses, emitterir.GatewayAddress{
+				Metadata: emitterir.NewExtensionFeatureMetadata(source, []*field.Path{statusPath.Index(i).Child("ip")}, ""),
+				GatewaySpecAddress: gatewayv1.GatewaySpecAddress{
+					Type:  ptr.To(gatewayv1.IPAddressType),
+					Value: lb.IP,
+				},
+			})
+		case lb.Hostname != "":
+			addresses = append(addresses, emitterir.GatewayAddress{
+				Metadata: emitterir.NewExtensionFeatureMetadata(source, []*field.Path{statusPath.Index(i).Child("hostname")}, ""),
+				GatewaySpecAddress: gatewayv1.GatewaySpecAddress{
+					Type:  ptr.To(gatewayv1.HostnameAddressType),
+					Value: lb.Hostname,
+				},
+				NotReusableReason: "the hostname is assigned by the load balancer and can't be requested by a new Gateway",
+			})
+		}
+	}
+	return addresses
+}
+
+// AddGatewayAddresses adds the addresses to the GatewayContext, skipping the
+// ones it already holds.
+func AddGatewayAddresses(gatewayContext *providerir.GatewayContext, addresses ...emitterir.GatewayAddress) {
+	for _, address := range addresses {
+		found := false
+		for _, existing := range gatewayContext.Addresses {
+			if ptr.Deref(existing.Type, gatewayv1.IPAddressType) == ptr.Deref(address.Type, gatewayv1.IPAddressType) && existing.Value == address.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			gatewayContext.Addresses = append(gatewayContext.Addresses, address)
+		}
+	}
+}
+
+// addIngressStatusAddresses carries the status addresses of every Ingress to
+// the Gateway generated for its ingress class.
+func addIngressStatusAddresses(ingresses []networkingv1.Ingress, gatewayByKey map[types.NamespacedName]providerir.GatewayContext) {
+	for _, ingress := range ingresses {
+		key := types.NamespacedName{Namespace: ingress.Namespace, Name: GetIngressClass(ingress)}
+		gatewayContext, ok := gatewayByKey[key]
+		if !ok {
+			continue
+		}
+		AddGatewayAddresses(&gatewayContext, IngressStatusAddresses(ingress)...)
+		gatewayByKey[key] = gatewayContext
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestToIRIngressStatusAddresses(t *testing.T) {
+	ingress := func(name string, status ...networkingv1.IngressLoadBalancerIngress) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: name + ".example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: "svc",
+								Port: networkingv1.ServiceBackendPort{Number: 80},
+							}},
+						}},
+					}},
+				}},
+			},
+			Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: status}},
+		}
+	}
+
+	ir, errs := ToIR([]networkingv1.Ingress{
+		ingress("a", networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}),
+		ingress("b", networkingv1.IngressLoadBalancerIngress{IP: "10.0.0.1"}, networkingv1.IngressLoadBalancerIngress{Hostname: "lb.example.com"}),
+	}, nil, nil, i2gw.ProviderImplementationSpecificOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	addresses := ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Addresses
+	if len(addresses) != 2 {
+		t.Fatalf("Expected 2 de-duplicated addresses, got %d: %+v", len(addresses), addresses)
+	}
+	if *addresses[0].Type != gatewayv1.IPAddressType || addresses[0].Value != "10.0.0.1" || addresses[0].NotReusableReason != "" {
+		t.Errorf("Unexpected IP address intent: %+v", addresses[0])
+	}
+	if *addresses[1].Type != gatewayv1.HostnameAddressType || addresses[1].Value != "lb.example.com" || addresses[1].NotReusableReason == "" {
+		t.Errorf("Expected a not reusable Hostname address intent, got %+v", addresses[1])
+	}
+	if addresses[1].Metadata.Source() != "Ingress default/b" {
+		t.Errorf("Expected the address to come from Ingress default/b, got %s", addresses[1].Metadata.Source())
+	}
+}
@@ -86,6 +86,8 @@ func ToIR(httpIngresses []networkingv1.Ingress, grpcIngresses []networkingv1.Ing
 		key := types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}
 		gatewayByKey[key] = providerir.GatewayContext{Gateway: gateway}
 	}
+	addIngressStatusAddresses(httpIngresses, gatewayByKey)
+	addIngressStatusAddresses(grpcIngresses, gatewayByKey)
 
 	return providerir.ProviderIR{
 		Gateways:           gatewayByKey,
@@ -126,6 +128,12 @@ var (
 		Kind:    "TCPRoute",
 	}
 
+	UDPRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+		Kind:    "UDPRoute",
+	}
+
 	ReferenceGrantGVK = schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1beta1",
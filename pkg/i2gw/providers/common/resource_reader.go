@@ -22,8 +22,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -116,7 +118,14 @@ func ReadServicesFromFile(reader io.Reader, namespace string) (map[types.Namespa
 // which is created from YAML or JSON input files.
 // It retrieves all objects, including nested ones if they are contained within a list.
 // The function takes a namespace parameter to optionally return only namespaced resources.
+// Readers that support seeking are rewound first, so the same input can be
+// read once per resource kind.
 func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructured.Unstructured, error) {
+	if seeker, ok := reader.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind manifest: %w", err)
+		}
+	}
 	d := kubeyaml.NewYAMLOrJSONDecoder(reader, 4096)
 	var objs []*unstructured.Unstructured
 	for {
@@ -159,3 +168,136 @@ func ExtractObjectsFromReader(reader io.Reader, namespace string) ([]*unstructur
 
 	return finalObjs, nil
 }
+
+// ReadSecretsFromCluster reads the Secrets of the keys from the cluster, one by
+// one, so that only the referenced Secrets are read. Secrets are optional: the
+// missing ones are skipped, and a notification tells about the ones the client
+// isn't allowed to read.
+func ReadSecretsFromCluster(ctx context.Context, client client.Client, notify notifications.NotifyFunc, keys []types.NamespacedName) (map[types.NamespacedName]*apiv1.Secret, error) {
+	secrets := map[types.NamespacedName]*apiv1.Secret{}
+	for _, key := range keys {
+		var secret apiv1.Secret
+		err := client.Get(ctx, key, &secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if apierrors.IsForbidden(err) {
+			notify(notifications.WarningNotification, fmt.Sprintf("Secret %s can't be read, it is not converted: %v", key, err))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s from the cluster: %w", key, err)
+		}
+		secrets[key] = &secret
+	}
+	return secrets, nil
+}
+
+// ReadSecretsFromFile reads the Secrets accepted by the filter from a reader.
+func ReadSecretsFromFile(reader io.Reader, namespace string, filter func(*apiv1.Secret) bool) (map[types.NamespacedName]*apiv1.Secret, error) {
+	unstructuredObjects, err := ExtractObjectsFromReader(reader, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	secrets := map[types.NamespacedName]*apiv1.Secret{}
+	for _, f := range unstructuredObjects {
+		if f.GroupVersionKind().Group != "" || f.GetKind() != "Secret" {
+			continue
+		}
+		var secret apiv1.Secret
+		err = runtime.DefaultUnstructuredConverter.
+			FromUnstructured(f.UnstructuredContent(), &secret)
+		if err != nil {
+			return nil, err
+		}
+		if !filter(&secret) {
+			continue
+		}
+		secrets[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] = &secret
+	}
+	return secrets, nil
+}
+
+// ReadConfigMapFromCluster reads a ConfigMap from the cluster.
+func ReadConfigMapFromCluster(ctx context.Context, client client.Client, key types.NamespacedName) (*apiv1.ConfigMap, error) {
+	var configMap apiv1.ConfigMap
+	if err := client.Get(ctx, key, &configMap); err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s from the cluster: %w", key, err)
+	}
+	return &configMap, nil
+}
+
+// ReadConfigMapFromFile reads a ConfigMap from a reader. ConfigMaps configuring
+// a controller usually live in another namespace than the converted resources,
+// so they are read from every namespace.
+func ReadConfigMapFromFile(reader io.Reader, key types.NamespacedName) (*apiv1.ConfigMap, error) {
+	unstructuredObjects, err := ExtractObjectsFromReader(reader, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	for _, f := range unstructuredObjects {
+		if f.GroupVersionKind().Group != "" || f.GetKind() != "ConfigMap" || f.GetNamespace() != key.Namespace || f.GetName() != key.Name {
+			continue
+		}
+		var configMap apiv1.ConfigMap
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(f.UnstructuredContent(), &configMap); err != nil {
+			return nil, err
+		}
+		return &configMap, nil
+	}
+	return nil, fmt.Errorf("configmap %s not found", key)
+}
+
+// ReadConfigMapsFromCluster reads the ConfigMaps of the keys from the cluster, one
+// by one, so that the referenced ConfigMaps are read from any namespace the client
+// can read. The missing ones are skipped, and a notification tells about the ones
+// the client isn't allowed to read.
+func ReadConfigMapsFromCluster(ctx context.Context, client client.Client, notify notifications.NotifyFunc, keys []types.NamespacedName) (map[types.NamespacedName]*apiv1.ConfigMap, error) {
+	configMaps := map[types.NamespacedName]*apiv1.ConfigMap{}
+	for _, key := range keys {
+		var configMap apiv1.ConfigMap
+		err := client.Get(ctx, key, &configMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if apierrors.IsForbidden(err) {
+			notify(notifications.WarningNotification, fmt.Sprintf("ConfigMap %s can't be read, it is not converted: %v", key, err))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap %s from the cluster: %w", key, err)
+		}
+		configMaps[key] = &configMap
+	}
+	return configMaps, nil
+}
+
+// ReadConfigMapsFromFile reads the ConfigMaps accepted by the filter from a reader.
+// The resources may reference ConfigMaps of other namespaces, so they are read
+// from every namespace.
+func ReadConfigMapsFromFile(reader io.Reader, filter func(*apiv1.ConfigMap) bool) (map[types.NamespacedName]*apiv1.ConfigMap, error) {
+	unstructuredObjects, err := ExtractObjectsFromReader(reader, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	configMaps := map[types.NamespacedName]*apiv1.ConfigMap{}
+	for _, f := range unstructuredObjects {
+		if f.GroupVersionKind().Group != "" || f.GetKind() != "ConfigMap" {
+			continue
+		}
+		var configMap apiv1.ConfigMap
+		err = runtime.DefaultUnstructuredConverter.
+			FromUnstructured(f.UnstructuredContent(), &configMap)
+		if err != nil {
+			return nil, err
+		}
+		if !filter(&configMap) {
+			continue
+		}
+		configMaps[types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}] = &configMap
+	}
+	return configMaps, nil
+}
@@ -93,6 +93,30 @@ func Test_ExtractObjectsFromReader(t *testing.T) {
 	}
 }
 
+func Test_ExtractObjectsFromReader_ReusedReader(t *testing.T) {
+	stream, err := os.ReadFile("testdata/input-file.yaml")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	reader := bytes.NewReader(stream)
+
+	first, err := ExtractObjectsFromReader(reader, "")
+	if err != nil {
+		t.Fatalf("failed to extract objects: %s", err)
+	}
+	second, err := ExtractObjectsFromReader(reader, "")
+	if err != nil {
+		t.Fatalf("failed to extract objects a second time: %s", err)
+	}
+
+	if len(first) == 0 {
+		t.Fatalf("Expected objects in the input file, got none")
+	}
+	if len(second) != len(first) {
+		t.Errorf("Expected %d objects when reading the reader again, got %d", len(first), len(second))
+	}
+}
+
 func ingress(port int32, name, namespace string) networkingv1.Ingress {
 	iPrefix := networkingv1.PathTypePrefix
 	ingressClassName := fmt.Sprintf("ingressClass-%s", name)
@@ -0,0 +1,334 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"cmp"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// certificateExpiryWarningPeriod is how long before its expiry a certificate
+// is reported as expiring soon.
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// ReadTLSSecretsFromCluster reads the kubernetes.io/tls Secrets from the
+// cluster, only the Secrets of that type are listed. Secrets are optional: when
+// the client isn't allowed to list them, nil is returned and a notification
+// tells that the certificates are not inspected.
+func ReadTLSSecretsFromCluster(ctx context.Context, c client.Client, notify notifications.NotifyFunc) (map[types.NamespacedName]*apiv1.Secret, error) {
+	var secretList apiv1.SecretList
+	err := c.List(ctx, &secretList, client.MatchingFields{"type": string(apiv1.SecretTypeTLS)})
+	if apierrors.IsForbidden(err) {
+		notify(notifications.WarningNotification, fmt.Sprintf("The TLS Secrets can't be listed, the certificates of the TLS listeners are not inspected: %v", err))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secrets from the cluster: %w", err)
+	}
+
+	secrets := map[types.NamespacedName]*apiv1.Secret{}
+	for i := range secretList.Items {
+		secrets[types.NamespacedName{Namespace: secretList.Items[i].Namespace, Name: secretList.Items[i].Name}] = &secretList.Items[i]
+	}
+	return secrets, nil
+}
+
+// ReadTLSSecretsFromFile reads the kubernetes.io/tls Secrets from a reader.
+func ReadTLSSecretsFromFile(reader io.Reader, namespace string) (map[types.NamespacedName]*apiv1.Secret, error) {
+	return ReadSecretsFromFile(reader, namespace, IsTLSSecret)
+}
+
+// IsTLSSecret reports whether the Secret is of type kubernetes.io/tls.
+func IsTLSSecret(secret *apiv1.Secret) bool {
+	return secret.Type == apiv1.SecretTypeTLS
+}
+
+// InspectTLSSecrets checks the certificates referenced by the TLS listeners
+// of the Gateways against the listener hostnames and reports the listeners
+// whose certificates are missing, expired or don't cover the hostname.
+//
+// Per-host HTTPS listeners which share the same certificates are then replaced
+// by a single wildcard listener when the certificates cover that wildcard.
+//
+// Nothing is done when no Secrets were read, since the certificates can't be
+// inspected without them.
+func InspectTLSSecrets(notify notifications.NotifyFunc, ir *providerir.ProviderIR, secrets map[types.NamespacedName]*apiv1.Secret) {
+	inspectTLSSecrets(notify, ir, secrets, time.Now())
+}
+
+func inspectTLSSecrets(notify notifications.NotifyFunc, ir *providerir.ProviderIR, secrets map[types.NamespacedName]*apiv1.Secret, now time.Time) {
+	if len(secrets) == 0 {
+		return
+	}
+
+	inspector := certificateInspector{
+		notify:       notify,
+		secrets:      secrets,
+		now:          now,
+		certificates: map[types.NamespacedName]*x509.Certificate{},
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(ir.Gateways), compareNamespacedNames) {
+		gatewayContext := ir.Gateways[key]
+		inspector.checkListeners(&gatewayContext.Gateway)
+		inspector.consolidateWildcardListeners(&gatewayContext.Gateway)
+		ir.Gateways[key] = gatewayContext
+	}
+}
+
+// certificateInspector parses the certificate of each TLS Secret once, so
+// that the problems with a Secret are only reported once.
+type certificateInspector struct {
+	notify       notifications.NotifyFunc
+	secrets      map[types.NamespacedName]*apiv1.Secret
+	now          time.Time
+	certificates map[types.NamespacedName]*x509.Certificate
+}
+
+// certificate returns the leaf certificate of the referenced Secret, or nil
+// if the Secret is missing or can't be parsed.
+func (i *certificateInspector) certificate(gateway *gatewayv1.Gateway, ref gatewayv1.SecretObjectReference) *x509.Certificate {
+	key := types.NamespacedName{Namespace: string(ptr.Deref(ref.Namespace, gatewayv1.Namespace(gateway.Namespace))), Name: string(ref.Name)}
+	if certificate, ok := i.certificates[key]; ok {
+		return certificate
+	}
+
+	var certificate *x509.Certificate
+	secret, ok := i.secrets[key]
+	if !ok {
+		i.notify(notifications.WarningNotification, fmt.Sprintf("TLS Secret %s referenced by Gateway %s/%s was not found, its certificate can't be checked", key, gateway.Namespace, gateway.Name), gateway)
+	} else if parsed, err := parseTLSSecretCertificate(secret); err != nil {
+		i.notify(notifications.ErrorNotification, fmt.Sprintf("TLS Secret %s referenced by Gateway %s/%s is invalid: %v", key, gateway.Namespace, gateway.Name, err), secret)
+	} else {
+		certificate = parsed
+		switch {
+		case i.now.After(certificate.NotAfter):
+			i.notify(notifications.ErrorNotification, fmt.Sprintf("the certificate in TLS Secret %s expired on %s", key, certificate.NotAfter.UTC().Format(time.RFC3339)), secret)
+		case i.now.Before(certificate.NotBefore):
+			i.notify(notifications.WarningNotification, fmt.Sprintf("the certificate in TLS Secret %s is not valid before %s", key, certificate.NotBefore.UTC().Format(time.RFC3339)), secret)
+		case i.now.Add(certificateExpiryWarningPeriod).After(certificate.NotAfter):
+			i.notify(notifications.WarningNotification, fmt.Sprintf("the certificate in TLS Secret %s expires on %s", key, certificate.NotAfter.UTC().Format(time.RFC3339)), secret)
+		}
+	}
+	i.certificates[key] = certificate
+	return certificate
+}
+
+// checkListeners reports the TLS listeners whose hostname is not covered by
+// any of their certificates.
+func (i *certificateInspector) checkListeners(gateway *gatewayv1.Gateway) {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+			continue
+		}
+
+		var names []string
+		covered, inspected := false, false
+		for _, ref := range listener.TLS.CertificateRefs {
+			names = append(names, string(ref.Name))
+			certificate := i.certificate(gateway, ref)
+			if certificate == nil {
+				continue
+			}
+			inspected = true
+			if listener.Hostname == nil || certificateCoversHostname(certificate, string(*listener.Hostname)) {
+				covered = true
+			}
+		}
+		if inspected && !covered {
+			i.notify(notifications.WarningNotification,
+				fmt.Sprintf("listener %q of Gateway %s/%s serves hostname %q, which is not covered by the certificates in TLS Secret(s) %s",
+					listener.Name, gateway.Namespace, gateway.Name, *listener.Hostname, strings.Join(names, ", ")),
+				gateway)
+		}
+	}
+}
+
+// consolidateWildcardListeners replaces the HTTPS listeners of sibling hosts
+// (a.example.com, b.example.com) which use the same certificates by a single
+// *.example.com listener, when all of these certificates cover the wildcard.
+func (i *certificateInspector) consolidateWildcardListeners(gateway *gatewayv1.Gateway) {
+	groups := map[string][]int{}
+	wildcards := map[string]string{}
+	for idx, listener := range gateway.Spec.Listeners {
+		wildcard, ok := i.wildcardFor(gateway, listener)
+		if !ok {
+			continue
+		}
+		var names []string
+		for _, ref := range listener.TLS.CertificateRefs {
+			names = append(names, fmt.Sprintf("%s/%s", ptr.Deref(ref.Namespace, ""), ref.Name))
+		}
+		key := fmt.Sprintf("%d/%s/%s", listener.Port, wildcard, strings.Join(names, ","))
+		groups[key] = append(groups[key], idx)
+		wildcards[key] = wildcard
+	}
+
+	removed := map[int]bool{}
+	replacements := map[int]gatewayv1.Listener{}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		idxs := groups[key]
+		if len(idxs) < 2 {
+			continue
+		}
+		first := gateway.Spec.Listeners[idxs[0]]
+		wildcard := wildcards[key]
+		// NameFromHost drops the leading "*", which would make the name collide
+		// with the listener of the parent domain.
+		name := gatewayv1.SectionName(fmt.Sprintf("wildcard-%s-https", NameFromHost(wildcard)))
+
+		compatible := true
+		for _, idx := range idxs[1:] {
+			listener := gateway.Spec.Listeners[idx]
+			if !apiequality.Semantic.DeepEqual(listener.TLS, first.TLS) || !apiequality.Semantic.DeepEqual(listener.AllowedRoutes, first.AllowedRoutes) {
+				compatible = false
+			}
+		}
+		for idx, listener := range gateway.Spec.Listeners {
+			if slices.Contains(idxs, idx) {
+				continue
+			}
+			if listener.Name == name || (listener.Port == first.Port && listener.Hostname != nil && string(*listener.Hostname) == wildcard) {
+				compatible = false
+			}
+		}
+		if !compatible {
+			continue
+		}
+
+		var names []string
+		for _, idx := range idxs {
+			names = append(names, string(gateway.Spec.Listeners[idx].Name))
+			removed[idx] = true
+		}
+		consolidated := *first.DeepCopy()
+		consolidated.Name = name
+		consolidated.Hostname = ptr.To(gatewayv1.Hostname(wildcard))
+		replacements[idxs[0]] = consolidated
+
+		i.notify(notifications.InfoNotification,
+			fmt.Sprintf("listeners %s of Gateway %s/%s were replaced by the wildcard listener %q, their certificates cover %s",
+				strings.Join(names, ", "), gateway.Namespace, gateway.Name, name, wildcard),
+			gateway)
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	var listeners []gatewayv1.Listener
+	for idx, listener := range gateway.Spec.Listeners {
+		if replacement, ok := replacements[idx]; ok {
+			listeners = append(listeners, replacement)
+		} else if !removed[idx] {
+			listeners = append(listeners, listener)
+		}
+	}
+	gateway.Spec.Listeners = listeners
+}
+
+// wildcardFor returns the wildcard hostname which could replace the hostname
+// of an HTTPS listener, if all the certificates of the listener cover it.
+func (i *certificateInspector) wildcardFor(gateway *gatewayv1.Gateway, listener gatewayv1.Listener) (string, bool) {
+	if listener.Protocol != gatewayv1.HTTPSProtocolType || listener.Hostname == nil || listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+		return "", false
+	}
+	hostname := string(*listener.Hostname)
+	_, parent, found := strings.Cut(hostname, ".")
+	// Wildcards directly under a top-level domain are not valid certificate names.
+	if strings.HasPrefix(hostname, "*") || !found || !strings.Contains(parent, ".") {
+		return "", false
+	}
+	wildcard := "*." + parent
+
+	for _, ref := range listener.TLS.CertificateRefs {
+		certificate := i.certificate(gateway, ref)
+		if certificate == nil || !slices.ContainsFunc(certificate.DNSNames, func(name string) bool { return strings.EqualFold(name, wildcard) }) {
+			return "", false
+		}
+	}
+	return wildcard, true
+}
+
+// parseTLSSecretCertificate returns the leaf certificate stored in the
+// tls.crt key of a kubernetes.io/tls Secret.
+func parseTLSSecretCertificate(secret *apiv1.Secret) (*x509.Certificate, error) {
+	data, ok := secret.Data[apiv1.TLSCertKey]
+	if !ok {
+		if value, found := secret.StringData[apiv1.TLSCertKey]; found {
+			data = []byte(value)
+		} else {
+			return nil, fmt.Errorf("the %s key is missing", apiv1.TLSCertKey)
+		}
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM encoded certificate found in " + apiv1.TLSCertKey)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the certificate: %w", err)
+		}
+		return certificate, nil
+	}
+}
+
+// certificateCoversHostname returns whether one of the DNS SANs of the
+// certificate matches the hostname. A wildcard SAN matches a single label,
+// and a wildcard hostname is only covered by the same wildcard SAN.
+func certificateCoversHostname(certificate *x509.Certificate, hostname string) bool {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	for _, name := range certificate.DNSNames {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+		if name == hostname {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*"); ok && !strings.HasPrefix(hostname, "*") {
+			label, rest, found := strings.Cut(hostname, ".")
+			if found && label != "" && "."+rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func compareNamespacedNames(a, b types.NamespacedName) int {
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
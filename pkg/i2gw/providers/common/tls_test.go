@@ -0,0 +1,338 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var testNow = time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func testCertificatePEM(t *testing.T, notAfter time.Time, dnsNames ...string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    testNow.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testTLSSecret(name string, cert []byte) *apiv1.Secret {
+	return &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Type:       apiv1.SecretTypeTLS,
+		Data:       map[string][]byte{apiv1.TLSCertKey: cert},
+	}
+}
+
+func testHTTPSListener(name, hostname, secret string) gatewayv1.Listener {
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(name),
+		Hostname: ptr.To(gatewayv1.Hostname(hostname)),
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.ListenerTLSConfig{
+			CertificateRefs: []gatewayv1.SecretObjectReference{{
+				Group: ptr.To(gatewayv1.Group("")),
+				Kind:  ptr.To(gatewayv1.Kind("Secret")),
+				Name:  gatewayv1.ObjectName(secret),
+			}},
+		},
+	}
+}
+
+func TestInspectTLSSecrets(t *testing.T) {
+	valid := testNow.Add(365 * 24 * time.Hour)
+	wildcardCert := testCertificatePEM(t, valid, "*.example.com", "example.com")
+	fooCert := testCertificatePEM(t, valid, "foo.example.org")
+	expiredCert := testCertificatePEM(t, testNow.Add(-time.Hour), "old.example.net")
+	expiringCert := testCertificatePEM(t, testNow.Add(7*24*time.Hour), "soon.example.net")
+
+	testCases := []struct {
+		name                  string
+		listeners             []gatewayv1.Listener
+		secrets               map[types.NamespacedName]*apiv1.Secret
+		expectedListeners     []gatewayv1.Listener
+		expectedNotifications map[notifications.MessageType]int
+	}{{
+		name: "sibling hosts covered by a wildcard certificate are consolidated",
+		listeners: []gatewayv1.Listener{
+			{Name: "a-example-com-http", Hostname: ptr.To(gatewayv1.Hostname("a.example.com")), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			testHTTPSListener("a-example-com-https", "a.example.com", "wildcard"),
+			testHTTPSListener("b-example-com-https", "b.example.com", "wildcard"),
+			testHTTPSListener("example-com-https", "example.com", "wildcard"),
+		},
+		secrets: map[types.NamespacedName]*apiv1.Secret{
+			{Namespace: "default", Name: "wildcard"}: testTLSSecret("wildcard", wildcardCert),
+		},
+		expectedListeners: []gatewayv1.Listener{
+			{Name: "a-example-com-http", Hostname: ptr.To(gatewayv1.Hostname("a.example.com")), Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			testHTTPSListener("wildcard-example-com-https", "*.example.com", "wildcard"),
+			testHTTPSListener("example-com-https", "example.com", "wildcard"),
+		},
+		expectedNotifications: map[notifications.MessageType]int{notifications.InfoNotification: 1},
+	}, {
+		name: "hosts with different certificates are not consolidated",
+		listeners: []gatewayv1.Listener{
+			testHTTPSListener("a-example-com-https", "a.example.com", "wildcard"),
+			testHTTPSListener("b-example-com-https", "b.example.com", "other-wildcard"),
+		},
+		secrets: map[types.NamespacedName]*apiv1.Secret{
+			{Namespace: "default", Name: "wildcard"}:       testTLSSecret("wildcard", wildcardCert),
+			{Namespace: "default", Name: "other-wildcard"}: testTLSSecret("other-wildcard", wildcardCert),
+		},
+		expectedListeners: []gatewayv1.Listener{
+			testHTTPSListener("a-example-com-https", "a.example.com", "wildcard"),
+			testHTTPSListener("b-example-com-https", "b.example.com", "other-wildcard"),
+		},
+		expectedNotifications: map[notifications.MessageType]int{},
+	}, {
+		name: "uncovered hostnames, expired certificates and missing Secrets are reported",
+		listeners: []gatewayv1.Listener{
+			testHTTPSListener("bar-example-org-https", "bar.example.org", "foo"),
+			testHTTPSListener("old-example-net-https", "old.example.net", "expired"),
+			testHTTPSListener("soon-example-net-https", "soon.example.net", "expiring"),
+			testHTTPSListener("missing-example-net-https", "missing.example.net", "missing"),
+		},
+		secrets: map[types.NamespacedName]*apiv1.Secret{
+			{Namespace: "default", Name: "foo"}:      testTLSSecret("foo", fooCert),
+			{Namespace: "default", Name: "expired"}:  testTLSSecret("expired", expiredCert),
+			{Namespace: "default", Name: "expiring"}: testTLSSecret("expiring", expiringCert),
+		},
+		expectedListeners: []gatewayv1.Listener{
+			testHTTPSListener("bar-example-org-https", "bar.example.org", "foo"),
+			testHTTPSListener("old-example-net-https", "old.example.net", "expired"),
+			testHTTPSListener("soon-example-net-https", "soon.example.net", "expiring"),
+			testHTTPSListener("missing-example-net-https", "missing.example.net", "missing"),
+		},
+		expectedNotifications: map[notifications.MessageType]int{
+			notifications.WarningNotification: 3,
+			notifications.ErrorNotification:   1,
+		},
+	}, {
+		name: "invalid certificates are reported",
+		listeners: []gatewayv1.Listener{
+			testHTTPSListener("foo-example-org-https", "foo.example.org", "invalid"),
+		},
+		secrets: map[types.NamespacedName]*apiv1.Secret{
+			{Namespace: "default", Name: "invalid"}: testTLSSecret("invalid", []byte("not a certificate")),
+		},
+		expectedListeners: []gatewayv1.Listener{
+			testHTTPSListener("foo-example-org-https", "foo.example.org", "invalid"),
+		},
+		expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+	}, {
+		name: "nothing is inspected without Secrets",
+		listeners: []gatewayv1.Listener{
+			testHTTPSListener("a-example-com-https", "a.example.com", "wildcard"),
+			testHTTPSListener("b-example-com-https", "b.example.com", "wildcard"),
+		},
+		expectedListeners: []gatewayv1.Listener{
+			testHTTPSListener("a-example-com-https", "a.example.com", "wildcard"),
+			testHTTPSListener("b-example-com-https", "b.example.com", "wildcard"),
+		},
+		expectedNotifications: map[notifications.MessageType]int{},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gwKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+			ir := providerir.ProviderIR{
+				Gateways: map[types.NamespacedName]providerir.GatewayContext{
+					gwKey: {Gateway: gatewayv1.Gateway{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+						Spec:       gatewayv1.GatewaySpec{Listeners: tc.listeners},
+					}},
+				},
+			}
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			inspectTLSSecrets(notify, &ir, tc.secrets, testNow)
+
+			if diff := cmp.Diff(tc.expectedListeners, ir.Gateways[gwKey].Spec.Listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCertificateCoversHostname(t *testing.T) {
+	certificate := &x509.Certificate{DNSNames: []string{"*.example.com", "Foo.Example.org"}}
+
+	testCases := []struct {
+		hostname string
+		expected bool
+	}{
+		{hostname: "a.example.com", expected: true},
+		{hostname: "a.b.example.com", expected: false},
+		{hostname: "example.com", expected: false},
+		{hostname: "*.example.com", expected: true},
+		{hostname: "foo.example.org", expected: true},
+		{hostname: "*.example.org", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.hostname, func(t *testing.T) {
+			if actual := certificateCoversHostname(certificate, tc.hostname); actual != tc.expected {
+				t.Errorf("certificateCoversHostname(%q) = %t, expected %t", tc.hostname, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestReadTLSSecretsFromFile(t *testing.T) {
+	data := []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  namespace: default
+spec:
+  ports:
+  - name: http
+    port: 80
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+  namespace: default
+type: kubernetes.io/tls
+data:
+  tls.crt: ""
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: opaque
+  namespace: default
+type: Opaque
+`)
+
+	// The same reader is used for every kind of resource.
+	reader := bytes.NewReader(data)
+	services, err := ReadServicesFromFile(reader, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	secrets, err := ReadTLSSecretsFromFile(reader, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(services) != 1 {
+		t.Errorf("Expected 1 Service, got %d", len(services))
+	}
+	if _, ok := secrets[types.NamespacedName{Namespace: "default", Name: "tls"}]; !ok || len(secrets) != 1 {
+		t.Errorf("Expected only the default/tls Secret, got %v", secrets)
+	}
+}
+
+func TestReadSecretsFromCluster(t *testing.T) {
+	opaque := &apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "auth"}, Type: apiv1.SecretTypeOpaque}
+	builder := func() *fake.ClientBuilder {
+		return fake.NewClientBuilder().
+			WithObjects(testTLSSecret("tls", nil), opaque).
+			WithIndex(&apiv1.Secret{}, "type", func(obj client.Object) []string {
+				return []string{string(obj.(*apiv1.Secret).Type)}
+			})
+	}
+
+	var messages []string
+	notify := func(_ notifications.MessageType, message string, _ ...client.Object) {
+		messages = append(messages, message)
+	}
+
+	cl := builder().Build()
+	secrets, err := ReadTLSSecretsFromCluster(context.Background(), cl, notify)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := secrets[types.NamespacedName{Namespace: "default", Name: "tls"}]; !ok || len(secrets) != 1 {
+		t.Errorf("Expected only the default/tls Secret, got %v", secrets)
+	}
+
+	keys := []types.NamespacedName{{Namespace: "default", Name: "auth"}, {Namespace: "default", Name: "missing"}}
+	secrets, err = ReadSecretsFromCluster(context.Background(), cl, notify, keys)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := secrets[keys[0]]; !ok || len(secrets) != 1 {
+		t.Errorf("Expected only the default/auth Secret, got %v", secrets)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected no notifications, got %v", messages)
+	}
+
+	forbidden := apierrors.NewForbidden(apiv1.Resource("secrets"), "", errors.New("no access"))
+	cl = builder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return forbidden
+		},
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return forbidden
+		},
+	}).Build()
+	if secrets, err = ReadTLSSecretsFromCluster(context.Background(), cl, notify); err != nil || secrets != nil {
+		t.Errorf("Expected no Secrets and no error, got %v, %v", secrets, err)
+	}
+	if secrets, err = ReadSecretsFromCluster(context.Background(), cl, notify, keys); err != nil || len(secrets) != 0 {
+		t.Errorf("Expected no Secrets and no error, got %v, %v", secrets, err)
+	}
+	if len(messages) != 3 {
+		t.Errorf("Expected a notification for the list and each Secret, got %v", messages)
+	}
+}
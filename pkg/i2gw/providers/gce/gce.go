@@ -25,6 +25,7 @@ import (
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
@@ -38,6 +39,20 @@ const (
 
 func init() {
 	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.ProviderDetectorByName[ProviderName] = i2gw.DetectionRules{
+		IngressClasses: supportedGCEIngressClass.UnsortedList(),
+		AnnotationPrefixes: []string{
+			globalStaticIPNameKey,
+			regionalStaticIPNameKey,
+			"ingress.gcp.kubernetes.io/",
+			"cloud.google.com/",
+			"networking.gke.io/",
+		},
+		SupportingKinds: []schema.GroupKind{
+			{Group: "cloud.google.com", Kind: "BackendConfig"},
+			{Group: "networking.gke.io", Kind: "FrontendConfig"},
+		},
+	}.Detect
 	i2gw.RegisterProviderSpecificFlag("gce", i2gw.ProviderSpecificFlag{
 		Name:         GatewayClassNameFlag,
 		Description:  "The name of the GatewayClass to use for the Gateway",
@@ -19,8 +19,10 @@ package gce
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate/gce"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
@@ -33,6 +35,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 type contextKey int
@@ -80,6 +84,7 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (providerir.Provi
 	if len(errs) > 0 {
 		return providerir.ProviderIR{}, errs
 	}
+	common.InspectTLSSecrets(c.notify, &ir, storage.Secrets)
 
 	// Extract gatewayClassName from provider-specific flags
 	var gatewayClassName string
@@ -96,6 +101,7 @@ func (c *resourcesToIRConverter) convertToIR(storage *storage) (providerir.Provi
 	if len(errs) > 0 {
 		return providerir.ProviderIR{}, errs
 	}
+	buildGceGatewayAddresses(ingressList, &ir)
 	buildGceGatewayIR(c.ctx, storage, &ir)
 	buildGceServiceIR(c.ctx, c.notify, storage, &ir)
 	return ir, errs
@@ -122,6 +128,48 @@ func buildGceGatewayIR(ctx context.Context, storage *storage, ir *providerir.Pro
 	}
 }
 
+// buildGceGatewayAddresses replaces the Ingress status addresses collected by
+// the common conversion with GCE specific address intent. Reserved static IPs
+// are referenced by name, while status IPs without a reservation are
+// ephemeral and are released together with the Ingress load balancer.
+func buildGceGatewayAddresses(ingresses []networkingv1.Ingress, ir *providerir.ProviderIR) {
+	addressesByGateway := make(map[types.NamespacedName][]emitterir.GatewayAddress)
+	for _, ingress := range ingresses {
+		gwyKey := types.NamespacedName{Namespace: ingress.Namespace, Name: common.GetIngressClass(ingress)}
+		source := fmt.Sprintf("Ingress %s/%s", ingress.Namespace, ingress.Name)
+
+		staticIPKey := globalStaticIPNameKey
+		staticIPName := ingress.Annotations[globalStaticIPNameKey]
+		if staticIPName == "" {
+			staticIPKey = regionalStaticIPNameKey
+			staticIPName = ingress.Annotations[regionalStaticIPNameKey]
+		}
+		if staticIPName != "" {
+			addressesByGateway[gwyKey] = append(addressesByGateway[gwyKey], emitterir.GatewayAddress{
+				Metadata: emitterir.NewExtensionFeatureMetadata(source, []*field.Path{field.NewPath("metadata", "annotations").Key(staticIPKey)}, ""),
+				GatewaySpecAddress: gatewayv1.GatewaySpecAddress{
+					Type:  ptr.To(gatewayv1.NamedAddressType),
+					Value: staticIPName,
+				},
+			})
+			continue
+		}
+
+		for _, address := range common.IngressStatusAddresses(ingress) {
+			if address.NotReusableReason == "" {
+				address.NotReusableReason = fmt.Sprintf("the IP address is ephemeral, reserve it as a static IP address and reference it with the %s annotation to reuse it", globalStaticIPNameKey)
+			}
+			addressesByGateway[gwyKey] = append(addressesByGateway[gwyKey], address)
+		}
+	}
+
+	for gwyKey, gatewayContext := range ir.Gateways {
+		gatewayContext.Addresses = nil
+		common.AddGatewayAddresses(&gatewayContext, addressesByGateway[gwyKey]...)
+		ir.Gateways[gwyKey] = gatewayContext
+	}
+}
+
 type gatewayNames []types.NamespacedName
 
 func getFrontendConfigMapping(ctx context.Context, storage *storage) map[types.NamespacedName]gatewayNames {
@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -1320,3 +1321,44 @@ func TestGetBackendConfigName(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildGceGatewayAddresses(t *testing.T) {
+	t.Parallel()
+
+	gwyKey := types.NamespacedName{Namespace: testNamespace, Name: gceIngressClass}
+	ingress := func(name string, annotations map[string]string, ip string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   testNamespace,
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: networkingv1.IngressSpec{IngressClassName: ptr.To(gceIngressClass)},
+			Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: ip}},
+			}},
+		}
+	}
+
+	ir := providerir.ProviderIR{
+		Gateways: map[types.NamespacedName]providerir.GatewayContext{gwyKey: {}},
+	}
+	buildGceGatewayAddresses([]networkingv1.Ingress{
+		ingress("static", map[string]string{globalStaticIPNameKey: "my-static-ip"}, "34.0.0.1"),
+		ingress("regional", map[string]string{regionalStaticIPNameKey: "my-regional-ip"}, "10.0.0.1"),
+		ingress("ephemeral", nil, "34.0.0.2"),
+	}, &ir)
+
+	var actual []string
+	for _, address := range ir.Gateways[gwyKey].Addresses {
+		actual = append(actual, fmt.Sprintf("%s=%s reusable=%t", *address.Type, address.Value, address.NotReusableReason == ""))
+	}
+	expected := []string{
+		"NamedAddress=my-static-ip reusable=true",
+		"NamedAddress=my-regional-ip reusable=true",
+		"IPAddress=34.0.0.2 reusable=false",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("Unexpected Gateway addresses (-want +got):\n%s", diff)
+	}
+}
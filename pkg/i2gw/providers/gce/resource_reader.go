@@ -71,6 +71,12 @@ func (r *reader) readResourcesFromCluster(ctx context.Context) (*storage, error)
 	storage.Services = services
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
 
+	secrets, err := common.ReadTLSSecretsFromCluster(ctx, r.conf.Client, r.conf.Report.Notifier(ProviderName))
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
+
 	backendConfigs, err := r.readBackendConfigsFromCluster(ctx)
 	if err != nil {
 		return nil, err
@@ -131,6 +137,7 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 
 	ingresses := make(map[types.NamespacedName]*networkingv1.Ingress)
 	services := make(map[types.NamespacedName]*apiv1.Service)
+	secrets := make(map[types.NamespacedName]*apiv1.Secret)
 	backendConfigs := make(map[types.NamespacedName]*backendconfigv1.BackendConfig)
 	frontendConfigs := make(map[types.NamespacedName]*frontendconfigv1beta1.FrontendConfig)
 
@@ -160,6 +167,17 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 			}
 			services[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = &service
 		}
+		if f.GetAPIVersion() == "v1" && f.GetKind() == "Secret" {
+			var secret apiv1.Secret
+			err := runtime.DefaultUnstructuredConverter.
+				FromUnstructured(f.UnstructuredContent(), &secret)
+			if err != nil {
+				return nil, err
+			}
+			if secret.Type == apiv1.SecretTypeTLS {
+				secrets[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] = &secret
+			}
+		}
 		if f.GetAPIVersion() == "cloud.google.com/v1" && f.GetKind() == "BackendConfig" {
 			var backendConfig backendconfigv1.BackendConfig
 			err := runtime.DefaultUnstructuredConverter.
@@ -182,6 +200,7 @@ func (r *reader) readUnstructuredObjects(objects []*unstructured.Unstructured) (
 	res.Ingresses = ingresses
 	res.Services = services
 	res.ServicePorts = common.GroupServicePortsByPortName(services)
+	res.Secrets = secrets
 	res.BackendConfigs = backendConfigs
 	res.FrontendConfigs = frontendConfigs
 	return res, nil
@@ -28,6 +28,7 @@ type storage struct {
 	Ingresses    map[types.NamespacedName]*networkingv1.Ingress
 	Services     map[types.NamespacedName]*apiv1.Service
 	ServicePorts map[types.NamespacedName]map[string]int32
+	Secrets      map[types.NamespacedName]*apiv1.Secret
 
 	// BackendConfig is a GKE Ingress extension, and it is associated to an GKE
 	// Ingress through specifying `cloud.google.com/backend-config` or
@@ -27,6 +27,8 @@ const (
 	backendConfigKey                       = "cloud.google.com/backend-config"
 	betaBackendConfigKey                   = "beta.cloud.google.com/backend-config"
 	frontendConfigKey                      = "networking.gke.io/v1beta1.FrontendConfig"
+	globalStaticIPNameKey                  = "kubernetes.io/ingress.global-static-ip-name"
+	regionalStaticIPNameKey                = "kubernetes.io/ingress.regional-static-ip-name"
 )
 
 // SupportedGKEGatewayClasses mapped to true for quick validation check
@@ -37,6 +37,7 @@ const (
 	FromToWWWRedirectAnnotation     = "nginx.ingress.kubernetes.io/from-to-www-redirect"
 	ProxyRedirectFromAnnotation     = "nginx.ingress.kubernetes.io/proxy-redirect-from"
 	ProxyRedirectToAnnotation       = "nginx.ingress.kubernetes.io/proxy-redirect-to"
+	AppRootAnnotation               = "nginx.ingress.kubernetes.io/app-root"
 
 	// Header annotations
 	XForwardedPrefixAnnotation      = "nginx.ingress.kubernetes.io/x-forwarded-prefix"
@@ -49,9 +50,19 @@ const (
 	ProxySendTimeoutAnnotation    = "nginx.ingress.kubernetes.io/proxy-send-timeout"
 	ProxyReadTimeoutAnnotation    = "nginx.ingress.kubernetes.io/proxy-read-timeout"
 
-	// Body Size annotations
-	ProxyBodySizeAnnotation        = "nginx.ingress.kubernetes.io/proxy-body-size"
-	ClientBodyBufferSizeAnnotation = "nginx.ingress.kubernetes.io/client-body-buffer-size"
+	// Retry annotations
+	ProxyNextUpstreamAnnotation        = "nginx.ingress.kubernetes.io/proxy-next-upstream"
+	ProxyNextUpstreamTriesAnnotation   = "nginx.ingress.kubernetes.io/proxy-next-upstream-tries"
+	ProxyNextUpstreamTimeoutAnnotation = "nginx.ingress.kubernetes.io/proxy-next-upstream-timeout"
+
+	// Body Size and buffering annotations
+	ProxyBodySizeAnnotation         = "nginx.ingress.kubernetes.io/proxy-body-size"
+	ClientBodyBufferSizeAnnotation  = "nginx.ingress.kubernetes.io/client-body-buffer-size"
+	ProxyRequestBufferingAnnotation = "nginx.ingress.kubernetes.io/proxy-request-buffering"
+	ProxyBufferingAnnotation        = "nginx.ingress.kubernetes.io/proxy-buffering"
+	ProxyBufferSizeAnnotation       = "nginx.ingress.kubernetes.io/proxy-buffer-size"
+	ProxyMaxTempFileSizeAnnotation  = "nginx.ingress.kubernetes.io/proxy-max-temp-file-size"
+	ProxyHTTPVersionAnnotation      = "nginx.ingress.kubernetes.io/proxy-http-version"
 
 	// Backend protocol annotation
 	BackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
@@ -62,6 +73,13 @@ const (
 	// SSL Redirect annotation
 	SSLRedirectAnnotation = "nginx.ingress.kubernetes.io/ssl-redirect"
 
+	// SSL Passthrough annotation
+	SSLPassthroughAnnotation = "nginx.ingress.kubernetes.io/ssl-passthrough"
+
+	// SSL cipher annotations
+	SSLCiphersAnnotation             = "nginx.ingress.kubernetes.io/ssl-ciphers"
+	SSLPreferServerCiphersAnnotation = "nginx.ingress.kubernetes.io/ssl-prefer-server-ciphers"
+
 	// CORS annotations
 	EnableCorsAnnotation       = "nginx.ingress.kubernetes.io/enable-cors"
 	CorsAllowOriginAnnotation  = "nginx.ingress.kubernetes.io/cors-allow-origin"
@@ -85,8 +103,61 @@ const (
 	ProxySSLProtocolsAnnotation   = "nginx.ingress.kubernetes.io/proxy-ssl-protocols"
 
 	// Affinity annotations
-	AffinityAnnotation             = "nginx.ingress.kubernetes.io/affinity"
-	SessionCookieExpiresAnnotation = "nginx.ingress.kubernetes.io/session-cookie-expires"
+	AffinityAnnotation                     = "nginx.ingress.kubernetes.io/affinity"
+	AffinityModeAnnotation                 = "nginx.ingress.kubernetes.io/affinity-mode"
+	SessionCookieNameAnnotation            = "nginx.ingress.kubernetes.io/session-cookie-name"
+	SessionCookiePathAnnotation            = "nginx.ingress.kubernetes.io/session-cookie-path"
+	SessionCookieExpiresAnnotation         = "nginx.ingress.kubernetes.io/session-cookie-expires"
+	SessionCookieMaxAgeAnnotation          = "nginx.ingress.kubernetes.io/session-cookie-max-age"
+	SessionCookieSameSiteAnnotation        = "nginx.ingress.kubernetes.io/session-cookie-samesite"
+	SessionCookieSecureAnnotation          = "nginx.ingress.kubernetes.io/session-cookie-secure"
+	SessionCookieChangeOnFailureAnnotation = "nginx.ingress.kubernetes.io/session-cookie-change-on-failure"
+
+	// Load balancing annotations
+	LoadBalanceAnnotation              = "nginx.ingress.kubernetes.io/load-balance"
+	UpstreamHashByAnnotation           = "nginx.ingress.kubernetes.io/upstream-hash-by"
+	UpstreamHashBySubsetAnnotation     = "nginx.ingress.kubernetes.io/upstream-hash-by-subset"
+	UpstreamHashBySubsetSizeAnnotation = "nginx.ingress.kubernetes.io/upstream-hash-by-subset-size"
+
+	// External authentication annotations
+	AuthURLAnnotation             = "nginx.ingress.kubernetes.io/auth-url"
+	AuthMethodAnnotation          = "nginx.ingress.kubernetes.io/auth-method"
+	AuthResponseHeadersAnnotation = "nginx.ingress.kubernetes.io/auth-response-headers"
+	AuthSigninAnnotation          = "nginx.ingress.kubernetes.io/auth-signin"
+	AuthCacheKeyAnnotation        = "nginx.ingress.kubernetes.io/auth-cache-key"
+	AuthCacheDurationAnnotation   = "nginx.ingress.kubernetes.io/auth-cache-duration"
+
+	// Basic authentication annotations
+	AuthTypeAnnotation       = "nginx.ingress.kubernetes.io/auth-type"
+	AuthSecretAnnotation     = "nginx.ingress.kubernetes.io/auth-secret"      //nolint:gosec // This is an annotation key, not a secret
+	AuthSecretTypeAnnotation = "nginx.ingress.kubernetes.io/auth-secret-type" //nolint:gosec // This is an annotation key, not a secret
+	AuthRealmAnnotation      = "nginx.ingress.kubernetes.io/auth-realm"
+
+	// Client certificate authentication annotations
+	AuthTLSSecretAnnotation                    = "nginx.ingress.kubernetes.io/auth-tls-secret" //nolint:gosec // This is an annotation key, not a secret
+	AuthTLSVerifyClientAnnotation              = "nginx.ingress.kubernetes.io/auth-tls-verify-client"
+	AuthTLSVerifyDepthAnnotation               = "nginx.ingress.kubernetes.io/auth-tls-verify-depth"
+	AuthTLSPassCertificateToUpstreamAnnotation = "nginx.ingress.kubernetes.io/auth-tls-pass-certificate-to-upstream"
+
+	// Snippet annotations
+	ConfigurationSnippetAnnotation = "nginx.ingress.kubernetes.io/configuration-snippet"
+	ServerSnippetAnnotation        = "nginx.ingress.kubernetes.io/server-snippet"
+
+	// Custom error annotations
+	CustomHTTPErrorsAnnotation = "nginx.ingress.kubernetes.io/custom-http-errors"
+	DefaultBackendAnnotation   = "nginx.ingress.kubernetes.io/default-backend"
+
+	// Mirror annotations
+	MirrorTargetAnnotation      = "nginx.ingress.kubernetes.io/mirror-target"
+	MirrorRequestBodyAnnotation = "nginx.ingress.kubernetes.io/mirror-request-body"
+	MirrorHostAnnotation        = "nginx.ingress.kubernetes.io/mirror-host"
+
+	// Rate limiting annotations
+	LimitRPSAnnotation             = "nginx.ingress.kubernetes.io/limit-rps"
+	LimitRPMAnnotation             = "nginx.ingress.kubernetes.io/limit-rpm"
+	LimitConnectionsAnnotation     = "nginx.ingress.kubernetes.io/limit-connections"
+	LimitBurstMultiplierAnnotation = "nginx.ingress.kubernetes.io/limit-burst-multiplier"
+	LimitWhitelistAnnotation       = "nginx.ingress.kubernetes.io/limit-whitelist"
 )
 
 const ingressNGINXAnnotationsPrefix = "nginx.ingress.kubernetes.io/"
@@ -95,47 +166,96 @@ const ingressNGINXAnnotationsPrefix = "nginx.ingress.kubernetes.io/"
 // it will be converted. Rather, if it isn't converted, the
 // error will be logged elsewhere.
 var parsedAnnotations = map[string]struct{}{
-	CanaryAnnotation:                {},
-	CanaryWeightAnnotation:          {},
-	CanaryWeightTotalAnnotation:     {},
-	CanaryByHeader:                  {},
-	CanaryByHeaderValue:             {},
-	CanaryByHeaderPattern:           {},
-	CanaryByCookie:                  {},
-	RewriteTargetAnnotation:         {},
-	PermanentRedirectAnnotation:     {},
-	PermanentRedirectCodeAnnotation: {},
-	TemporalRedirectAnnotation:      {},
-	TemporalRedirectCodeAnnotation:  {},
-	ProxyRedirectFromAnnotation:     {},
-	ProxyRedirectToAnnotation:       {},
-	XForwardedPrefixAnnotation:      {},
-	UpstreamVhostAnnotation:         {},
-	ConnectionProxyHeaderAnnotation: {},
-	CustomHeadersAnnotation:         {},
-	ProxyConnectTimeoutAnnotation:   {},
-	ProxySendTimeoutAnnotation:      {},
-	ProxyReadTimeoutAnnotation:      {},
-	ProxyBodySizeAnnotation:         {},
-	ClientBodyBufferSizeAnnotation:  {},
-	BackendProtocolAnnotation:       {},
-	UseRegexAnnotation:              {},
-	SSLRedirectAnnotation:           {},
-	EnableCorsAnnotation:            {},
-	CorsAllowOriginAnnotation:       {},
-	CorsAllowHeadersAnnotation:      {},
-	CorsAllowMethodsAnnotation:      {},
-	CorsAllowCredentialsAnnotation:  {},
-	CorsExposeHeadersAnnotation:     {},
-	CorsMaxAgeAnnotation:            {},
-	WhiteListSourceRangeAnnotation:  {},
-	DenyListSourceRangeAnnotation:   {},
-	ProxySSLVerifyAnnotation:        {},
-	ProxySSLSecretAnnotation:        {},
-	ProxySSLNameAnnotation:          {},
-	ProxySSLServerNameAnnotation:    {},
-	ProxySSLVerifyDepthAnnotation:   {},
-	ProxySSLProtocolsAnnotation:     {},
-	AffinityAnnotation:              {},
-	SessionCookieExpiresAnnotation:  {},
+	CanaryAnnotation:                           {},
+	CanaryWeightAnnotation:                     {},
+	CanaryWeightTotalAnnotation:                {},
+	CanaryByHeader:                             {},
+	CanaryByHeaderValue:                        {},
+	CanaryByHeaderPattern:                      {},
+	CanaryByCookie:                             {},
+	RewriteTargetAnnotation:                    {},
+	PermanentRedirectAnnotation:                {},
+	PermanentRedirectCodeAnnotation:            {},
+	TemporalRedirectAnnotation:                 {},
+	TemporalRedirectCodeAnnotation:             {},
+	ProxyRedirectFromAnnotation:                {},
+	ProxyRedirectToAnnotation:                  {},
+	AppRootAnnotation:                          {},
+	XForwardedPrefixAnnotation:                 {},
+	UpstreamVhostAnnotation:                    {},
+	ConnectionProxyHeaderAnnotation:            {},
+	CustomHeadersAnnotation:                    {},
+	ProxyConnectTimeoutAnnotation:              {},
+	ProxySendTimeoutAnnotation:                 {},
+	ProxyReadTimeoutAnnotation:                 {},
+	ProxyNextUpstreamAnnotation:                {},
+	ProxyNextUpstreamTriesAnnotation:           {},
+	ProxyNextUpstreamTimeoutAnnotation:         {},
+	ProxyBodySizeAnnotation:                    {},
+	ClientBodyBufferSizeAnnotation:             {},
+	ProxyRequestBufferingAnnotation:            {},
+	ProxyBufferingAnnotation:                   {},
+	ProxyBufferSizeAnnotation:                  {},
+	ProxyMaxTempFileSizeAnnotation:             {},
+	ProxyHTTPVersionAnnotation:                 {},
+	BackendProtocolAnnotation:                  {},
+	UseRegexAnnotation:                         {},
+	SSLRedirectAnnotation:                      {},
+	SSLPassthroughAnnotation:                   {},
+	SSLCiphersAnnotation:                       {},
+	SSLPreferServerCiphersAnnotation:           {},
+	EnableCorsAnnotation:                       {},
+	CorsAllowOriginAnnotation:                  {},
+	CorsAllowHeadersAnnotation:                 {},
+	CorsAllowMethodsAnnotation:                 {},
+	CorsAllowCredentialsAnnotation:             {},
+	CorsExposeHeadersAnnotation:                {},
+	CorsMaxAgeAnnotation:                       {},
+	WhiteListSourceRangeAnnotation:             {},
+	DenyListSourceRangeAnnotation:              {},
+	ProxySSLVerifyAnnotation:                   {},
+	ProxySSLSecretAnnotation:                   {},
+	ProxySSLNameAnnotation:                     {},
+	ProxySSLServerNameAnnotation:               {},
+	ProxySSLVerifyDepthAnnotation:              {},
+	ProxySSLProtocolsAnnotation:                {},
+	AffinityAnnotation:                         {},
+	SessionCookieExpiresAnnotation:             {},
+	AffinityModeAnnotation:                     {},
+	SessionCookieNameAnnotation:                {},
+	SessionCookiePathAnnotation:                {},
+	SessionCookieMaxAgeAnnotation:              {},
+	SessionCookieSameSiteAnnotation:            {},
+	SessionCookieSecureAnnotation:              {},
+	SessionCookieChangeOnFailureAnnotation:     {},
+	LoadBalanceAnnotation:                      {},
+	UpstreamHashByAnnotation:                   {},
+	UpstreamHashBySubsetAnnotation:             {},
+	UpstreamHashBySubsetSizeAnnotation:         {},
+	AuthURLAnnotation:                          {},
+	AuthMethodAnnotation:                       {},
+	AuthResponseHeadersAnnotation:              {},
+	AuthSigninAnnotation:                       {},
+	AuthCacheKeyAnnotation:                     {},
+	AuthCacheDurationAnnotation:                {},
+	AuthTypeAnnotation:                         {},
+	AuthSecretAnnotation:                       {},
+	AuthSecretTypeAnnotation:                   {},
+	AuthRealmAnnotation:                        {},
+	AuthTLSSecretAnnotation:                    {},
+	AuthTLSVerifyClientAnnotation:              {},
+	AuthTLSVerifyDepthAnnotation:               {},
+	AuthTLSPassCertificateToUpstreamAnnotation: {},
+	ConfigurationSnippetAnnotation:             {},
+	ServerSnippetAnnotation:                    {},
+	CustomHTTPErrorsAnnotation:                 {},
+	DefaultBackendAnnotation:                   {},
+	MirrorTargetAnnotation:                     {},
+	MirrorRequestBodyAnnotation:                {},
+	MirrorHostAnnotation:                       {},
+	LimitRPSAnnotation:                         {},
+	LimitRPMAnnotation:                         {},
+	LimitConnectionsAnnotation:                 {},
+	LimitBurstMultiplierAnnotation:             {},
+	LimitWhitelistAnnotation:                   {},
 }
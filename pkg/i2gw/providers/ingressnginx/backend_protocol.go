@@ -17,14 +17,30 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
 	"strings"
 
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// h2cAppProtocol is the appProtocol of the Service ports serving HTTP/2 over cleartext.
+	h2cAppProtocol = "kubernetes.io/h2c"
+	// webSocketAppProtocol is the appProtocol of the Service ports serving WebSocket over cleartext.
+	webSocketAppProtocol = "kubernetes.io/ws"
+	// secureWebSocketAppProtocol is the appProtocol of the Service ports serving WebSocket over TLS.
+	secureWebSocketAppProtocol = "kubernetes.io/wss"
 )
 
 // createBackendTLSPolicies inspects ingresses for backend-protocol annotations
@@ -66,3 +82,156 @@ func createBackendTLSPolicies(_ notifications.NotifyFunc, ingresses []networking
 	}
 	return errList
 }
+
+// backendAppProtocol returns the appProtocol of the Service ports the Ingress
+// forwards to, or "" when its backend protocol doesn't need one. WebSocket
+// backends are recognized by a snippet passing the Upgrade header of the client.
+func backendAppProtocol(ing *networkingv1.Ingress) string {
+	webSocket := webSocketAppProtocol
+	switch strings.ToUpper(ing.Annotations[BackendProtocolAnnotation]) {
+	case "H2C", "GRPC":
+		return h2cAppProtocol
+	case "HTTPS":
+		webSocket = secureWebSocketAppProtocol
+	case "", "HTTP":
+	default:
+		return ""
+	}
+	for _, annotation := range []string{ConfigurationSnippetAnnotation, ServerSnippetAnnotation} {
+		for _, directive := range parseSnippet(ing.Annotations[annotation]) {
+			if !directive.block && directive.name == "proxy_set_header" && isUpgradeHeader(directive.args) &&
+				strings.EqualFold(directive.args[0], "Upgrade") {
+				return webSocket
+			}
+		}
+	}
+	return ""
+}
+
+// ingressServiceBackends returns the Service backends of the Ingress.
+func ingressServiceBackends(ing *networkingv1.Ingress) []*networkingv1.IngressServiceBackend {
+	var backends []*networkingv1.IngressServiceBackend
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+		backends = append(backends, ing.Spec.DefaultBackend.Service)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				backends = append(backends, path.Backend.Service)
+			}
+		}
+	}
+	return backends
+}
+
+// servicePort returns the port of the Service the backend port refers to. When
+// the Service isn't known, only a port number can be patched.
+func (p *Provider) servicePort(service types.NamespacedName, port networkingv1.ServiceBackendPort) (apiv1.ServicePort, bool) {
+	svc, ok := p.storage.Services[service]
+	if !ok {
+		return apiv1.ServicePort{Port: port.Number, Protocol: apiv1.ProtocolTCP}, port.Name == ""
+	}
+	for _, servicePort := range svc.Spec.Ports {
+		if (port.Name != "" && servicePort.Name == port.Name) || (port.Name == "" && servicePort.Port == port.Number) {
+			if servicePort.Protocol == "" {
+				servicePort.Protocol = apiv1.ProtocolTCP
+			}
+			return servicePort, true
+		}
+	}
+	return apiv1.ServicePort{}, false
+}
+
+// addServiceAppProtocolPatches patches the Service ports the Ingresses forward to
+// with the appProtocol their backend protocol requires, as Gateway API selects the
+// protocol of the backends with it: H2C and GRPC backends get kubernetes.io/h2c
+// and WebSocket backends kubernetes.io/ws, or kubernetes.io/wss over TLS. The
+// appProtocol already set on a port isn't changed. It returns the Ingresses whose
+// backends all get their WebSocket appProtocol.
+func (p *Provider) addServiceAppProtocolPatches(eIR *emitterir.EmitterIR) map[types.NamespacedName]bool {
+	type servicePortKey struct {
+		service types.NamespacedName
+		port    int32
+	}
+	type portAppProtocol struct {
+		appProtocol string
+		ingress     types.NamespacedName
+	}
+	patched := map[servicePortKey]portAppProtocol{}
+	patches := map[types.NamespacedName]*apiv1.Service{}
+	webSocketIngresses := map[types.NamespacedName]bool{}
+
+	for _, ing := range p.storage.Ingresses.List() {
+		appProtocol := backendAppProtocol(&ing)
+		if appProtocol == "" {
+			continue
+		}
+		ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+		allSet := true
+
+		for _, backend := range ingressServiceBackends(&ing) {
+			service := types.NamespacedName{Namespace: ing.Namespace, Name: backend.Name}
+			servicePort, ok := p.servicePort(service, backend.Port)
+			if !ok {
+				allSet = false
+				continue
+			}
+
+			if current := ptr.Deref(servicePort.AppProtocol, ""); current != "" {
+				if current != appProtocol {
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Port %d of Service %s has appProtocol %s, the backends of ingress %s require %s, it is not changed",
+						servicePort.Port, service, current, ingKey, appProtocol), &ing)
+					allSet = false
+				}
+				continue
+			}
+
+			key := servicePortKey{service: service, port: servicePort.Port}
+			if previous, found := patched[key]; found {
+				if previous.appProtocol != appProtocol {
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Port %d of Service %s gets appProtocol %s for ingress %s, the backends of ingress %s require %s",
+						servicePort.Port, service, previous.appProtocol, previous.ingress, ingKey, appProtocol), &ing)
+					allSet = false
+				}
+				continue
+			}
+			patched[key] = portAppProtocol{appProtocol: appProtocol, ingress: ingKey}
+
+			patch, found := patches[service]
+			if !found {
+				patch = &apiv1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: service.Namespace,
+						Name:      service.Name,
+					},
+				}
+				patch.SetGroupVersionKind(apiv1.SchemeGroupVersion.WithKind("Service"))
+				patches[service] = patch
+			}
+			patch.Spec.Ports = append(patch.Spec.Ports, apiv1.ServicePort{
+				Name:        servicePort.Name,
+				Port:        servicePort.Port,
+				Protocol:    servicePort.Protocol,
+				AppProtocol: ptr.To(appProtocol),
+			})
+		}
+
+		if allSet && appProtocol != h2cAppProtocol {
+			webSocketIngresses[ingKey] = true
+		}
+	}
+
+	if len(patches) > 0 && eIR.ServicePatches == nil {
+		eIR.ServicePatches = make(map[types.NamespacedName]emitterir.ServicePatchContext)
+	}
+	for service, patch := range patches {
+		slices.SortFunc(patch.Spec.Ports, func(a, b apiv1.ServicePort) int {
+			return cmp.Compare(a.Port, b.Port)
+		})
+		eIR.ServicePatches[service] = emitterir.ServicePatchContext{Service: *patch}
+	}
+	return webSocketIngresses
+}
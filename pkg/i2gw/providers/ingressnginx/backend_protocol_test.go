@@ -20,12 +20,18 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 func TestBackendProtocolFeature(t *testing.T) {
@@ -565,3 +571,138 @@ func TestBackendProtocolFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestAddServiceAppProtocolPatches(t *testing.T) {
+	ingress := func(name string, annotations map[string]string, service string, port networkingv1.ServiceBackendPort) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: service, Port: port},
+				},
+			},
+		}
+	}
+	service := func(name string, ports ...apiv1.ServicePort) *apiv1.Service {
+		return &apiv1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       apiv1.ServiceSpec{Ports: ports},
+		}
+	}
+	patch := func(name string, ports ...apiv1.ServicePort) emitterir.ServicePatchContext {
+		svc := service(name, ports...)
+		svc.SetGroupVersionKind(apiv1.SchemeGroupVersion.WithKind("Service"))
+		return emitterir.ServicePatchContext{Service: *svc}
+	}
+	h2c := map[string]string{BackendProtocolAnnotation: "H2C"}
+	webSocket := map[string]string{ConfigurationSnippetAnnotation: "proxy_set_header Upgrade $http_upgrade;\nproxy_set_header Connection $connection_upgrade;"}
+
+	secureWebSocket := map[string]string{BackendProtocolAnnotation: "HTTPS", ConfigurationSnippetAnnotation: webSocket[ConfigurationSnippetAnnotation]}
+
+	testCases := []struct {
+		name      string
+		ingresses []*networkingv1.Ingress
+		services  []*apiv1.Service
+		expected  map[types.NamespacedName]emitterir.ServicePatchContext
+		// expectedWebSocket are the Ingresses whose backends get a WebSocket appProtocol.
+		expectedWebSocket map[types.NamespacedName]bool
+	}{
+		{
+			name:      "H2C backend with a named port",
+			ingresses: []*networkingv1.Ingress{ingress("a", h2c, "svc", networkingv1.ServiceBackendPort{Name: "http"})},
+			services:  []*apiv1.Service{service("svc", apiv1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt32(8080)})},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Name: "http", Port: 80, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/h2c")}),
+			},
+		},
+		{
+			name:      "GRPC backend of an unknown Service",
+			ingresses: []*networkingv1.Ingress{ingress("a", map[string]string{BackendProtocolAnnotation: "grpc"}, "svc", networkingv1.ServiceBackendPort{Number: 9000})},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Port: 9000, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/h2c")}),
+			},
+		},
+		{
+			name:      "WebSocket backend",
+			ingresses: []*networkingv1.Ingress{ingress("a", webSocket, "svc", networkingv1.ServiceBackendPort{Number: 80})},
+			services:  []*apiv1.Service{service("svc", apiv1.ServicePort{Port: 80, Protocol: apiv1.ProtocolTCP})},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Port: 80, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/ws")}),
+			},
+			expectedWebSocket: map[types.NamespacedName]bool{{Namespace: "default", Name: "a"}: true},
+		},
+		{
+			name:      "WebSocket backend over TLS",
+			ingresses: []*networkingv1.Ingress{ingress("a", secureWebSocket, "svc", networkingv1.ServiceBackendPort{Number: 443})},
+			services:  []*apiv1.Service{service("svc", apiv1.ServicePort{Port: 443, Protocol: apiv1.ProtocolTCP})},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Port: 443, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/wss")}),
+			},
+			expectedWebSocket: map[types.NamespacedName]bool{{Namespace: "default", Name: "a"}: true},
+		},
+		{
+			name:      "WebSocket backend of a GRPCS Ingress is not patched",
+			ingresses: []*networkingv1.Ingress{ingress("a", map[string]string{BackendProtocolAnnotation: "GRPCS", ConfigurationSnippetAnnotation: webSocket[ConfigurationSnippetAnnotation]}, "svc", networkingv1.ServiceBackendPort{Number: 443})},
+		},
+		{
+			name:      "WebSocket backend with an existing appProtocol",
+			ingresses: []*networkingv1.Ingress{ingress("a", webSocket, "svc", networkingv1.ServiceBackendPort{Number: 80})},
+			services:  []*apiv1.Service{service("svc", apiv1.ServicePort{Port: 80, AppProtocol: ptr.To("http")})},
+		},
+		{
+			name:      "HTTP and HTTPS backends are not patched",
+			ingresses: []*networkingv1.Ingress{ingress("a", nil, "svc", networkingv1.ServiceBackendPort{Number: 80}), ingress("b", map[string]string{BackendProtocolAnnotation: "HTTPS"}, "svc", networkingv1.ServiceBackendPort{Number: 443})},
+		},
+		{
+			name:      "existing appProtocol is not changed",
+			ingresses: []*networkingv1.Ingress{ingress("a", h2c, "svc", networkingv1.ServiceBackendPort{Number: 80})},
+			services:  []*apiv1.Service{service("svc", apiv1.ServicePort{Port: 80, AppProtocol: ptr.To("http")})},
+		},
+		{
+			name: "first Ingress sets the appProtocol of a shared port",
+			ingresses: []*networkingv1.Ingress{
+				ingress("a", h2c, "svc", networkingv1.ServiceBackendPort{Number: 80}),
+				ingress("b", webSocket, "svc", networkingv1.ServiceBackendPort{Number: 80}),
+			},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Port: 80, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/h2c")}),
+			},
+		},
+		{
+			name: "second WebSocket Ingress of a shared port",
+			ingresses: []*networkingv1.Ingress{
+				ingress("a", webSocket, "svc", networkingv1.ServiceBackendPort{Number: 80}),
+				ingress("b", webSocket, "svc", networkingv1.ServiceBackendPort{Number: 80}),
+			},
+			expected: map[types.NamespacedName]emitterir.ServicePatchContext{
+				{Namespace: "default", Name: "svc"}: patch("svc", apiv1.ServicePort{Port: 80, Protocol: apiv1.ProtocolTCP, AppProtocol: ptr.To("kubernetes.io/ws")}),
+			},
+			expectedWebSocket: map[types.NamespacedName]bool{{Namespace: "default", Name: "a"}: true, {Namespace: "default", Name: "b"}: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Provider{storage: newResourcesStorage(), notify: notifications.NoopNotify}
+			ingresses := map[types.NamespacedName]*networkingv1.Ingress{}
+			for _, ing := range tc.ingresses {
+				ingresses[types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}] = ing
+			}
+			p.storage.Ingresses.FromMap(ingresses)
+			p.storage.Services = map[types.NamespacedName]*apiv1.Service{}
+			for _, svc := range tc.services {
+				p.storage.Services[types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}] = svc
+			}
+
+			eIR := emitterir.EmitterIR{}
+			webSocketIngresses := p.addServiceAppProtocolPatches(&eIR)
+
+			if diff := cmp.Diff(tc.expected, eIR.ServicePatches, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected Service patches (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedWebSocket, webSocketIngresses, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected WebSocket Ingresses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// authFileSecretType is the default auth-secret-type: the Secret holds
+	// an htpasswd file in its "auth" key.
+	authFileSecretType = "auth-file"
+	// authMapSecretType Secrets hold one key per user, whose value is the
+	// hashed password.
+	authMapSecretType = "auth-map"
+
+	authFileSecretKey = "auth"
+)
+
+// applyBasicAuthToEmitterIR reads the ingress-nginx basic authentication annotations from ProviderIR
+// sources and stores provider-neutral basic authentication intent into EmitterIR, which will later
+// be converted by each custom emitter.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/auth-type
+// - nginx.ingress.kubernetes.io/auth-secret
+// - nginx.ingress.kubernetes.io/auth-secret-type
+// - nginx.ingress.kubernetes.io/auth-realm
+func (p *Provider) applyBasicAuthToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.BasicAuth{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			basicAuth, found := parsed[ingKey]
+			if !found {
+				basicAuth = p.parseBasicAuth(ing)
+				parsed[ingKey] = basicAuth
+			}
+			if basicAuth == nil {
+				continue
+			}
+
+			if eRouteCtx.BasicAuthByRuleIdx == nil {
+				eRouteCtx.BasicAuthByRuleIdx = make(map[int]*emitterir.BasicAuth)
+			}
+			eRouteCtx.BasicAuthByRuleIdx[ruleIdx] = basicAuth
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseBasicAuth returns the basic authentication intent of the Ingress,
+// or nil if it has none or its configuration can't be converted.
+func (p *Provider) parseBasicAuth(ing *networkingv1.Ingress) *emitterir.BasicAuth {
+	authType := strings.ToLower(strings.TrimSpace(ing.Annotations[AuthTypeAnnotation]))
+	switch authType {
+	case "":
+		return nil
+	case "basic":
+	case "digest":
+		p.notify(notifications.ErrorNotification, "auth-type \"digest\" is not supported, digest authentication can't be converted", ing)
+		return nil
+	default:
+		p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid auth-type annotation %q, skipping basic authentication", authType), ing)
+		return nil
+	}
+
+	secretRef, hasSecret := annotationObjectRef(ing, AuthSecretAnnotation)
+	if !hasSecret {
+		p.notify(notifications.ErrorNotification, "auth-type \"basic\" requires the auth-secret annotation, skipping basic authentication", ing)
+		return nil
+	}
+
+	basicAuth := emitterir.BasicAuth{
+		Secret: secretRef,
+		Realm:  strings.TrimSpace(ing.Annotations[AuthRealmAnnotation]),
+	}
+
+	secretType := strings.TrimSpace(ing.Annotations[AuthSecretTypeAnnotation])
+	switch secretType {
+	case "", authFileSecretType:
+		basicAuth.SecretKey = authFileSecretKey
+	case authMapSecretType:
+	default:
+		p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid auth-secret-type annotation %q, skipping basic authentication", secretType), ing)
+		return nil
+	}
+
+	if secret, found := p.storage.Secrets[secretRef]; found {
+		users, err := htpasswdUsers(secret, basicAuth.SecretKey)
+		if err != nil {
+			p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid auth-secret %s: %v, skipping basic authentication", secretRef, err), ing)
+			return nil
+		}
+		basicAuth.Users = users
+	} else {
+		p.notify(notifications.WarningNotification, fmt.Sprintf("auth-secret %s was not found, its credentials can't be converted", secretRef), ing)
+	}
+
+	source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+	var paths []*field.Path
+	for _, annotation := range []string{AuthTypeAnnotation, AuthSecretAnnotation, AuthSecretTypeAnnotation, AuthRealmAnnotation} {
+		if _, ok := ing.Annotations[annotation]; ok {
+			paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+		}
+	}
+	basicAuth.Metadata = emitterir.NewExtensionFeatureMetadata(
+		source,
+		paths,
+		"Basic authentication is not supported, the route is left unauthenticated",
+	)
+
+	return &basicAuth
+}
+
+// htpasswdUsers returns the "user:hashed-password" entries stored in the
+// Secret, either as an htpasswd file in key or, when key is empty, as one
+// key per user.
+func htpasswdUsers(secret *apiv1.Secret, key string) ([]string, error) {
+	// Secrets read from files may still use stringData.
+	data := map[string]string{}
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		data[k] = v
+	}
+
+	var users []string
+	if key != "" {
+		htpasswd, ok := data[key]
+		if !ok {
+			return nil, fmt.Errorf("the %q key is missing", key)
+		}
+		for _, line := range strings.Split(htpasswd, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if !strings.Contains(line, ":") {
+				return nil, fmt.Errorf("%q is not an htpasswd entry", line)
+			}
+			users = append(users, line)
+		}
+	} else {
+		for user, password := range data {
+			users = append(users, fmt.Sprintf("%s:%s", user, strings.TrimSpace(password)))
+		}
+		sort.Strings(users)
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("no user is defined")
+	}
+	return users, nil
+}
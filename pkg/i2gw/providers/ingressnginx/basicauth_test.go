@@ -0,0 +1,189 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestApplyBasicAuthToEmitterIR(t *testing.T) {
+	secrets := map[types.NamespacedName]*apiv1.Secret{
+		{Namespace: "default", Name: "htpasswd"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "htpasswd"},
+			Data: map[string][]byte{
+				"auth": []byte("# admins\nfoo:{SHA}C+7Hteo/D9vJXQ3UfzxbwnXaijM=\n\nbar:$apr1$xyz$abc\n"),
+			},
+		},
+		{Namespace: "auth", Name: "users"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "auth", Name: "users"},
+			Data: map[string][]byte{
+				"zed": []byte("{SHA}C+7Hteo/D9vJXQ3UfzxbwnXaijM="),
+				"amy": []byte("{SHA}YlP+yy8uQZy+Fp9Qw+IiRNH5MT4=\n"),
+			},
+		},
+		{Namespace: "default", Name: "empty"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "empty"},
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedBasicAuth     *emitterir.BasicAuth
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "auth-file secret",
+			annotations: map[string]string{
+				AuthTypeAnnotation:   "basic",
+				AuthSecretAnnotation: "htpasswd",
+				AuthRealmAnnotation:  "Authentication Required",
+			},
+			expectedBasicAuth: &emitterir.BasicAuth{
+				Secret:    types.NamespacedName{Namespace: "default", Name: "htpasswd"},
+				SecretKey: "auth",
+				Realm:     "Authentication Required",
+				Users:     []string{"foo:{SHA}C+7Hteo/D9vJXQ3UfzxbwnXaijM=", "bar:$apr1$xyz$abc"},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "auth-map secret in another namespace",
+			annotations: map[string]string{
+				AuthTypeAnnotation:       "basic",
+				AuthSecretAnnotation:     "auth/users",
+				AuthSecretTypeAnnotation: "auth-map",
+			},
+			expectedBasicAuth: &emitterir.BasicAuth{
+				Secret: types.NamespacedName{Namespace: "auth", Name: "users"},
+				Users:  []string{"amy:{SHA}YlP+yy8uQZy+Fp9Qw+IiRNH5MT4=", "zed:{SHA}C+7Hteo/D9vJXQ3UfzxbwnXaijM="},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "missing secret",
+			annotations: map[string]string{
+				AuthTypeAnnotation:   "basic",
+				AuthSecretAnnotation: "missing",
+			},
+			expectedBasicAuth: &emitterir.BasicAuth{
+				Secret:    types.NamespacedName{Namespace: "default", Name: "missing"},
+				SecretKey: "auth",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "secret without credentials",
+			annotations: map[string]string{
+				AuthTypeAnnotation:   "basic",
+				AuthSecretAnnotation: "empty",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "digest authentication",
+			annotations: map[string]string{
+				AuthTypeAnnotation:   "digest",
+				AuthSecretAnnotation: "htpasswd",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "missing auth-secret",
+			annotations: map[string]string{
+				AuthTypeAnnotation: "basic",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "basic-auth",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			route := gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+				},
+			}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: route,
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing}},
+							{{Ingress: &ing}},
+						},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {HTTPRoute: route},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify, storage: &storage{Secrets: secrets}}
+
+			p.applyBasicAuthToEmitterIR(pIR, &eIR)
+
+			result := eIR.HTTPRoutes[key].BasicAuthByRuleIdx
+			if tc.expectedBasicAuth == nil {
+				if result != nil {
+					t.Fatalf("Expected no BasicAuth, got %v", result)
+				}
+			} else {
+				if len(result) != 2 {
+					t.Fatalf("Expected BasicAuth for 2 rules, got %d", len(result))
+				}
+				for idx, basicAuth := range result {
+					if diff := cmp.Diff(tc.expectedBasicAuth, basicAuth, cmpopts.IgnoreFields(emitterir.BasicAuth{}, "Metadata")); diff != "" {
+						t.Errorf("Unexpected BasicAuth for rule %d (-want +got):\n%s", idx, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
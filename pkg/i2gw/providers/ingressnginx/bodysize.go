@@ -1,165 +0,0 @@
-/*
-Copyright The Kubernetes Authors.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package ingressnginx
-
-import (
-	"fmt"
-	"regexp"
-	"strings"
-
-	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
-	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
-	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
-	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/apimachinery/pkg/util/validation/field"
-)
-
-// Ref: https://github.com/kubernetes/ingress-nginx/blob/main/internal/ingress/annotations/parser/validators.go#L57
-var nginxSizeRegex = regexp.MustCompile(`^(?i)(\d+)([bkmg]?)$`)
-
-// convertNginxSizeToK8sQuantity converts nginx size format to Kubernetes resource.Quantity format.
-//
-// NGINX uses binary units (e.g. k = 2^10) while in Kubernetes, k = 1000 and Ki = 2^10.
-func convertNginxSizeToK8sQuantity(nginxSize string) (string, error) {
-	nginxSize = strings.TrimSpace(nginxSize)
-
-	matches := nginxSizeRegex.FindStringSubmatch(nginxSize)
-	if matches == nil {
-		return "", fmt.Errorf("invalid nginx size format: %q", nginxSize)
-	}
-
-	number := matches[1]
-	unit := matches[2]
-
-	// Convert nginx unit to K8s Quantity unit
-	switch strings.ToLower(unit) {
-	case "b", "":
-		return number, nil
-	case "k":
-		return number + "Ki", nil
-	case "m":
-		return number + "Mi", nil
-	case "g":
-		return number + "Gi", nil
-	default:
-		return "", fmt.Errorf("unsupported nginx size unit: %q", unit)
-	}
-}
-
-// applyBodySizeToEmitterIR reads ingress-nginx body size annotations from ProviderIR sources and stores
-// provider-neutral body size intent into EmitterIR, which will later be converted by each custom emitter.
-//
-// Currently supported annotations are:
-// - nginx.ingress.kubernetes.io/proxy-body-size
-// - nginx.ingress.kubernetes.io/client-body-buffer-size
-func (p *Provider) applyBodySizeToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
-
-	for key, pRouteCtx := range pIR.HTTPRoutes {
-		eRouteCtx, ok := eIR.HTTPRoutes[key]
-		if !ok {
-			continue
-		}
-
-		for ruleIdx := range eRouteCtx.Spec.Rules {
-			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
-				continue
-			}
-			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
-			if ing == nil {
-				continue
-			}
-
-			var (
-				maxSize    *resource.Quantity
-				bufferSize *resource.Quantity
-			)
-			parsedAnnotations := make([]string, 0, 2)
-
-			// handle proxy-body-size
-			if val, ok := ing.Annotations[ProxyBodySizeAnnotation]; ok && val != "" {
-				parsedAnnotations = append(parsedAnnotations, ProxyBodySizeAnnotation)
-				k8sSize, err := convertNginxSizeToK8sQuantity(val)
-				if err != nil {
-					p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid proxy-body-size annotation %q: %v, skipping body size",
-						val, err), ing)
-					continue
-				}
-
-				quantity, err := resource.ParseQuantity(k8sSize)
-				if err != nil {
-					p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid proxy-body-size annotation %q: %v, skipping body size",
-						val, err), ing)
-					continue
-				}
-				maxSize = &quantity
-			}
-
-			// handle client-body-buffer-size
-			if val, ok := ing.Annotations[ClientBodyBufferSizeAnnotation]; ok && val != "" {
-				parsedAnnotations = append(parsedAnnotations, ClientBodyBufferSizeAnnotation)
-				k8sSize, err := convertNginxSizeToK8sQuantity(val)
-				if err != nil {
-					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid client-body-buffer-size annotation %q: %v, skipping buffer size",
-						val, err), ing)
-					continue
-				}
-
-				quantity, err := resource.ParseQuantity(k8sSize)
-				if err != nil {
-					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid client-body-buffer-size annotation %q: %v, skipping buffer size",
-						val, err), ing)
-					continue
-				}
-				bufferSize = &quantity
-			}
-
-			if maxSize == nil && bufferSize == nil {
-				continue
-			}
-
-			if eRouteCtx.BodySizeByRuleIdx == nil {
-				eRouteCtx.BodySizeByRuleIdx = make(map[int]*emitterir.BodySize)
-			}
-
-			bodySizeIR := emitterir.BodySize{}
-			{
-				source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
-				message := "Most Gateway API implementations have reasonable body size and buffering defaults"
-				paths := make([]*field.Path, len(parsedAnnotations))
-				for i, ann := range parsedAnnotations {
-					paths[i] = field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", ann))
-				}
-				bodySizeIR.Metadata = emitterir.NewExtensionFeatureMetadata(
-					source,
-					paths,
-					message,
-				)
-			}
-
-			if maxSize != nil {
-				bodySizeIR.MaxSize = maxSize
-			}
-			if bufferSize != nil {
-				bodySizeIR.BufferSize = bufferSize
-			}
-
-			eRouteCtx.BodySizeByRuleIdx[ruleIdx] = &bodySizeIR
-		}
-
-		eIR.HTTPRoutes[key] = eRouteCtx
-	}
-}
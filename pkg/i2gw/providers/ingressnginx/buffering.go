@@ -0,0 +1,244 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+// Ref: https://github.com/kubernetes/ingress-nginx/blob/main/internal/ingress/annotations/parser/validators.go#L57
+var nginxSizeRegex = regexp.MustCompile(`^(?i)(\d+)([bkmg]?)$`)
+
+// convertNginxSizeToK8sQuantity converts nginx size format to Kubernetes resource.Quantity format.
+//
+// NGINX uses binary units (e.g. k = 2^10) while in Kubernetes, k = 1000 and Ki = 2^10.
+func convertNginxSizeToK8sQuantity(nginxSize string) (string, error) {
+	nginxSize = strings.TrimSpace(nginxSize)
+
+	matches := nginxSizeRegex.FindStringSubmatch(nginxSize)
+	if matches == nil {
+		return "", fmt.Errorf("invalid nginx size format: %q", nginxSize)
+	}
+
+	number := matches[1]
+	unit := matches[2]
+
+	// Convert nginx unit to K8s Quantity unit
+	switch strings.ToLower(unit) {
+	case "b", "":
+		return number, nil
+	case "k":
+		return number + "Ki", nil
+	case "m":
+		return number + "Mi", nil
+	case "g":
+		return number + "Gi", nil
+	default:
+		return "", fmt.Errorf("unsupported nginx size unit: %q", unit)
+	}
+}
+
+// defaultProxyBodySize is the default maximum request body size of ingress-nginx,
+// which the request buffers are limited to.
+const defaultProxyBodySize = "1Mi"
+
+// parseNginxSize parses an nginx size as a Kubernetes resource.Quantity.
+func parseNginxSize(nginxSize string) (*resource.Quantity, error) {
+	k8sSize, err := convertNginxSizeToK8sQuantity(nginxSize)
+	if err != nil {
+		return nil, err
+	}
+	quantity, err := resource.ParseQuantity(k8sSize)
+	if err != nil {
+		return nil, err
+	}
+	return &quantity, nil
+}
+
+// parseNginxSwitch parses an nginx "on" or "off" value.
+func parseNginxSwitch(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on":
+		return true, true
+	case "off":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// applyBufferingToEmitterIR reads ingress-nginx body size and buffering annotations from ProviderIR
+// sources and stores provider-neutral buffering intent into EmitterIR, which will later be converted
+// by each custom emitter.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/proxy-body-size
+// - nginx.ingress.kubernetes.io/client-body-buffer-size
+// - nginx.ingress.kubernetes.io/proxy-request-buffering
+// - nginx.ingress.kubernetes.io/proxy-buffering
+// - nginx.ingress.kubernetes.io/proxy-buffer-size
+// - nginx.ingress.kubernetes.io/proxy-max-temp-file-size
+// - nginx.ingress.kubernetes.io/proxy-http-version
+func (p *Provider) applyBufferingToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	defaults := p.storage.controllerDefaults()
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			bufferingIR := emitterir.Buffering{}
+			parsedAnnotations := make([]string, 0, 7)
+
+			// handle proxy-body-size
+			if val, ok := defaults.annotation(ing, ProxyBodySizeAnnotation); ok && val != "" {
+				parsedAnnotations = append(parsedAnnotations, ProxyBodySizeAnnotation)
+				quantity, err := parseNginxSize(val)
+				if err != nil {
+					source, obj := defaults.source(ing, ProxyBodySizeAnnotation)
+					p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid proxy-body-size %q from %s: %v, skipping body size",
+						val, source, err), obj)
+					continue
+				}
+				bufferingIR.MaxSize = quantity
+			}
+
+			// handle client-body-buffer-size
+			if val, ok := defaults.annotation(ing, ClientBodyBufferSizeAnnotation); ok && val != "" {
+				parsedAnnotations = append(parsedAnnotations, ClientBodyBufferSizeAnnotation)
+				quantity, err := parseNginxSize(val)
+				if err != nil {
+					source, obj := defaults.source(ing, ClientBodyBufferSizeAnnotation)
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid client-body-buffer-size %q from %s: %v, skipping buffer size",
+						val, source, err), obj)
+					continue
+				}
+				bufferingIR.BufferSize = quantity
+			}
+
+			// handle proxy-request-buffering and proxy-buffering
+			for _, toggle := range []struct {
+				annotation string
+				enabled    **bool
+			}{
+				{ProxyRequestBufferingAnnotation, &bufferingIR.RequestBuffering},
+				{ProxyBufferingAnnotation, &bufferingIR.ResponseBuffering},
+			} {
+				annotation := toggle.annotation
+				val, _ := defaults.annotation(ing, annotation)
+				if val == "" {
+					continue
+				}
+				parsedAnnotations = append(parsedAnnotations, annotation)
+				enabled, valid := parseNginxSwitch(val)
+				if !valid {
+					source, obj := defaults.source(ing, annotation)
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid %q from %s, expected on or off, it is ignored",
+						val, source), obj)
+					continue
+				}
+				*toggle.enabled = ptr.To(enabled)
+			}
+
+			// handle proxy-buffer-size
+			if val, _ := defaults.annotation(ing, ProxyBufferSizeAnnotation); val != "" {
+				parsedAnnotations = append(parsedAnnotations, ProxyBufferSizeAnnotation)
+				quantity, err := parseNginxSize(val)
+				if err != nil {
+					source, obj := defaults.source(ing, ProxyBufferSizeAnnotation)
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid proxy-buffer-size %q from %s: %v, it is ignored",
+						val, source, err), obj)
+				} else {
+					bufferingIR.ResponseBufferSize = quantity
+				}
+			}
+
+			// handle proxy-max-temp-file-size: the implementations don't write buffered
+			// responses to disk, like with a size of 0, so it is only validated.
+			if val, _ := defaults.annotation(ing, ProxyMaxTempFileSizeAnnotation); val != "" {
+				parsedAnnotations = append(parsedAnnotations, ProxyMaxTempFileSizeAnnotation)
+				if _, err := parseNginxSize(val); err != nil {
+					source, obj := defaults.source(ing, ProxyMaxTempFileSizeAnnotation)
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid proxy-max-temp-file-size %q from %s: %v, it is ignored",
+						val, source, err), obj)
+				}
+			}
+
+			// handle proxy-http-version
+			if val, _ := defaults.annotation(ing, ProxyHTTPVersionAnnotation); val != "" {
+				parsedAnnotations = append(parsedAnnotations, ProxyHTTPVersionAnnotation)
+				if val == "1.0" || val == "1.1" {
+					bufferingIR.HTTPVersion = val
+				} else {
+					source, obj := defaults.source(ing, ProxyHTTPVersionAnnotation)
+					p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid proxy-http-version %q from %s, expected 1.0 or 1.1, it is ignored",
+						val, source), obj)
+				}
+			}
+
+			if reflect.DeepEqual(bufferingIR, emitterir.Buffering{}) {
+				continue
+			}
+
+			// The request buffers are limited to the default body size of ingress-nginx.
+			if ptr.Deref(bufferingIR.RequestBuffering, false) && bufferingIR.MaxSize == nil && bufferingIR.BufferSize == nil {
+				bufferingIR.MaxSize = ptr.To(resource.MustParse(defaultProxyBodySize))
+			}
+
+			{
+				source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+				message := "Most Gateway API implementations have reasonable body size and buffering defaults"
+				paths := make([]*field.Path, len(parsedAnnotations))
+				for i, ann := range parsedAnnotations {
+					paths[i] = defaults.path(ing, ann)
+				}
+				bufferingIR.Metadata = emitterir.NewExtensionFeatureMetadata(
+					source,
+					paths,
+					message,
+				)
+			}
+
+			if eRouteCtx.BufferingByRuleIdx == nil {
+				eRouteCtx.BufferingByRuleIdx = make(map[int]*emitterir.Buffering)
+			}
+			eRouteCtx.BufferingByRuleIdx[ruleIdx] = &bufferingIR
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
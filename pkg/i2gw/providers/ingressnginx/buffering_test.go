@@ -19,12 +19,16 @@ package ingressnginx
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -117,17 +121,17 @@ func TestConvertNginxSizeToK8sQuantity(t *testing.T) {
 	}
 }
 
-func TestApplyBodySizeToEmitterIR_SetMaxSize(t *testing.T) {
+func TestApplyBufferingToEmitterIR_SetMaxSize(t *testing.T) {
 	key := types.NamespacedName{Namespace: "default", Name: "route"}
 	annotations := map[string]string{
 		ProxyBodySizeAnnotation: "10m",
 	}
-	pIR, eIR := setupBodySizeTest(key, annotations)
+	pIR, eIR := setupBufferingTest(key, annotations)
 
 	p := &Provider{notify: notifications.NoopNotify}
-	p.applyBodySizeToEmitterIR(pIR, &eIR)
+	p.applyBufferingToEmitterIR(pIR, &eIR)
 
-	bodySizeIR := eIR.HTTPRoutes[key].BodySizeByRuleIdx[0]
+	bodySizeIR := eIR.HTTPRoutes[key].BufferingByRuleIdx[0]
 	if bodySizeIR == nil {
 		t.Fatalf("expected body size IR to be set for rule index 0")
 	}
@@ -136,17 +140,17 @@ func TestApplyBodySizeToEmitterIR_SetMaxSize(t *testing.T) {
 	}
 }
 
-func TestApplyBodySizeToEmitterIR_SetBufferSize(t *testing.T) {
+func TestApplyBufferingToEmitterIR_SetBufferSize(t *testing.T) {
 	key := types.NamespacedName{Namespace: "default", Name: "route"}
 	annotations := map[string]string{
 		ClientBodyBufferSizeAnnotation: "10m",
 	}
-	pIR, eIR := setupBodySizeTest(key, annotations)
+	pIR, eIR := setupBufferingTest(key, annotations)
 
 	p := &Provider{notify: notifications.NoopNotify}
-	p.applyBodySizeToEmitterIR(pIR, &eIR)
+	p.applyBufferingToEmitterIR(pIR, &eIR)
 
-	bodySizeIR := eIR.HTTPRoutes[key].BodySizeByRuleIdx[0]
+	bodySizeIR := eIR.HTTPRoutes[key].BufferingByRuleIdx[0]
 	if bodySizeIR == nil {
 		t.Fatalf("expected body size IR to be set for rule index 0")
 	}
@@ -155,18 +159,18 @@ func TestApplyBodySizeToEmitterIR_SetBufferSize(t *testing.T) {
 	}
 }
 
-func TestApplyBodySizeToEmitterIR_SetMaxAndBufferSize(t *testing.T) {
+func TestApplyBufferingToEmitterIR_SetMaxAndBufferSize(t *testing.T) {
 	key := types.NamespacedName{Namespace: "default", Name: "route"}
 	annotations := map[string]string{
 		ProxyBodySizeAnnotation:        "100m",
 		ClientBodyBufferSizeAnnotation: "50m",
 	}
-	pIR, eIR := setupBodySizeTest(key, annotations)
+	pIR, eIR := setupBufferingTest(key, annotations)
 
 	p := &Provider{notify: notifications.NoopNotify}
-	p.applyBodySizeToEmitterIR(pIR, &eIR)
+	p.applyBufferingToEmitterIR(pIR, &eIR)
 
-	bodySizeIR := eIR.HTTPRoutes[key].BodySizeByRuleIdx[0]
+	bodySizeIR := eIR.HTTPRoutes[key].BufferingByRuleIdx[0]
 	if bodySizeIR == nil {
 		t.Fatalf("expected body size IR to be set for rule index 0")
 	}
@@ -178,7 +182,75 @@ func TestApplyBodySizeToEmitterIR_SetMaxAndBufferSize(t *testing.T) {
 	}
 }
 
-func setupBodySizeTest(httpRouteKey types.NamespacedName, ingAnnotations map[string]string) (providerir.ProviderIR, emitterir.EmitterIR) {
+func TestApplyBufferingToEmitterIR_Buffering(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        *emitterir.Buffering
+	}{
+		{
+			name: "request buffering on defaults to the body size of ingress-nginx",
+			annotations: map[string]string{
+				ProxyRequestBufferingAnnotation: "on",
+			},
+			want: &emitterir.Buffering{
+				MaxSize:          ptr.To(resource.MustParse("1Mi")),
+				RequestBuffering: ptr.To(true),
+			},
+		},
+		{
+			name: "request buffering off",
+			annotations: map[string]string{
+				ProxyRequestBufferingAnnotation: "off",
+				ProxyBodySizeAnnotation:         "8m",
+			},
+			want: &emitterir.Buffering{
+				MaxSize:          ptr.To(resource.MustParse("8Mi")),
+				RequestBuffering: ptr.To(false),
+			},
+		},
+		{
+			name: "response buffering, buffer size and HTTP version",
+			annotations: map[string]string{
+				ProxyBufferingAnnotation:       "on",
+				ProxyBufferSizeAnnotation:      "16k",
+				ProxyMaxTempFileSizeAnnotation: "0",
+				ProxyHTTPVersionAnnotation:     "1.0",
+			},
+			want: &emitterir.Buffering{
+				ResponseBuffering:  ptr.To(true),
+				ResponseBufferSize: ptr.To(resource.MustParse("16Ki")),
+				HTTPVersion:        "1.0",
+			},
+		},
+		{
+			name: "invalid values are ignored",
+			annotations: map[string]string{
+				ProxyBufferingAnnotation:       "maybe",
+				ProxyBufferSizeAnnotation:      "lots",
+				ProxyMaxTempFileSizeAnnotation: "1g",
+				ProxyHTTPVersionAnnotation:     "2.0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := types.NamespacedName{Namespace: "default", Name: "route"}
+			pIR, eIR := setupBufferingTest(key, tc.annotations)
+
+			p := &Provider{notify: notifications.NoopNotify}
+			p.applyBufferingToEmitterIR(pIR, &eIR)
+
+			got := eIR.HTTPRoutes[key].BufferingByRuleIdx[0]
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(emitterir.Buffering{}, "Metadata")); diff != "" {
+				t.Fatalf("unexpected buffering IR (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func setupBufferingTest(httpRouteKey types.NamespacedName, ingAnnotations map[string]string) (providerir.ProviderIR, emitterir.EmitterIR) {
 	parentRefs := []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName("gw")}}
 
 	ing := networkingv1.Ingress{
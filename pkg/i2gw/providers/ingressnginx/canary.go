@@ -18,6 +18,7 @@ package ingressnginx
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
@@ -31,12 +32,15 @@ import (
 
 // canaryConfig holds the parsed canary configuration from a single Ingress
 type canaryConfig struct {
-	isHeader    bool
-	header      string
-	headerValue string
-	isWeight    bool
-	weight      int32
-	weightTotal int32
+	isHeader      bool
+	header        string
+	headerValue   string
+	headerPattern string
+	isCookie      bool
+	cookie        string
+	isWeight      bool
+	weight        int32
+	weightTotal   int32
 }
 
 // parseCanaryConfig extracts canary weight configuration from an Ingress.
@@ -48,22 +52,31 @@ func parseCanaryConfig(notify notifications.NotifyFunc, ingress *networkingv1.In
 		weightTotal: 100, // default
 	}
 
-	if ingress.Annotations[CanaryByHeaderPattern] != "" {
-		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s uses unsupported annotation %s",
-			ingress.Namespace, ingress.Name, CanaryByHeaderPattern), ingress)
-	}
-
-	if ingress.Annotations[CanaryByCookie] != "" {
-		notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s uses unsupported annotation %s",
-			ingress.Namespace, ingress.Name, CanaryByCookie), ingress)
-	}
-
 	if ingress.Annotations[CanaryByHeader] != "" {
 		config.isHeader = true
 	}
 	config.header = ingress.Annotations[CanaryByHeader]
 	config.headerValue = ingress.Annotations[CanaryByHeaderValue]
 
+	// Like in ingress-nginx, the header pattern is ignored when the header value is set.
+	if pattern := ingress.Annotations[CanaryByHeaderPattern]; pattern != "" {
+		switch {
+		case !config.isHeader:
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s sets %s without %s, the pattern is ignored",
+				ingress.Namespace, ingress.Name, CanaryByHeaderPattern, CanaryByHeader), ingress)
+		case config.headerValue != "":
+			notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s sets both %s and %s, the pattern is ignored",
+				ingress.Namespace, ingress.Name, CanaryByHeaderValue, CanaryByHeaderPattern), ingress)
+		default:
+			config.headerPattern = pattern
+		}
+	}
+
+	if ingress.Annotations[CanaryByCookie] != "" {
+		config.isCookie = true
+	}
+	config.cookie = ingress.Annotations[CanaryByCookie]
+
 	weight := ingress.Annotations[CanaryWeightAnnotation]
 
 	if weight != "" {
@@ -104,10 +117,11 @@ func parseCanaryConfig(notify notifications.NotifyFunc, ingress *networkingv1.In
 	return config
 }
 
-func createHeaderMatchRule(header string, value string, existingMatches []gatewayv1.HTTPRouteMatch, backend gatewayv1.HTTPBackendRef) gatewayv1.HTTPRouteRule {
+func createHeaderMatchRule(header string, matchType gatewayv1.HeaderMatchType, value string, existingMatches []gatewayv1.HTTPRouteMatch, backend gatewayv1.HTTPBackendRef) gatewayv1.HTTPRouteRule {
 	headerMatch := gatewayv1.HTTPRouteMatch{
 		Headers: []gatewayv1.HTTPHeaderMatch{
 			{
+				Type:  &matchType,
 				Name:  gatewayv1.HTTPHeaderName(header),
 				Value: value,
 			},
@@ -122,6 +136,29 @@ func createHeaderMatchRule(header string, value string, existingMatches []gatewa
 	}
 }
 
+// headerValuePattern returns a regular expression matching the header values that
+// contain a match of the pattern, like nginx does. The expression matches the whole
+// value, so it works whether the implementation anchors regular expressions or not.
+func headerValuePattern(pattern string) string {
+	return fmt.Sprintf(`.*(?:%s).*`, pattern)
+}
+
+// cookieValuePattern returns a regular expression matching a Cookie header that
+// contains the cookie with the given value. The expression matches the whole header,
+// so it works whether the implementation anchors regular expressions or not.
+func cookieValuePattern(cookie, value string) string {
+	return fmt.Sprintf(`^(.*;\s*)?%s=%s(;.*)?$`, regexp.QuoteMeta(cookie), regexp.QuoteMeta(value))
+}
+
+// createCookieMatchRules returns the rules routing requests with the canary cookie set
+// to "always" to the canary backend, and to "never" to the non-canary backend.
+func createCookieMatchRules(cookie string, existingMatches []gatewayv1.HTTPRouteMatch, canaryBackend, nonCanaryBackend gatewayv1.HTTPBackendRef) []gatewayv1.HTTPRouteRule {
+	return []gatewayv1.HTTPRouteRule{
+		createHeaderMatchRule("Cookie", gatewayv1.HeaderMatchRegularExpression, cookieValuePattern(cookie, "always"), existingMatches, canaryBackend),
+		createHeaderMatchRule("Cookie", gatewayv1.HeaderMatchRegularExpression, cookieValuePattern(cookie, "never"), existingMatches, nonCanaryBackend),
+	}
+}
+
 func canaryFeature(notify notifications.NotifyFunc, ingresses []networkingv1.Ingress, _ map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
 	ruleGroups := common.GetRuleGroups(ingresses)
 	var errList field.ErrorList
@@ -139,35 +176,46 @@ func canaryFeature(notify notifications.NotifyFunc, ingresses []networkingv1.Ing
 				canaryWeight, nonCanaryWeight, config, canaryBackendIdx, nonCanaryBackendIdx, parseErrs := getCanaryInfo(notify, backendSources, "httproute", httpRouteContext.HTTPRoute.Name, ruleIdx)
 				errList = append(errList, parseErrs...)
 				if canaryBackendIdx != -1 && nonCanaryBackendIdx != -1 {
-					// Set weights if isWeight is true or both header and weight are not set (all traffic should go to non-canary)
-					if config.isWeight || !config.isHeader {
+					// Set weights if isWeight is true or neither header, cookie nor weight are set (all traffic should go to non-canary)
+					if config.isWeight || (!config.isHeader && !config.isCookie) {
 						httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[canaryBackendIdx].Weight = &canaryWeight
 						httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[nonCanaryBackendIdx].Weight = &nonCanaryWeight
 					}
 
-					if config.isHeader {
-						canaryBackend := httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[canaryBackendIdx]
-						nonCanaryBackend := httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[nonCanaryBackendIdx]
+					if config.isHeader || config.isCookie {
+						canaryBackendCopy := httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[canaryBackendIdx]
+						canaryBackendCopy.Weight = nil
+						nonCanaryBackendCopy := httpRouteContext.HTTPRoute.Spec.Rules[ruleIdx].BackendRefs[nonCanaryBackendIdx]
+						nonCanaryBackendCopy.Weight = nil
 						canaryBackendSource := backendSources[canaryBackendIdx]
 						nonCanaryBackendSource := backendSources[nonCanaryBackendIdx]
 
-						var canaryHeaderValue = "always"
-						if config.headerValue != "" {
-							canaryHeaderValue = config.headerValue
+						// ingress-nginx checks the header first, then the cookie, then the weight.
+						// The header and cookie rules have the same number of header matches, so
+						// the header rules are added first to take precedence.
+						if config.isHeader {
+							switch {
+							case config.headerValue != "":
+								rulesToAdd = append(rulesToAdd, createHeaderMatchRule(config.header, gatewayv1.HeaderMatchExact, config.headerValue, existingMatches, canaryBackendCopy))
+								sourcesToAdd = append(sourcesToAdd, []providerir.BackendSource{canaryBackendSource, nonCanaryBackendSource})
+							case config.headerPattern != "":
+								rulesToAdd = append(rulesToAdd, createHeaderMatchRule(config.header, gatewayv1.HeaderMatchRegularExpression, headerValuePattern(config.headerPattern), existingMatches, canaryBackendCopy))
+								sourcesToAdd = append(sourcesToAdd, []providerir.BackendSource{canaryBackendSource, nonCanaryBackendSource})
+							default:
+								rulesToAdd = append(rulesToAdd,
+									createHeaderMatchRule(config.header, gatewayv1.HeaderMatchExact, "always", existingMatches, canaryBackendCopy),
+									createHeaderMatchRule(config.header, gatewayv1.HeaderMatchExact, "never", existingMatches, nonCanaryBackendCopy))
+								sourcesToAdd = append(sourcesToAdd,
+									[]providerir.BackendSource{canaryBackendSource, nonCanaryBackendSource},
+									[]providerir.BackendSource{nonCanaryBackendSource})
+							}
 						}
-						canaryBackendCopy := canaryBackend
-						canaryBackendCopy.Weight = nil
-
-						var canaryMatchRule = createHeaderMatchRule(config.header, canaryHeaderValue, existingMatches, canaryBackendCopy)
-						rulesToAdd = append(rulesToAdd, canaryMatchRule)
-						sourcesToAdd = append(sourcesToAdd, []providerir.BackendSource{canaryBackendSource, nonCanaryBackendSource})
 
-						if config.headerValue == "" {
-							nonCanaryBackendCopy := nonCanaryBackend
-							nonCanaryBackendCopy.Weight = nil
-							var nonCanaryMatchRule = createHeaderMatchRule(config.header, "never", existingMatches, nonCanaryBackendCopy)
-							rulesToAdd = append(rulesToAdd, nonCanaryMatchRule)
-							sourcesToAdd = append(sourcesToAdd, []providerir.BackendSource{nonCanaryBackendSource})
+						if config.isCookie {
+							rulesToAdd = append(rulesToAdd, createCookieMatchRules(config.cookie, existingMatches, canaryBackendCopy, nonCanaryBackendCopy)...)
+							sourcesToAdd = append(sourcesToAdd,
+								[]providerir.BackendSource{canaryBackendSource, nonCanaryBackendSource},
+								[]providerir.BackendSource{nonCanaryBackendSource})
 						}
 
 						if !config.isWeight {
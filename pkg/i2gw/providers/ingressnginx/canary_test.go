@@ -17,6 +17,7 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,6 +26,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -394,6 +396,62 @@ func Test_parseCanaryConfig(t *testing.T) {
 				weightTotal: 100,
 			},
 		},
+		{
+			name: "parses canary-by-header-pattern",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/canary":                   "true",
+						"nginx.ingress.kubernetes.io/canary-by-header":         "X-Canary",
+						"nginx.ingress.kubernetes.io/canary-by-header-pattern": "^(beta|alpha)$",
+					},
+				},
+			},
+			expectedConfig: canaryConfig{
+				isHeader:      true,
+				header:        "X-Canary",
+				headerPattern: "^(beta|alpha)$",
+				weightTotal:   100,
+			},
+		},
+		{
+			name: "header value takes precedence over header pattern",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/canary":                   "true",
+						"nginx.ingress.kubernetes.io/canary-by-header":         "X-Canary",
+						"nginx.ingress.kubernetes.io/canary-by-header-value":   "beta",
+						"nginx.ingress.kubernetes.io/canary-by-header-pattern": "^(beta|alpha)$",
+					},
+				},
+			},
+			expectedConfig: canaryConfig{
+				isHeader:    true,
+				header:      "X-Canary",
+				headerValue: "beta",
+				weightTotal: 100,
+			},
+		},
+		{
+			name: "parses canary-by-cookie",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/canary":           "true",
+						"nginx.ingress.kubernetes.io/canary-by-cookie": "canary",
+						"nginx.ingress.kubernetes.io/canary-weight":    "20",
+					},
+				},
+			},
+			expectedConfig: canaryConfig{
+				isCookie:    true,
+				cookie:      "canary",
+				isWeight:    true,
+				weight:      20,
+				weightTotal: 100,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -654,3 +712,165 @@ func Test_canaryFeature_GRPC_ByWeight(t *testing.T) {
 		t.Errorf("expected weight 25 for canary backend, got %d", *backendRefs[1].Weight)
 	}
 }
+
+func Test_cookieValuePattern(t *testing.T) {
+	testCases := []struct {
+		cookieHeader string
+		value        string
+		expected     bool
+	}{
+		{cookieHeader: "canary=always", value: "always", expected: true},
+		{cookieHeader: "session=abc; canary=always", value: "always", expected: true},
+		{cookieHeader: "canary=always;session=abc", value: "always", expected: true},
+		{cookieHeader: "canary=never", value: "always", expected: false},
+		{cookieHeader: "canary=alwaysnot", value: "always", expected: false},
+		{cookieHeader: "my-canary=always", value: "always", expected: false},
+		{cookieHeader: "session=abc; canary=never", value: "never", expected: true},
+	}
+
+	pattern := regexp.MustCompile(cookieValuePattern("canary", "always"))
+	neverPattern := regexp.MustCompile(cookieValuePattern("canary", "never"))
+	for _, tc := range testCases {
+		re := pattern
+		if tc.value == "never" {
+			re = neverPattern
+		}
+		if matched := re.MatchString(tc.cookieHeader); matched != tc.expected {
+			t.Errorf("matching %q against the %s cookie pattern: expected %t, got %t", tc.cookieHeader, tc.value, tc.expected, matched)
+		}
+	}
+}
+
+func Test_headerValuePattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{pattern: "beta|alpha", value: "beta", expected: true},
+		{pattern: "beta|alpha", value: "open-beta-1", expected: true},
+		{pattern: "beta|alpha", value: "stable", expected: false},
+		{pattern: "^(beta|alpha)$", value: "beta", expected: true},
+		{pattern: "^(beta|alpha)$", value: "open-beta", expected: false},
+	}
+
+	for _, tc := range testCases {
+		re := regexp.MustCompile("^" + headerValuePattern(tc.pattern) + "$")
+		if matched := re.MatchString(tc.value); matched != tc.expected {
+			t.Errorf("matching %q against the %q pattern: expected %t, got %t", tc.value, tc.pattern, tc.expected, matched)
+		}
+	}
+}
+
+func Test_canaryFeature_HeaderPatternAndCookie(t *testing.T) {
+	pathType := gatewayv1.PathMatchPathPrefix
+	existingMatches := []gatewayv1.HTTPRouteMatch{{
+		Path: &gatewayv1.HTTPPathMatch{Type: &pathType, Value: ptr.To("/")},
+	}}
+	backend := func(name string) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(name)},
+		}}
+	}
+	exact := gatewayv1.HeaderMatchExact
+	regex := gatewayv1.HeaderMatchRegularExpression
+
+	testCases := []struct {
+		name              string
+		canaryAnnotations map[string]string
+		expectedRules     []gatewayv1.HTTPRouteRule
+	}{
+		{
+			name: "header pattern",
+			canaryAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/canary-by-header":         "X-Canary",
+				"nginx.ingress.kubernetes.io/canary-by-header-pattern": "beta|alpha",
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: existingMatches, BackendRefs: []gatewayv1.HTTPBackendRef{backend("stable")}},
+				createHeaderMatchRule("X-Canary", regex, ".*(?:beta|alpha).*", existingMatches, backend("canary")),
+			},
+		},
+		{
+			name: "cookie",
+			canaryAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/canary-by-cookie": "canary",
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: existingMatches, BackendRefs: []gatewayv1.HTTPBackendRef{backend("stable")}},
+				createHeaderMatchRule("Cookie", regex, cookieValuePattern("canary", "always"), existingMatches, backend("canary")),
+				createHeaderMatchRule("Cookie", regex, cookieValuePattern("canary", "never"), existingMatches, backend("stable")),
+			},
+		},
+		{
+			name: "header takes precedence over cookie and weight",
+			canaryAnnotations: map[string]string{
+				"nginx.ingress.kubernetes.io/canary-by-header": "X-Canary",
+				"nginx.ingress.kubernetes.io/canary-by-cookie": "canary",
+				"nginx.ingress.kubernetes.io/canary-weight":    "10",
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: existingMatches, BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "stable"}, Weight: ptr.To[int32](90)}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "canary"}, Weight: ptr.To[int32](10)}},
+				}},
+				createHeaderMatchRule("X-Canary", exact, "always", existingMatches, backend("canary")),
+				createHeaderMatchRule("X-Canary", exact, "never", existingMatches, backend("stable")),
+				createHeaderMatchRule("Cookie", regex, cookieValuePattern("canary", "always"), existingMatches, backend("canary")),
+				createHeaderMatchRule("Cookie", regex, cookieValuePattern("canary", "never"), existingMatches, backend("stable")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stableIngress := &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+			}
+			canaryAnnotations := map[string]string{"nginx.ingress.kubernetes.io/canary": "true"}
+			for k, v := range tc.canaryAnnotations {
+				canaryAnnotations[k] = v
+			}
+			canaryIngress := &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "default", Annotations: canaryAnnotations},
+			}
+
+			key := types.NamespacedName{Namespace: "default", Name: "stable-example-com"}
+			ir := &providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{
+									Matches:     existingMatches,
+									BackendRefs: []gatewayv1.HTTPBackendRef{backend("stable"), backend("canary")},
+								}},
+							},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: stableIngress}, {Ingress: canaryIngress}},
+						},
+					},
+				},
+			}
+			ingresses := []networkingv1.Ingress{*stableIngress, *canaryIngress}
+			for i := range ingresses {
+				ingresses[i].Spec.Rules = []networkingv1.IngressRule{{Host: "example.com"}}
+			}
+
+			errs := canaryFeature(notifications.NoopNotify, ingresses, nil, ir)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			route := ir.HTTPRoutes[key]
+			if diff := cmp.Diff(tc.expectedRules, route.HTTPRoute.Spec.Rules); diff != "" {
+				t.Errorf("unexpected rules (-want +got):\n%s", diff)
+			}
+			if len(route.RuleBackendSources) != len(route.HTTPRoute.Spec.Rules) {
+				t.Errorf("expected %d rule backend sources, got %d", len(route.HTTPRoute.Spec.Rules), len(route.RuleBackendSources))
+			}
+		})
+	}
+}
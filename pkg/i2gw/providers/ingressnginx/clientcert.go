@@ -0,0 +1,299 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// caCertificateKey is the key holding the CA bundle, both in the
+	// auth-tls-secret Secret and in the ConfigMap it is converted to.
+	caCertificateKey = "ca.crt"
+
+	verifyClientOn           = "on"
+	verifyClientOff          = "off"
+	verifyClientOptional     = "optional"
+	verifyClientOptionalNoCA = "optional_no_ca"
+)
+
+// clientCertAuth is the client certificate authentication configuration of an Ingress.
+type clientCertAuth struct {
+	ing                       *networkingv1.Ingress
+	secret                    types.NamespacedName
+	caBundle                  []byte
+	optional                  bool
+	verifyDepth               int32
+	passCertificateToUpstream bool
+}
+
+// sameValidation returns true when both configurations validate clients the same way.
+func (c *clientCertAuth) sameValidation(other *clientCertAuth) bool {
+	return c.secret == other.secret &&
+		c.optional == other.optional &&
+		c.verifyDepth == other.verifyDepth &&
+		c.passCertificateToUpstream == other.passCertificateToUpstream
+}
+
+type listenerKey struct {
+	gateway  types.NamespacedName
+	listener gatewayv1.SectionName
+}
+
+// applyClientCertificateValidationToEmitterIR reads the ingress-nginx client certificate
+// authentication annotations from ProviderIR sources and stores provider-neutral client
+// certificate validation intent into the EmitterIR Gateways, which will later be converted
+// by the common emitter or by each custom emitter.
+//
+// ingress-nginx configures client certificate authentication per host, while Gateways
+// configure it per listener, so hosts sharing a listener share their validation.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/auth-tls-secret
+// - nginx.ingress.kubernetes.io/auth-tls-verify-client
+// - nginx.ingress.kubernetes.io/auth-tls-verify-depth
+// - nginx.ingress.kubernetes.io/auth-tls-pass-certificate-to-upstream
+func (p *Provider) applyClientCertificateValidationToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*clientCertAuth{}
+	// hostsByListener maps the hosts served by each listener to their
+	// configuration, which is nil for hosts without client certificate
+	// authentication.
+	hostsByListener := map[listenerKey]map[string]*clientCertAuth{}
+
+	routeKeys := slices.SortedFunc(maps.Keys(pIR.HTTPRoutes), func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, key := range routeKeys {
+		pRouteCtx := pIR.HTTPRoutes[key]
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok || len(eRouteCtx.Spec.Hostnames) == 0 {
+			continue
+		}
+
+		var auth *clientCertAuth
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			ingAuth, found := parsed[ingKey]
+			if !found {
+				ingAuth = p.parseClientCertAuth(ing)
+				parsed[ingKey] = ingAuth
+			}
+			// The first Ingress configuring the host wins, as in ingress-nginx.
+			if auth == nil {
+				auth = ingAuth
+			}
+		}
+
+		for _, hostname := range eRouteCtx.Spec.Hostnames {
+			for _, parentRef := range eRouteCtx.Spec.ParentRefs {
+				gwKey := types.NamespacedName{Namespace: eRouteCtx.Namespace, Name: string(parentRef.Name)}
+				if parentRef.Namespace != nil {
+					gwKey.Namespace = string(*parentRef.Namespace)
+				}
+				lKey := listenerKey{gateway: gwKey, listener: httpsListenerName(eIR, eRouteCtx.Namespace, parentRef, string(hostname))}
+				if !hasHTTPSListener(eIR.Gateways[gwKey].Spec.Listeners, lKey.listener) {
+					if auth != nil {
+						p.notify(notifications.WarningNotification, fmt.Sprintf("Host %s is not served over TLS, client certificate authentication can't be converted", hostname), auth.ing)
+					}
+					continue
+				}
+				if hostsByListener[lKey] == nil {
+					hostsByListener[lKey] = map[string]*clientCertAuth{}
+				}
+				if hostsByListener[lKey][string(hostname)] == nil {
+					hostsByListener[lKey][string(hostname)] = auth
+				}
+			}
+		}
+	}
+
+	for lKey, hosts := range hostsByListener {
+		gatewayContext, ok := eIR.Gateways[lKey.gateway]
+		if !ok {
+			continue
+		}
+		validation := p.listenerClientCertificateValidation(lKey, hosts)
+		if validation == nil {
+			continue
+		}
+		if gatewayContext.ClientCertificateValidationByListener == nil {
+			gatewayContext.ClientCertificateValidationByListener = make(map[gatewayv1.SectionName]*emitterir.ClientCertificateValidation)
+		}
+		gatewayContext.ClientCertificateValidationByListener[lKey.listener] = validation
+		eIR.Gateways[lKey.gateway] = gatewayContext
+	}
+}
+
+// listenerClientCertificateValidation merges the configurations of the hosts
+// served by a listener into the validation of the listener, along with the CA
+// bundle ConfigMap it references. It returns nil when no host asks for client
+// certificate authentication.
+func (p *Provider) listenerClientCertificateValidation(lKey listenerKey, hosts map[string]*clientCertAuth) *emitterir.ClientCertificateValidation {
+	var auth *clientCertAuth
+	var authHosts, otherHosts []string
+	for _, hostname := range slices.Sorted(maps.Keys(hosts)) {
+		hostAuth := hosts[hostname]
+		switch {
+		case hostAuth == nil:
+			otherHosts = append(otherHosts, hostname)
+		case auth == nil:
+			auth = hostAuth
+			authHosts = append(authHosts, hostname)
+		case auth.sameValidation(hostAuth):
+			authHosts = append(authHosts, hostname)
+		default:
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Hosts %s and %s share listener %s of Gateway %s but configure different client certificate authentication, the one of %s is used for both",
+				authHosts[0], hostname, lKey.listener, lKey.gateway, authHosts[0]), hostAuth.ing)
+			authHosts = append(authHosts, hostname)
+		}
+	}
+	if auth == nil {
+		return nil
+	}
+	if len(otherHosts) > 0 {
+		p.notify(notifications.WarningNotification, fmt.Sprintf("Client certificate authentication of %s is applied to listener %s of Gateway %s, which also serves %s",
+			strings.Join(authHosts, ", "), lKey.listener, lKey.gateway, strings.Join(otherHosts, ", ")), auth.ing)
+	}
+
+	configMapKey := types.NamespacedName{Namespace: lKey.gateway.Namespace, Name: fmt.Sprintf("%s-ca", auth.secret.Name)}
+	// The ConfigMap is emitted by the emitters converting the validation.
+	var configMap *apiv1.ConfigMap
+	if auth.caBundle != nil {
+		configMap = &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: configMapKey.Namespace,
+				Name:      configMapKey.Name,
+			},
+			Data: map[string]string{caCertificateKey: string(auth.caBundle)},
+		}
+		configMap.SetGroupVersionKind(apiv1.SchemeGroupVersion.WithKind("ConfigMap"))
+	}
+
+	ing := auth.ing
+	var paths []*field.Path
+	for _, annotation := range []string{AuthTLSSecretAnnotation, AuthTLSVerifyClientAnnotation, AuthTLSVerifyDepthAnnotation, AuthTLSPassCertificateToUpstreamAnnotation} {
+		if _, ok := ing.Annotations[annotation]; ok {
+			paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+		}
+	}
+	return &emitterir.ClientCertificateValidation{
+		Metadata: emitterir.NewExtensionFeatureMetadata(
+			fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+			paths,
+			"Client certificate authentication is not supported, clients are not authenticated",
+		),
+		CACertificateRef:          configMapKey,
+		CACertificate:             configMap,
+		Optional:                  auth.optional,
+		VerifyDepth:               auth.verifyDepth,
+		PassCertificateToUpstream: auth.passCertificateToUpstream,
+		Hostnames:                 authHosts,
+	}
+}
+
+// parseClientCertAuth returns the client certificate authentication configuration
+// of the Ingress, or nil if it has none or its configuration is invalid.
+func (p *Provider) parseClientCertAuth(ing *networkingv1.Ingress) *clientCertAuth {
+	secretRef, hasSecret := annotationObjectRef(ing, AuthTLSSecretAnnotation)
+	if !hasSecret {
+		return nil
+	}
+
+	auth := clientCertAuth{
+		ing:    ing,
+		secret: secretRef,
+	}
+
+	verifyClient := strings.ToLower(strings.TrimSpace(ing.Annotations[AuthTLSVerifyClientAnnotation]))
+	switch verifyClient {
+	case "", verifyClientOn:
+	case verifyClientOff:
+		return nil
+	case verifyClientOptional:
+		auth.optional = true
+	case verifyClientOptionalNoCA:
+		auth.optional = true
+		p.notify(notifications.WarningNotification, "auth-tls-verify-client \"optional_no_ca\" is converted to \"optional\", client certificates are validated against the CA bundle", ing)
+	default:
+		p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid auth-tls-verify-client annotation %q, skipping client certificate authentication", verifyClient), ing)
+		return nil
+	}
+
+	if rawDepth := strings.TrimSpace(ing.Annotations[AuthTLSVerifyDepthAnnotation]); rawDepth != "" {
+		depth, err := strconv.ParseInt(rawDepth, 10, 32)
+		if err != nil || depth < 1 {
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid auth-tls-verify-depth annotation %q, the default depth is used", rawDepth), ing)
+		} else {
+			auth.verifyDepth = int32(depth)
+		}
+	}
+
+	if rawPass := strings.TrimSpace(ing.Annotations[AuthTLSPassCertificateToUpstreamAnnotation]); rawPass != "" {
+		pass, err := strconv.ParseBool(rawPass)
+		if err != nil {
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid auth-tls-pass-certificate-to-upstream annotation %q, the client certificate is not passed to the backends", rawPass), ing)
+		}
+		auth.passCertificateToUpstream = pass
+	}
+
+	if secret, found := p.storage.Secrets[secretRef]; found {
+		caBundle := secret.Data[caCertificateKey]
+		if caBundle == nil && secret.StringData[caCertificateKey] != "" {
+			caBundle = []byte(secret.StringData[caCertificateKey])
+		}
+		if len(caBundle) == 0 {
+			p.notify(notifications.ErrorNotification, fmt.Sprintf("auth-tls-secret %s has no %q key, skipping client certificate authentication", secretRef, caCertificateKey), ing)
+			return nil
+		}
+		auth.caBundle = caBundle
+	} else {
+		p.notify(notifications.WarningNotification, fmt.Sprintf("auth-tls-secret %s was not found, a ConfigMap %s-ca with its %q key must be created next to the Gateway",
+			secretRef, secretRef.Name, caCertificateKey), ing)
+	}
+
+	return &auth
+}
+
+// hasHTTPSListener returns true if the listener exists and terminates TLS.
+func hasHTTPSListener(listeners []gatewayv1.Listener, name gatewayv1.SectionName) bool {
+	return slices.ContainsFunc(listeners, func(l gatewayv1.Listener) bool {
+		return l.Name == name && l.Protocol == gatewayv1.HTTPSProtocolType
+	})
+}
@@ -0,0 +1,227 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestApplyClientCertificateValidationToEmitterIR(t *testing.T) {
+	caBundle := "-----BEGIN CERTIFICATE-----\n"
+	secrets := map[types.NamespacedName]*apiv1.Secret{
+		{Namespace: "default", Name: "ca"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca"},
+			Data:       map[string][]byte{"ca.crt": []byte(caBundle)},
+		},
+		{Namespace: "default", Name: "no-ca"}: {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "no-ca"},
+			Data:       map[string][]byte{"tls.crt": []byte(caBundle)},
+		},
+	}
+	gwKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		// listeners are the HTTPS listeners of the Gateway, by hostname.
+		listeners             map[string]gatewayv1.SectionName
+		expectedListener      gatewayv1.SectionName
+		expectedValidation    *emitterir.ClientCertificateValidation
+		expectedConfigMap     bool
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "required verification",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation: "default/ca",
+			},
+			listeners:        map[string]gatewayv1.SectionName{"secure.example.com": "secure", "open.example.com": "open"},
+			expectedListener: "secure",
+			expectedValidation: &emitterir.ClientCertificateValidation{
+				CACertificateRef: types.NamespacedName{Namespace: "default", Name: "ca-ca"},
+				Hostnames:        []string{"secure.example.com"},
+			},
+			expectedConfigMap:     true,
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "optional verification with missing secret",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation:                    "missing",
+				AuthTLSVerifyClientAnnotation:              "optional",
+				AuthTLSVerifyDepthAnnotation:               "2",
+				AuthTLSPassCertificateToUpstreamAnnotation: "true",
+			},
+			listeners:        map[string]gatewayv1.SectionName{"secure.example.com": "secure", "open.example.com": "open"},
+			expectedListener: "secure",
+			expectedValidation: &emitterir.ClientCertificateValidation{
+				CACertificateRef:          types.NamespacedName{Namespace: "default", Name: "missing-ca"},
+				Optional:                  true,
+				VerifyDepth:               2,
+				PassCertificateToUpstream: true,
+				Hostnames:                 []string{"secure.example.com"},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "listener shared with a host without verification",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation:       "ca",
+				AuthTLSVerifyClientAnnotation: "optional_no_ca",
+			},
+			listeners:        map[string]gatewayv1.SectionName{"*.example.com": "wildcard"},
+			expectedListener: "wildcard",
+			expectedValidation: &emitterir.ClientCertificateValidation{
+				CACertificateRef: types.NamespacedName{Namespace: "default", Name: "ca-ca"},
+				Optional:         true,
+				Hostnames:        []string{"secure.example.com"},
+			},
+			expectedConfigMap:     true,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 2},
+		},
+		{
+			name: "verification turned off",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation:       "ca",
+				AuthTLSVerifyClientAnnotation: "off",
+			},
+			listeners:             map[string]gatewayv1.SectionName{"secure.example.com": "secure"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "invalid verify-client",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation:       "ca",
+				AuthTLSVerifyClientAnnotation: "sometimes",
+			},
+			listeners:             map[string]gatewayv1.SectionName{"secure.example.com": "secure"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "secret without CA bundle",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation: "no-ca",
+			},
+			listeners:             map[string]gatewayv1.SectionName{"secure.example.com": "secure"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "host without TLS",
+			annotations: map[string]string{
+				AuthTLSSecretAnnotation: "ca",
+			},
+			listeners:             map[string]gatewayv1.SectionName{"open.example.com": "open"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: gwKey.Namespace, Name: gwKey.Name}}
+			for hostname, name := range tc.listeners {
+				gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
+					Name:     name,
+					Hostname: ptr.To(gatewayv1.Hostname(hostname)),
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+				})
+			}
+
+			pIR := providerir.ProviderIR{HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{}}
+			eIR := emitterir.EmitterIR{
+				Gateways:   map[types.NamespacedName]emitterir.GatewayContext{gwKey: {Gateway: gateway}},
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{},
+			}
+			for name, annotations := range map[string]map[string]string{"secure": tc.annotations, "open": nil} {
+				ing := networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   "default",
+						Annotations: annotations,
+					},
+				}
+				hostname := name + ".example.com"
+				key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, hostname)}
+				route := gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{
+							ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gwKey.Name)}},
+						},
+						Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(hostname)},
+						Rules:     []gatewayv1.HTTPRouteRule{{}},
+					},
+				}
+				pIR.HTTPRoutes[key] = providerir.HTTPRouteContext{
+					HTTPRoute:          route,
+					RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &ing}}},
+				}
+				eIR.HTTPRoutes[key] = emitterir.HTTPRouteContext{HTTPRoute: route}
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify, storage: &storage{Secrets: secrets}}
+
+			p.applyClientCertificateValidationToEmitterIR(pIR, &eIR)
+
+			result := eIR.Gateways[gwKey].ClientCertificateValidationByListener
+			if tc.expectedValidation == nil {
+				if result != nil {
+					t.Fatalf("Expected no ClientCertificateValidation, got %v", result)
+				}
+			} else {
+				if len(result) != 1 {
+					t.Fatalf("Expected ClientCertificateValidation for 1 listener, got %d", len(result))
+				}
+				if diff := cmp.Diff(tc.expectedValidation, result[tc.expectedListener], cmpopts.IgnoreFields(emitterir.ClientCertificateValidation{}, "Metadata", "CACertificate")); diff != "" {
+					t.Errorf("Unexpected ClientCertificateValidation (-want +got):\n%s", diff)
+				}
+			}
+
+			var configMap *apiv1.ConfigMap
+			if validation := result[tc.expectedListener]; validation != nil {
+				configMap = validation.CACertificate
+			}
+			if hasConfigMap := configMap != nil; hasConfigMap != tc.expectedConfigMap {
+				t.Errorf("Expected CA ConfigMap: %v, got %v", tc.expectedConfigMap, hasConfigMap)
+			}
+			if configMap != nil && configMap.Data["ca.crt"] != caBundle {
+				t.Errorf("Unexpected CA bundle %q", configMap.Data["ca.crt"])
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Controller ConfigMap keys configuring HTTP Strict Transport Security.
+const (
+	hstsKey                  = "hsts"
+	hstsMaxAgeKey            = "hsts-max-age"
+	hstsIncludeSubdomainsKey = "hsts-include-subdomains"
+	hstsPreloadKey           = "hsts-preload"
+)
+
+// Controller ConfigMap keys configuring the TLS connections of the servers.
+const (
+	sslProtocolsKey = "ssl-protocols"
+	useHTTP2Key     = "use-http2"
+)
+
+// controllerConfigAnnotations maps the controller ConfigMap keys to the annotations
+// they are the global default of. Annotations of the Ingresses override them.
+var controllerConfigAnnotations = map[string]string{
+	"proxy-connect-timeout":       ProxyConnectTimeoutAnnotation,
+	"proxy-send-timeout":          ProxySendTimeoutAnnotation,
+	"proxy-read-timeout":          ProxyReadTimeoutAnnotation,
+	"proxy-next-upstream":         ProxyNextUpstreamAnnotation,
+	"proxy-next-upstream-tries":   ProxyNextUpstreamTriesAnnotation,
+	"proxy-next-upstream-timeout": ProxyNextUpstreamTimeoutAnnotation,
+	"proxy-body-size":             ProxyBodySizeAnnotation,
+	"client-body-buffer-size":     ClientBodyBufferSizeAnnotation,
+	"proxy-request-buffering":     ProxyRequestBufferingAnnotation,
+	"proxy-buffering":             ProxyBufferingAnnotation,
+	"proxy-buffer-size":           ProxyBufferSizeAnnotation,
+	"proxy-max-temp-file-size":    ProxyMaxTempFileSizeAnnotation,
+	"proxy-http-version":          ProxyHTTPVersionAnnotation,
+	"ssl-redirect":                SSLRedirectAnnotation,
+	"ssl-ciphers":                 SSLCiphersAnnotation,
+	"whitelist-source-range":      WhiteListSourceRangeAnnotation,
+	"denylist-source-range":       DenyListSourceRangeAnnotation,
+	"load-balance":                LoadBalanceAnnotation,
+}
+
+// hstsKeys are the controller ConfigMap keys converted to the HSTS policy of the Gateways.
+var hstsKeys = []string{hstsKey, hstsIncludeSubdomainsKey, hstsMaxAgeKey, hstsPreloadKey}
+
+// parsedControllerConfigKeys are the controller ConfigMap keys the features read
+// directly, rather than as defaults of annotations.
+var parsedControllerConfigKeys = map[string]struct{}{
+	hstsKey:                         {},
+	hstsMaxAgeKey:                   {},
+	hstsIncludeSubdomainsKey:        {},
+	hstsPreloadKey:                  {},
+	globalAllowedResponseHeadersKey: {},
+	sslProtocolsKey:                 {},
+	useHTTP2Key:                     {},
+}
+
+// warnUnsupportedControllerConfigKeys warns about the keys of the controller ConfigMap
+// which aren't converted.
+func warnUnsupportedControllerConfigKeys(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap) {
+	if configMap == nil {
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(configMap.Data)) {
+		_, isParsed := parsedControllerConfigKeys[key]
+		if _, isDefault := controllerConfigAnnotations[key]; !isDefault && !isParsed {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Unsupported key %q of controller ConfigMap %s/%s is not converted", key, configMap.Namespace, configMap.Name), configMap)
+		}
+	}
+}
+
+// controllerDefaults are the global defaults of the annotations set by the controller
+// ConfigMap. The features fall back to them when the Ingresses don't set the
+// annotations, and attribute them to the ConfigMap.
+type controllerDefaults struct {
+	configMap *apiv1.ConfigMap
+}
+
+// defaultKey returns the controller ConfigMap key the annotation of the Ingress
+// defaults to, if the Ingress doesn't set the annotation and the ConfigMap sets the key.
+func (d controllerDefaults) defaultKey(ing *networkingv1.Ingress, annotation string) (string, bool) {
+	if d.configMap == nil {
+		return "", false
+	}
+	if _, ok := ing.Annotations[annotation]; ok {
+		return "", false
+	}
+	for key, keyAnnotation := range controllerConfigAnnotations {
+		if keyAnnotation != annotation {
+			continue
+		}
+		_, ok := d.configMap.Data[key]
+		return key, ok
+	}
+	return "", false
+}
+
+// annotation returns the value of the annotation of the Ingress or, when the
+// Ingress doesn't set it, its global default, and whether one of them is set.
+func (d controllerDefaults) annotation(ing *networkingv1.Ingress, annotation string) (string, bool) {
+	if key, ok := d.defaultKey(ing, annotation); ok {
+		return d.configMap.Data[key], true
+	}
+	value, ok := ing.Annotations[annotation]
+	return value, ok
+}
+
+// path returns the path of the field setting the annotation of the Ingress: the
+// annotation itself, or the key of the controller ConfigMap it defaults to.
+func (d controllerDefaults) path(ing *networkingv1.Ingress, annotation string) *field.Path {
+	if key, ok := d.defaultKey(ing, annotation); ok {
+		return field.NewPath(d.configMap.Namespace, d.configMap.Name, "data", key)
+	}
+	return field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation))
+}
+
+// source describes the field setting the annotation of the Ingress and returns the
+// object it belongs to, for the notifications about its value.
+func (d controllerDefaults) source(ing *networkingv1.Ingress, annotation string) (string, client.Object) {
+	if key, ok := d.defaultKey(ing, annotation); ok {
+		return fmt.Sprintf("ConfigMap %s/%s data.%s", d.configMap.Namespace, d.configMap.Name, key), d.configMap
+	}
+	return fmt.Sprintf("annotation %s of ingress %s/%s", annotation, ing.Namespace, ing.Name), ing
+}
+
+// controllerBool returns the boolean value of the key of the controller ConfigMap,
+// or the default value when it isn't set or is invalid.
+func controllerBool(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap, key string, defaultValue bool) bool {
+	value, ok := configMap.Data[key]
+	if !ok {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		notify(notifications.WarningNotification,
+			fmt.Sprintf("Invalid %s %q in controller ConfigMap %s/%s, defaulting to %t", key, value, configMap.Namespace, configMap.Name, defaultValue), configMap)
+		return defaultValue
+	}
+	return parsed
+}
+
+// controllerHSTS returns the HSTS configuration of the controller ConfigMap, with the
+// defaults of ingress-nginx for the keys which aren't set, or nil when it is disabled.
+func controllerHSTS(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap) *emitterir.HSTS {
+	if !controllerBool(notify, configMap, hstsKey, true) {
+		return nil
+	}
+
+	hsts := &emitterir.HSTS{
+		MaxAge:            31536000,
+		IncludeSubDomains: controllerBool(notify, configMap, hstsIncludeSubdomainsKey, true),
+		Preload:           controllerBool(notify, configMap, hstsPreloadKey, false),
+	}
+	if value, ok := configMap.Data[hstsMaxAgeKey]; ok {
+		maxAge, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || maxAge < 0 {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Invalid %s %q in controller ConfigMap %s/%s, defaulting to %d", hstsMaxAgeKey, value, configMap.Namespace, configMap.Name, hsts.MaxAge), configMap)
+		} else {
+			hsts.MaxAge = maxAge
+		}
+	}
+
+	return hsts
+}
@@ -0,0 +1,194 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestControllerDefaults(t *testing.T) {
+	defaultsIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "defaults"},
+	}
+	overridesIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "overrides",
+			Annotations: map[string]string{
+				ProxyBodySizeAnnotation: "8m",
+				SSLRedirectAnnotation:   "true",
+			},
+		},
+	}
+	configMap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"},
+		Data: map[string]string{
+			"proxy-body-size":       "1m",
+			"proxy-read-timeout":    "120",
+			"ssl-redirect":          "false",
+			"hsts":                  "true",
+			"server-tokens":         "true",
+			"use-forwarded-headers": "true",
+		},
+	}
+	defaults := controllerDefaults{configMap: configMap}
+
+	testCases := []struct {
+		name           string
+		ingress        *networkingv1.Ingress
+		annotation     string
+		expectedValue  string
+		expectedSet    bool
+		expectedPath   string
+		expectedSource string
+	}{
+		{
+			name:           "default",
+			ingress:        defaultsIngress,
+			annotation:     ProxyBodySizeAnnotation,
+			expectedValue:  "1m",
+			expectedSet:    true,
+			expectedPath:   "ingress-nginx.ingress-nginx-controller.data.proxy-body-size",
+			expectedSource: "ConfigMap ingress-nginx/ingress-nginx-controller data.proxy-body-size",
+		},
+		{
+			name:           "annotation overrides the default",
+			ingress:        overridesIngress,
+			annotation:     ProxyBodySizeAnnotation,
+			expectedValue:  "8m",
+			expectedSet:    true,
+			expectedPath:   `default.overrides.metadata.annotations."nginx.ingress.kubernetes.io/proxy-body-size"`,
+			expectedSource: "annotation nginx.ingress.kubernetes.io/proxy-body-size of ingress default/overrides",
+		},
+		{
+			name:           "no default",
+			ingress:        defaultsIngress,
+			annotation:     ProxySendTimeoutAnnotation,
+			expectedPath:   `default.defaults.metadata.annotations."nginx.ingress.kubernetes.io/proxy-send-timeout"`,
+			expectedSource: "annotation nginx.ingress.kubernetes.io/proxy-send-timeout of ingress default/defaults",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := defaults.annotation(tc.ingress, tc.annotation)
+			if value != tc.expectedValue || ok != tc.expectedSet {
+				t.Errorf("Expected value %q (set: %t), got %q (set: %t)", tc.expectedValue, tc.expectedSet, value, ok)
+			}
+			if path := defaults.path(tc.ingress, tc.annotation).String(); path != tc.expectedPath {
+				t.Errorf("Expected path %s, got %s", tc.expectedPath, path)
+			}
+			if source, _ := defaults.source(tc.ingress, tc.annotation); source != tc.expectedSource {
+				t.Errorf("Expected source %q, got %q", tc.expectedSource, source)
+			}
+		})
+	}
+
+	// The defaults are never set on the Ingresses.
+	if defaultsIngress.Annotations != nil {
+		t.Errorf("Expected no annotations on Ingress %s, got %v", defaultsIngress.Name, defaultsIngress.Annotations)
+	}
+}
+
+func TestWarnUnsupportedControllerConfigKeys(t *testing.T) {
+	configMap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"},
+		Data: map[string]string{
+			"proxy-body-size":       "1m",
+			"hsts":                  "true",
+			"server-tokens":         "true",
+			"use-forwarded-headers": "true",
+			"enable-cors":           "true",
+		},
+	}
+
+	actualNotifications := map[notifications.MessageType]int{}
+	notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+		actualNotifications[mType]++
+	}
+
+	warnUnsupportedControllerConfigKeys(notify, configMap)
+
+	// server-tokens and use-forwarded-headers aren't converted, and CORS can only be
+	// enabled by the annotation.
+	if diff := cmp.Diff(map[notifications.MessageType]int{notifications.WarningNotification: 3}, actualNotifications); diff != "" {
+		t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+	}
+}
+
+func TestControllerHSTS(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		data                  map[string]string
+		expected              *emitterir.HSTS
+		expectedNotifications int
+	}{
+		{
+			name:     "defaults",
+			data:     map[string]string{},
+			expected: &emitterir.HSTS{MaxAge: 31536000, IncludeSubDomains: true},
+		},
+		{
+			name: "configured",
+			data: map[string]string{
+				hstsMaxAgeKey:            "600",
+				hstsIncludeSubdomainsKey: "false",
+				hstsPreloadKey:           "true",
+			},
+			expected: &emitterir.HSTS{MaxAge: 600, Preload: true},
+		},
+		{
+			name: "disabled",
+			data: map[string]string{hstsKey: "false", hstsMaxAgeKey: "600"},
+		},
+		{
+			name:                  "invalid values",
+			data:                  map[string]string{hstsMaxAgeKey: "1y", hstsPreloadKey: "yes"},
+			expected:              &emitterir.HSTS{MaxAge: 31536000, IncludeSubDomains: true},
+			expectedNotifications: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMap := &apiv1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"},
+				Data:       tc.data,
+			}
+			notificationCount := 0
+			notify := func(notifications.MessageType, string, ...client.Object) {
+				notificationCount++
+			}
+
+			hsts := controllerHSTS(notify, configMap)
+			if diff := cmp.Diff(tc.expected, hsts); diff != "" {
+				t.Errorf("Unexpected HSTS (-want +got):\n%s", diff)
+			}
+			if notificationCount != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %d", tc.expectedNotifications, notificationCount)
+			}
+		})
+	}
+}
@@ -33,25 +33,38 @@ import (
 type resourcesToIRConverter struct {
 	featureParsers []i2gw.FeatureParser
 	notify         notifications.NotifyFunc
+	// ingressClass names the Gateways the TCP and UDP services are exposed on.
+	ingressClass string
+	// storage holds the resources being converted, for the feature parsers
+	// reading other resources than the Ingresses.
+	storage *storage
 }
 
 // newResourcesToIRConverter returns an ingress-nginx resourcesToIRConverter instance.
-func newResourcesToIRConverter(notify notifications.NotifyFunc) *resourcesToIRConverter {
-	return &resourcesToIRConverter{
-		featureParsers: []i2gw.FeatureParser{
-			canaryFeature,
-			createBackendTLSPolicies,
-			redirectFeature,
-			headerModifierFeature,
-			regexFeature,
-			backendTLSFeature,
-			sessionAffinityFeature,
-		},
-		notify: notify,
+func newResourcesToIRConverter(notify notifications.NotifyFunc, ingressClass string) *resourcesToIRConverter {
+	c := &resourcesToIRConverter{
+		notify:       notify,
+		ingressClass: ingressClass,
 	}
+	c.featureParsers = []i2gw.FeatureParser{
+		canaryFeature,
+		createBackendTLSPolicies,
+		redirectFeature,
+		headerModifierFeature,
+		defaultBackendFeature,
+		regexFeature,
+		backendTLSFeature,
+		sessionAffinityFeature,
+		c.loadBalancingFeature,
+		mirrorFeature,
+		c.customHeadersFeature,
+	}
+	return c
 }
 
 func (c *resourcesToIRConverter) convert(notify notifications.NotifyFunc, storage *storage) (providerir.ProviderIR, field.ErrorList) {
+	c.storage = storage
+	warnUnsupportedControllerConfigKeys(c.notify, storage.ControllerConfig)
 
 	// TODO(liorliberman) temporary until we decide to change ToIR and featureParsers to get a map of [types.NamespacedName]*networkingv1.Ingress instead of a list
 	ingressList := storage.Ingresses.List()
@@ -65,7 +78,10 @@ func (c *resourcesToIRConverter) convert(notify notifications.NotifyFunc, storag
 			switch strings.ToUpper(val) {
 			case "GRPC", "GRPCS":
 				grpcIngresses = append(grpcIngresses, ing)
-			case "HTTP", "HTTPS", "AUTO_HTTP":
+			case "HTTP", "HTTPS", "H2C":
+				httpIngresses = append(httpIngresses, ing)
+			case "AUTO_HTTP":
+				notify(notifications.WarningNotification, fmt.Sprintf("AUTO_HTTP backend-protocol of ingress %s/%s sends the requests to the backends with the scheme of the client, they are sent with HTTP", ing.Namespace, ing.Name), &ing)
 				httpIngresses = append(httpIngresses, ing)
 			default:
 				// Should cover FCGI and unknown
@@ -88,6 +104,12 @@ func (c *resourcesToIRConverter) convert(notify notifications.NotifyFunc, storag
 		ToImplementationSpecificHTTPPathTypeMatch: implementationSpecificPathMatch,
 	})
 
+	// SSL passthrough replaces the HTTP rules and listeners of the hosts, so it is
+	// converted before the listeners are inspected and the features are parsed.
+	if len(errs) == 0 {
+		errs = append(errs, sslPassthroughFeature(c.notify, ingressList, storage.ServicePorts, &pIR)...)
+	}
+
 	// Warn about hosts that lack TLS certificates. Ingress NGINX serves TLS
 	// for all hosts using a self-signed certificate when no explicit cert is
 	// configured. We do not translate this behavior.
@@ -115,6 +137,8 @@ func (c *resourcesToIRConverter) convert(notify notifications.NotifyFunc, storag
 		}
 	}
 
+	common.InspectTLSSecrets(notify, &pIR, storage.Secrets)
+
 	for _, ingress := range ingressList {
 		for annotation := range ingress.Annotations {
 			if _, ok := parsedAnnotations[annotation]; !ok && strings.HasPrefix(annotation, ingressNGINXAnnotationsPrefix) {
@@ -134,6 +158,8 @@ func (c *resourcesToIRConverter) convert(notify notifications.NotifyFunc, storag
 		errs = append(errs, parseErrs...)
 	}
 
+	convertStreamServices(c.notify, storage, c.ingressClass, &pIR)
+
 	return pIR, errs
 }
 
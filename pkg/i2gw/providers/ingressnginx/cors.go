@@ -32,6 +32,7 @@ import (
 // It matches the pattern of applyRewriteTargetToEmitterIR by applying changes directly to EmitterIR
 // after the initial ProviderIR -> EmitterIR conversion.
 func (p *Provider) applyCorsToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	defaults := p.storage.controllerDefaults()
 	for key, pRouteCtx := range pIR.HTTPRoutes {
 		eRouteCtx, ok := eIR.HTTPRoutes[key]
 		if !ok {
@@ -50,7 +51,7 @@ func (p *Provider) applyCorsToEmitterIR(pIR providerir.ProviderIR, eIR *emitteri
 			}
 
 			// Check if CORS is enabled
-			enableCors, ok := ing.Annotations[EnableCorsAnnotation]
+			enableCors, ok := defaults.annotation(ing, EnableCorsAnnotation)
 			if !ok {
 				continue
 			}
@@ -0,0 +1,222 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// defaultBackendServicePort is the port of the default-backend Service when its
+// single port can't be found.
+const defaultBackendServicePort = 80
+
+// defaultBackendFeature routes the paths of the hosts without a root path to the
+// default-backend Service of their Ingresses, like ingress-nginx does. The catch-all
+// rule has the lowest precedence, so it only receives the unmatched paths.
+func defaultBackendFeature(notify notifications.NotifyFunc, ingresses []networkingv1.Ingress, servicePorts map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	var errs field.ErrorList
+
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		if ingress.Annotations[DefaultBackendAnnotation] == "" {
+			continue
+		}
+		if service, found := defaultBackendService(ingress, servicePorts); !found {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Cannot find the single port of default-backend Service %s/%s, port %d is used", ingress.Namespace, service.Name, defaultBackendServicePort), ingress)
+		}
+	}
+
+	for key, httpRouteCtx := range ir.HTTPRoutes {
+		if slices.ContainsFunc(httpRouteCtx.Spec.Rules, isRootRule) {
+			continue
+		}
+
+		for _, sources := range httpRouteCtx.RuleBackendSources {
+			ingress := getNonCanaryIngress(sources)
+			if ingress == nil || ingress.Annotations[DefaultBackendAnnotation] == "" {
+				continue
+			}
+
+			service, _ := defaultBackendService(ingress, servicePorts)
+			backend := networkingv1.IngressBackend{Service: &service}
+			backendRef, err := common.ToBackendRef(ingress.Namespace, backend, servicePorts, field.NewPath(ingress.Namespace, ingress.Name, "metadata", "annotations", fmt.Sprintf("%q", DefaultBackendAnnotation)))
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+
+			httpRouteCtx.Spec.Rules = append(httpRouteCtx.Spec.Rules, gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{
+						Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+						Value: ptr.To("/"),
+					},
+				}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: *backendRef}},
+			})
+			httpRouteCtx.RuleBackendSources = append(httpRouteCtx.RuleBackendSources, []providerir.BackendSource{{
+				Ingress:        ingress,
+				DefaultBackend: &backend,
+			}})
+			ir.HTTPRoutes[key] = httpRouteCtx
+
+			notify(notifications.InfoNotification,
+				fmt.Sprintf("Unmatched paths of HTTPRoute %s are routed to default-backend Service %s", key, service.Name), &httpRouteCtx.HTTPRoute)
+			break
+		}
+	}
+
+	return errs
+}
+
+// isRootRule returns whether the rule matches every path.
+func isRootRule(rule gatewayv1.HTTPRouteRule) bool {
+	if len(rule.Matches) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(rule.Matches, func(match gatewayv1.HTTPRouteMatch) bool {
+		return match.Path == nil || (ptr.Deref(match.Path.Type, gatewayv1.PathMatchPathPrefix) == gatewayv1.PathMatchPathPrefix && ptr.Deref(match.Path.Value, "/") == "/")
+	})
+}
+
+// defaultBackendService returns the default-backend Service of the Ingress. Like
+// ingress-nginx, its first port is used, which is only known when it is the single
+// port of the Service.
+func defaultBackendService(ingress *networkingv1.Ingress, servicePorts map[types.NamespacedName]map[string]int32) (networkingv1.IngressServiceBackend, bool) {
+	service := networkingv1.IngressServiceBackend{
+		Name: ingress.Annotations[DefaultBackendAnnotation],
+		Port: networkingv1.ServiceBackendPort{Number: defaultBackendServicePort},
+	}
+	ports := servicePorts[types.NamespacedName{Namespace: ingress.Namespace, Name: service.Name}]
+	if len(ports) != 1 {
+		return service, false
+	}
+	for _, port := range ports {
+		service.Port.Number = port
+	}
+	return service, true
+}
+
+// applyCustomErrorsToEmitterIR reads the custom-http-errors and default-backend
+// annotations from ProviderIR sources and stores provider-neutral custom error
+// response intent into EmitterIR, which will later be converted by each custom emitter.
+func (p *Provider) applyCustomErrorsToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.CustomErrorResponses{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			sources := pRouteCtx.RuleBackendSources[ruleIdx]
+			ing := getNonCanaryIngress(sources)
+			if ing == nil {
+				continue
+			}
+			// The default backends serve the error responses themselves.
+			if slices.ContainsFunc(sources, func(source providerir.BackendSource) bool { return source.DefaultBackend != nil }) {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			customErrors, found := parsed[ingKey]
+			if !found {
+				customErrors = p.parseCustomErrors(ing)
+				parsed[ingKey] = customErrors
+			}
+			if customErrors == nil {
+				continue
+			}
+
+			if eRouteCtx.CustomErrorResponsesByRuleIdx == nil {
+				eRouteCtx.CustomErrorResponsesByRuleIdx = make(map[int]*emitterir.CustomErrorResponses)
+			}
+			eRouteCtx.CustomErrorResponsesByRuleIdx[ruleIdx] = customErrors
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseCustomErrors returns the custom error response intent of the Ingress, or nil if
+// it doesn't intercept any status code.
+func (p *Provider) parseCustomErrors(ing *networkingv1.Ingress) *emitterir.CustomErrorResponses {
+	rawCodes := strings.TrimSpace(ing.Annotations[CustomHTTPErrorsAnnotation])
+	if rawCodes == "" {
+		return nil
+	}
+
+	var codes []int
+	for _, rawCode := range strings.Split(rawCodes, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(rawCode))
+		if err != nil || code < 100 || code > 599 {
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid status code %q in custom-http-errors annotation, it is ignored", rawCode), ing)
+			continue
+		}
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	if ing.Annotations[DefaultBackendAnnotation] == "" {
+		p.notify(notifications.WarningNotification,
+			"custom-http-errors is served by the default backend of the controller, which is not converted; set the default-backend annotation to convert the error responses", ing)
+		return nil
+	}
+	service, _ := defaultBackendService(ing, p.storage.ServicePorts)
+	backendRef, err := common.ToBackendRef(ing.Namespace, networkingv1.IngressBackend{Service: &service}, p.storage.ServicePorts, field.NewPath(ing.Namespace, ing.Name))
+	if err != nil {
+		return nil
+	}
+
+	var paths []*field.Path
+	for _, annotation := range []string{CustomHTTPErrorsAnnotation, DefaultBackendAnnotation} {
+		paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+	}
+	return &emitterir.CustomErrorResponses{
+		Metadata: emitterir.NewExtensionFeatureMetadata(
+			fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+			paths,
+			"Custom error responses are not supported, the error responses of the backends are returned",
+		),
+		StatusCodes: codes,
+		Backend:     backendRef.BackendObjectReference,
+	}
+}
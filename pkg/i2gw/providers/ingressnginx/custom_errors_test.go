@@ -0,0 +1,237 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func customErrorsIngress(name string, annotations map[string]string, paths ...string) networkingv1.Ingress {
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("nginx"),
+			Rules: []networkingv1.IngressRule{{
+				Host: "app.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{},
+				},
+			}},
+		},
+	}
+	for _, path := range paths {
+		ingress.Spec.Rules[0].HTTP.Paths = append(ingress.Spec.Rules[0].HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 80}},
+			},
+		})
+	}
+	return ingress
+}
+
+func TestDefaultBackendFeature(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "app-app-example-com"}
+	servicePorts := map[types.NamespacedName]map[string]int32{
+		{Namespace: "default", Name: "error-pages"}: {"http": 8080},
+		{Namespace: "default", Name: "multi-port"}:  {"http": 8080, "metrics": 9090},
+	}
+
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		expectedFallback      *gatewayv1.BackendObjectReference
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "host without root path",
+			ingresses: []networkingv1.Ingress{
+				customErrorsIngress("app", map[string]string{DefaultBackendAnnotation: "error-pages"}, "/api"),
+			},
+			expectedFallback:      &gatewayv1.BackendObjectReference{Name: "error-pages", Port: ptr.To(gatewayv1.PortNumber(8080))},
+			expectedNotifications: map[notifications.MessageType]int{notifications.InfoNotification: 1},
+		},
+		{
+			name: "host with root path",
+			ingresses: []networkingv1.Ingress{
+				customErrorsIngress("app", map[string]string{DefaultBackendAnnotation: "error-pages"}, "/api"),
+				customErrorsIngress("root", nil, "/"),
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "service with several ports",
+			ingresses: []networkingv1.Ingress{
+				customErrorsIngress("app", map[string]string{DefaultBackendAnnotation: "multi-port"}, "/api"),
+			},
+			expectedFallback: &gatewayv1.BackendObjectReference{Name: "multi-port", Port: ptr.To(gatewayv1.PortNumber(defaultBackendServicePort))},
+			expectedNotifications: map[notifications.MessageType]int{
+				notifications.InfoNotification:    1,
+				notifications.WarningNotification: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, nil, servicePorts, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting the Ingresses: %v", errs)
+			}
+			rulesBefore := len(ir.HTTPRoutes[routeKey].Spec.Rules)
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			if errs = defaultBackendFeature(notify, tc.ingresses, servicePorts, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			routeCtx := ir.HTTPRoutes[routeKey]
+			if tc.expectedFallback == nil {
+				if len(routeCtx.Spec.Rules) != rulesBefore {
+					t.Errorf("Expected no fallback rule, got rules %+v", routeCtx.Spec.Rules)
+				}
+			} else {
+				if len(routeCtx.Spec.Rules) != rulesBefore+1 || len(routeCtx.RuleBackendSources) != rulesBefore+1 {
+					t.Fatalf("Expected a fallback rule, got rules %+v", routeCtx.Spec.Rules)
+				}
+				fallback := routeCtx.Spec.Rules[rulesBefore]
+				if !isRootRule(fallback) {
+					t.Errorf("Expected the fallback rule to match every path, got %+v", fallback.Matches)
+				}
+				if diff := cmp.Diff(*tc.expectedFallback, fallback.BackendRefs[0].BackendObjectReference); diff != "" {
+					t.Errorf("Unexpected fallback backend (-want +got):\n%s", diff)
+				}
+				if routeCtx.RuleBackendSources[rulesBefore][0].DefaultBackend == nil {
+					t.Errorf("Expected the fallback rule to come from a default backend")
+				}
+			}
+
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyCustomErrorsToEmitterIR(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "route"}
+	servicePorts := map[types.NamespacedName]map[string]int32{
+		{Namespace: "default", Name: "error-pages"}: {"http": 8080},
+	}
+
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expected              *emitterir.CustomErrorResponses
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "status codes with default backend",
+			annotations: map[string]string{
+				CustomHTTPErrorsAnnotation: "404, 503,404",
+				DefaultBackendAnnotation:   "error-pages",
+			},
+			expected: &emitterir.CustomErrorResponses{
+				StatusCodes: []int{404, 503},
+				Backend:     gatewayv1.BackendObjectReference{Name: "error-pages", Port: ptr.To(gatewayv1.PortNumber(8080))},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "invalid status codes",
+			annotations: map[string]string{
+				CustomHTTPErrorsAnnotation: "404,abc,99",
+				DefaultBackendAnnotation:   "error-pages",
+			},
+			expected: &emitterir.CustomErrorResponses{
+				StatusCodes: []int{404},
+				Backend:     gatewayv1.BackendObjectReference{Name: "error-pages", Port: ptr.To(gatewayv1.PortNumber(8080))},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 2},
+		},
+		{
+			name:                  "status codes without default backend",
+			annotations:           map[string]string{CustomHTTPErrorsAnnotation: "404"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name:                  "default backend only",
+			annotations:           map[string]string{DefaultBackendAnnotation: "error-pages"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := customErrorsIngress("app", tc.annotations, "/api")
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					routeKey: {
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing, Path: &ing.Spec.Rules[0].HTTP.Paths[0]}},
+							{{Ingress: &ing, DefaultBackend: &networkingv1.IngressBackend{}}},
+						},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					routeKey: {
+						HTTPRoute: gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}}}},
+					},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify, storage: &storage{ServicePorts: servicePorts}}
+			p.applyCustomErrorsToEmitterIR(pIR, &eIR)
+
+			customErrors := eIR.HTTPRoutes[routeKey].CustomErrorResponsesByRuleIdx
+			if diff := cmp.Diff(tc.expected, customErrors[0], cmpopts.IgnoreFields(emitterir.CustomErrorResponses{}, "Metadata")); diff != "" {
+				t.Errorf("Unexpected custom error responses (-want +got):\n%s", diff)
+			}
+			if customErrors[1] != nil {
+				t.Errorf("Expected no custom error responses for the default backend rule, got %+v", customErrors[1])
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
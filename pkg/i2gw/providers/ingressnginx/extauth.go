@@ -0,0 +1,133 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// applyExternalAuthToEmitterIR reads the ingress-nginx external authentication annotations from
+// ProviderIR sources and stores provider-neutral external authentication intent into EmitterIR,
+// which will later be converted by each custom emitter.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/auth-url
+// - nginx.ingress.kubernetes.io/auth-method
+// - nginx.ingress.kubernetes.io/auth-response-headers
+// - nginx.ingress.kubernetes.io/auth-signin
+// - nginx.ingress.kubernetes.io/auth-cache-key
+// - nginx.ingress.kubernetes.io/auth-cache-duration
+func (p *Provider) applyExternalAuthToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.ExternalAuth{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			externalAuth, found := parsed[ingKey]
+			if !found {
+				externalAuth = p.parseExternalAuth(ing)
+				parsed[ingKey] = externalAuth
+			}
+			if externalAuth == nil {
+				continue
+			}
+
+			if eRouteCtx.ExternalAuthByRuleIdx == nil {
+				eRouteCtx.ExternalAuthByRuleIdx = make(map[int]*emitterir.ExternalAuth)
+			}
+			eRouteCtx.ExternalAuthByRuleIdx[ruleIdx] = externalAuth
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseExternalAuth returns the external authentication intent of the Ingress,
+// or nil if it has none or its configuration is invalid.
+func (p *Provider) parseExternalAuth(ing *networkingv1.Ingress) *emitterir.ExternalAuth {
+	rawURL := strings.TrimSpace(ing.Annotations[AuthURLAnnotation])
+	if rawURL == "" {
+		return nil
+	}
+
+	authURL, err := url.Parse(rawURL)
+	if err != nil || (authURL.Scheme != "http" && authURL.Scheme != "https") || authURL.Host == "" {
+		p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid auth-url annotation %q: an absolute http or https URL is expected, skipping external authentication", rawURL), ing)
+		return nil
+	}
+
+	externalAuth := emitterir.ExternalAuth{
+		URL:       rawURL,
+		Service:   serviceFromURL(authURL),
+		Path:      authURL.Path,
+		Method:    strings.ToUpper(strings.TrimSpace(ing.Annotations[AuthMethodAnnotation])),
+		SigninURL: strings.TrimSpace(ing.Annotations[AuthSigninAnnotation]),
+		CacheKey:  strings.TrimSpace(ing.Annotations[AuthCacheKeyAnnotation]),
+		// ingress-nginx sends all the headers of the client request to the
+		// authentication service.
+		RequestHeaders: nil,
+		CacheDuration:  strings.TrimSpace(ing.Annotations[AuthCacheDurationAnnotation]),
+	}
+	for _, header := range strings.Split(ing.Annotations[AuthResponseHeadersAnnotation], ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			externalAuth.ResponseHeaders = append(externalAuth.ResponseHeaders, header)
+		}
+	}
+
+	if externalAuth.Service != nil && authURL.Scheme == "https" {
+		p.notify(notifications.WarningNotification, fmt.Sprintf("auth-url %q is served over TLS, a BackendTLSPolicy is needed for the authentication Service %s/%s",
+			rawURL, *externalAuth.Service.Namespace, externalAuth.Service.Name), ing)
+	}
+
+	source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+	var paths []*field.Path
+	for _, annotation := range []string{AuthURLAnnotation, AuthMethodAnnotation, AuthResponseHeadersAnnotation, AuthSigninAnnotation, AuthCacheKeyAnnotation, AuthCacheDurationAnnotation} {
+		if _, ok := ing.Annotations[annotation]; ok {
+			paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+		}
+	}
+	externalAuth.Metadata = emitterir.NewExtensionFeatureMetadata(
+		source,
+		paths,
+		"External authentication is not supported",
+	)
+
+	return &externalAuth
+}
@@ -0,0 +1,175 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestApplyExternalAuthToEmitterIR(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedExternalAuth  *emitterir.ExternalAuth
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "in-cluster auth service with all options",
+			annotations: map[string]string{
+				AuthURLAnnotation:             "http://auth.auth-system.svc.cluster.local:8080/verify",
+				AuthMethodAnnotation:          "post",
+				AuthResponseHeadersAnnotation: "X-User, X-Email",
+				AuthSigninAnnotation:          "https://login.example.com/start",
+				AuthCacheKeyAnnotation:        "$remote_user",
+				AuthCacheDurationAnnotation:   "200 202 10m",
+			},
+			expectedExternalAuth: &emitterir.ExternalAuth{
+				URL: "http://auth.auth-system.svc.cluster.local:8080/verify",
+				Service: &gatewayv1.BackendObjectReference{
+					Name:      "auth",
+					Namespace: ptr.To(gatewayv1.Namespace("auth-system")),
+					Port:      ptr.To(gatewayv1.PortNumber(8080)),
+				},
+				Path:            "/verify",
+				Method:          "POST",
+				ResponseHeaders: []string{"X-User", "X-Email"},
+				SigninURL:       "https://login.example.com/start",
+				CacheKey:        "$remote_user",
+				CacheDuration:   "200 202 10m",
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "https auth service defaults to port 443",
+			annotations: map[string]string{
+				AuthURLAnnotation: "https://auth.default.svc/",
+			},
+			expectedExternalAuth: &emitterir.ExternalAuth{
+				URL: "https://auth.default.svc/",
+				Service: &gatewayv1.BackendObjectReference{
+					Name:      "auth",
+					Namespace: ptr.To(gatewayv1.Namespace("default")),
+					Port:      ptr.To(gatewayv1.PortNumber(443)),
+				},
+				Path: "/",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "external auth service",
+			annotations: map[string]string{
+				AuthURLAnnotation: "https://auth.example.com/oauth2/auth",
+			},
+			expectedExternalAuth: &emitterir.ExternalAuth{
+				URL:  "https://auth.example.com/oauth2/auth",
+				Path: "/oauth2/auth",
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "invalid auth-url",
+			annotations: map[string]string{
+				AuthURLAnnotation: "/relative/path",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name:                  "no auth-url",
+			annotations:           map[string]string{AuthMethodAnnotation: "GET"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ext-auth",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{Host: "example.com"}},
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			route := gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+				},
+			}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: route,
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing}},
+							{{Ingress: &ing}},
+						},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {HTTPRoute: route},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			(&Provider{notify: notify}).applyExternalAuthToEmitterIR(pIR, &eIR)
+
+			result := eIR.HTTPRoutes[key].ExternalAuthByRuleIdx
+			if tc.expectedExternalAuth == nil {
+				if result != nil {
+					t.Fatalf("Expected no ExternalAuth, got %v", result)
+				}
+			} else {
+				if len(result) != 2 {
+					t.Fatalf("Expected ExternalAuth for 2 rules, got %d", len(result))
+				}
+				for idx, externalAuth := range result {
+					if diff := cmp.Diff(tc.expectedExternalAuth, externalAuth, cmpopts.IgnoreFields(emitterir.ExternalAuth{}, "Metadata")); diff != "" {
+						t.Errorf("Unexpected ExternalAuth for rule %d (-want +got):\n%s", idx, diff)
+					}
+				}
+			}
+			// Notifications are only reported once per Ingress.
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,10 @@ package ingressnginx
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
@@ -52,12 +56,6 @@ func headerModifierFeature(notify notifications.NotifyFunc, _ []networkingv1.Ing
 				headersToSet["Connection"] = val
 			}
 
-			// 3. custom-headers -> Warn unsupported
-			// TODO: implement custom-headers annotation.
-			if _, ok := ingress.Annotations[CustomHeadersAnnotation]; ok {
-				notify(notifications.WarningNotification, fmt.Sprintf("Ingress %s/%s uses '%s' which is not supported.", ingress.Namespace, ingress.Name, CustomHeadersAnnotation), &httpRouteContext.HTTPRoute)
-			}
-
 			if len(headersToSet) > 0 {
 				applyHeaderModifiers(notify, &httpRouteContext.HTTPRoute, i, headersToSet)
 			}
@@ -66,6 +64,114 @@ func headerModifierFeature(notify notifications.NotifyFunc, _ []networkingv1.Ing
 	return nil
 }
 
+// globalAllowedResponseHeadersKey is the controller ConfigMap key listing the
+// headers the custom-headers ConfigMaps may set.
+const globalAllowedResponseHeadersKey = "global-allowed-response-headers"
+
+// headerNameRegex matches the header names Gateway API accepts.
+var headerNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+\\-.^_`|~]+$")
+
+// customHeadersFeature converts the custom-headers annotation, which references a
+// ConfigMap of response headers, into ResponseHeaderModifier filters setting them
+// on every rule of the Ingress.
+func (c *resourcesToIRConverter) customHeadersFeature(notify notifications.NotifyFunc, _ []networkingv1.Ingress, _ map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]map[string]string{}
+
+	for _, httpRouteContext := range ir.HTTPRoutes {
+		for i := range httpRouteContext.HTTPRoute.Spec.Rules {
+			if i >= len(httpRouteContext.RuleBackendSources) {
+				continue
+			}
+			ingress := getNonCanaryIngress(httpRouteContext.RuleBackendSources[i])
+			if ingress == nil {
+				continue
+			}
+
+			ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+			headers, found := parsed[ingressKey]
+			if !found {
+				headers = parseCustomHeaders(notify, ingress, c.storage)
+				parsed[ingressKey] = headers
+			}
+
+			if len(headers) > 0 {
+				applyResponseHeaderModifiers(&httpRouteContext.HTTPRoute, i, headers)
+			}
+		}
+	}
+	return nil
+}
+
+// parseCustomHeaders returns the response headers of the ConfigMap referenced by
+// the custom-headers annotation of the Ingress. Like ingress-nginx, the annotation
+// is ignored when the ConfigMap is missing or the controller ConfigMap doesn't allow
+// one of the headers.
+func parseCustomHeaders(notify notifications.NotifyFunc, ingress *networkingv1.Ingress, storage *storage) map[string]string {
+	key, ok := annotationObjectRef(ingress, CustomHeadersAnnotation)
+	if !ok {
+		return nil
+	}
+	configMap, ok := storage.ConfigMaps[key]
+	if !ok {
+		notify(notifications.ErrorNotification,
+			fmt.Sprintf("ConfigMap %s of the %s annotation is not found, the annotation is ignored", key, CustomHeadersAnnotation), ingress)
+		return nil
+	}
+
+	var allowed []string
+	if storage.ControllerConfig != nil {
+		for _, header := range strings.Split(storage.ControllerConfig.Data[globalAllowedResponseHeadersKey], ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				allowed = append(allowed, strings.ToLower(header))
+			}
+		}
+	}
+
+	headers := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(configMap.Data)) {
+		if !headerNameRegex.MatchString(name) {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Invalid header name %q in ConfigMap %s of the %s annotation, the header is ignored", name, key, CustomHeadersAnnotation), ingress)
+			continue
+		}
+		if storage.ControllerConfig != nil && !slices.Contains(allowed, strings.ToLower(name)) {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Header %q in ConfigMap %s is not allowed by %s of the controller ConfigMap, the %s annotation is ignored", name, key, globalAllowedResponseHeadersKey, CustomHeadersAnnotation), ingress)
+			return nil
+		}
+		if strings.Contains(configMap.Data[name], "$") {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Value of header %q in ConfigMap %s may use NGINX variables, which are copied verbatim", name, key), ingress)
+		}
+		headers[name] = configMap.Data[name]
+	}
+	return headers
+}
+
+// applyResponseHeaderModifiers sets the headers in the ResponseHeaderModifier filter of the rule.
+func applyResponseHeaderModifiers(httpRoute *gatewayv1.HTTPRoute, ruleIndex int, headersToSet map[string]string) {
+	rule := &httpRoute.Spec.Rules[ruleIndex]
+	filterIdx := slices.IndexFunc(rule.Filters, func(f gatewayv1.HTTPRouteFilter) bool {
+		return f.Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil
+	})
+	if filterIdx == -1 {
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+			Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{},
+		})
+		filterIdx = len(rule.Filters) - 1
+	}
+
+	filter := rule.Filters[filterIdx].ResponseHeaderModifier
+	for _, name := range slices.Sorted(maps.Keys(headersToSet)) {
+		filter.Set = append(filter.Set, gatewayv1.HTTPHeader{
+			Name:  gatewayv1.HTTPHeaderName(name),
+			Value: headersToSet[name],
+		})
+	}
+}
+
 func applyHeaderModifiers(_ notifications.NotifyFunc, httpRoute *gatewayv1.HTTPRoute, ruleIndex int, headersToSet map[string]string) {
 	// Find existing RequestHeaderModifier filter or create new one
 	var filter *gatewayv1.HTTPRouteFilter
@@ -87,11 +193,11 @@ func applyHeaderModifiers(_ notifications.NotifyFunc, httpRoute *gatewayv1.HTTPR
 		filter = &httpRoute.Spec.Rules[ruleIndex].Filters[len(httpRoute.Spec.Rules[ruleIndex].Filters)-1]
 	}
 
-	for name, value := range headersToSet {
+	for _, name := range slices.Sorted(maps.Keys(headersToSet)) {
 		// Used standard append as suggested in PR review
 		filter.RequestHeaderModifier.Set = append(filter.RequestHeaderModifier.Set, gatewayv1.HTTPHeader{
 			Name:  gatewayv1.HTTPHeaderName(name),
-			Value: value,
+			Value: headersToSet[name],
 		})
 	}
 }
@@ -19,13 +19,16 @@ package ingressnginx
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -247,3 +250,120 @@ func TestHeaderModifierFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomHeadersFeature(t *testing.T) {
+	ingress := func(annotation string) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "default",
+				Annotations: map[string]string{CustomHeadersAnnotation: annotation},
+			},
+		}
+	}
+	customHeaders := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "custom-headers"},
+		Data: map[string]string{
+			"X-Frame-Options": "DENY",
+			"X-Served-By":     "ingress",
+			"Bad Header":      "value",
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		ingress               networkingv1.Ingress
+		controllerConfig      map[string]string
+		expectedFilters       []gatewayv1.HTTPRouteFilter
+		expectedNotifications int
+	}{
+		{
+			name:    "custom headers",
+			ingress: ingress("custom-headers"),
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+					Set: []gatewayv1.HTTPHeader{
+						{Name: "X-Frame-Options", Value: "DENY"},
+						{Name: "X-Served-By", Value: "ingress"},
+					},
+				},
+			}},
+			expectedNotifications: 1,
+		},
+		{
+			name:             "allowed headers",
+			ingress:          ingress("default/custom-headers"),
+			controllerConfig: map[string]string{globalAllowedResponseHeadersKey: "x-frame-options, X-Served-By"},
+			expectedFilters: []gatewayv1.HTTPRouteFilter{{
+				Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+					Set: []gatewayv1.HTTPHeader{
+						{Name: "X-Frame-Options", Value: "DENY"},
+						{Name: "X-Served-By", Value: "ingress"},
+					},
+				},
+			}},
+			expectedNotifications: 1,
+		},
+		{
+			name:                  "header not allowed",
+			ingress:               ingress("custom-headers"),
+			controllerConfig:      map[string]string{globalAllowedResponseHeadersKey: "X-Frame-Options"},
+			expectedNotifications: 2,
+		},
+		{
+			name:                  "missing ConfigMap",
+			ingress:               ingress("other/custom-headers"),
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.ConfigMaps = map[types.NamespacedName]*apiv1.ConfigMap{
+				{Namespace: "default", Name: "custom-headers"}: customHeaders,
+			}
+			if tc.controllerConfig != nil {
+				storage.ControllerConfig = &apiv1.ConfigMap{Data: tc.controllerConfig}
+			}
+
+			key := types.NamespacedName{Namespace: "default", Name: "app-example-com"}
+			ir := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+							},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &tc.ingress}},
+							{{Ingress: &tc.ingress}},
+						},
+					},
+				},
+			}
+
+			notificationCount := 0
+			notify := func(notifications.MessageType, string, ...client.Object) {
+				notificationCount++
+			}
+
+			c := &resourcesToIRConverter{storage: storage}
+			if errs := c.customHeadersFeature(notify, nil, nil, &ir); len(errs) > 0 {
+				t.Errorf("Unexpected errors: %v", errs)
+			}
+			for i, rule := range ir.HTTPRoutes[key].HTTPRoute.Spec.Rules {
+				if diff := cmp.Diff(tc.expectedFilters, rule.Filters); diff != "" {
+					t.Errorf("Unexpected filters of rule %d (-want +got):\n%s", i, diff)
+				}
+			}
+			if notificationCount != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %d", tc.expectedNotifications, notificationCount)
+			}
+		})
+	}
+}
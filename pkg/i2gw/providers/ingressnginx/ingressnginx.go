@@ -32,14 +32,34 @@ import (
 const Name = "ingress-nginx"
 const NginxIngressClass = "nginx"
 const NginxIngressClassFlag = "ingress-class"
+const TCPServicesConfigMapFlag = "tcp-services-configmap"
+const UDPServicesConfigMapFlag = "udp-services-configmap"
+const ControllerConfigMapFlag = "controller-configmap"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.ProviderDetectorByName[Name] = i2gw.DetectionRules{
+		IngressClasses:     []string{NginxIngressClass},
+		Controllers:        []string{"k8s.io/ingress-nginx"},
+		AnnotationPrefixes: []string{ingressNGINXAnnotationsPrefix},
+	}.Detect
 	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
 		Name:         "ingress-class",
 		Description:  "The name of the ingress class to select. Defaults to 'nginx'",
 		DefaultValue: NginxIngressClass,
 	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:        TCPServicesConfigMapFlag,
+		Description: "The namespace/name of the ConfigMap exposing TCP services, like the --tcp-services-configmap flag of the controller",
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:        UDPServicesConfigMapFlag,
+		Description: "The namespace/name of the ConfigMap exposing UDP services, like the --udp-services-configmap flag of the controller",
+	})
+	i2gw.RegisterProviderSpecificFlag(Name, i2gw.ProviderSpecificFlag{
+		Name:        ControllerConfigMapFlag,
+		Description: "The namespace/name of the ConfigMap configuring the controller, like the --configmap flag of the controller",
+	})
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -53,11 +73,12 @@ type Provider struct {
 // NewProvider constructs and returns the ingress-nginx implementation of i2gw.Provider.
 func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 	notify := conf.Report.Notifier(Name)
+	resourceReader := newResourceReader(conf)
 
 	return &Provider{
 		storage:                newResourcesStorage(),
-		resourceReader:         newResourceReader(conf),
-		resourcesToIRConverter: newResourcesToIRConverter(notify),
+		resourceReader:         resourceReader,
+		resourcesToIRConverter: newResourcesToIRConverter(notify, resourceReader.ingressClass),
 		notify:                 notify,
 	}
 }
@@ -67,11 +88,22 @@ func NewProvider(conf *i2gw.ProviderConf) i2gw.Provider {
 func (p *Provider) ToIR() (emitterir.EmitterIR, field.ErrorList) {
 	pIR, errs := p.resourcesToIRConverter.convert(p.notify, p.storage)
 	eIR := providerir.ToEmitterIR(pIR)
-	applyRewriteTargetToEmitterIR(p.storage.Ingresses.List(), pIR, &eIR)
+	applyRewriteTargetToEmitterIR(p.notify, p.storage.Ingresses.List(), pIR, &eIR)
 	p.applyIPRangeControlToEmitterIR(pIR, &eIR)
 	p.applyTimeoutsToEmitterIR(pIR, &eIR)
+	p.applyRetryToEmitterIR(pIR, &eIR)
 	p.applyCorsToEmitterIR(pIR, &eIR)
-	p.applyBodySizeToEmitterIR(pIR, &eIR)
+	p.applyBufferingToEmitterIR(pIR, &eIR)
+	p.applyExternalAuthToEmitterIR(pIR, &eIR)
+	p.applyBasicAuthToEmitterIR(pIR, &eIR)
+	p.applyRateLimitToEmitterIR(pIR, &eIR)
+	p.applyClientCertificateValidationToEmitterIR(pIR, &eIR)
+	p.applyCustomErrorsToEmitterIR(pIR, &eIR)
+	p.applyProxyRedirectToEmitterIR(pIR, &eIR)
+	p.applyListenerTLSOptionsToEmitterIR(pIR, &eIR)
+	webSocketIngresses := p.addServiceAppProtocolPatches(&eIR)
+	p.applySnippetsToEmitterIR(pIR, &eIR, webSocketIngresses)
+	p.addAppRootRedirects(&pIR, &eIR)
 	p.addSSLAndTrailingSlashRedirects(p.storage.Ingresses.List(), &pIR, &eIR)
 	errs = append(errs, p.addWWWRedirect(&pIR, &eIR)...)
 	return eIR, errs
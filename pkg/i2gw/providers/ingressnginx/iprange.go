@@ -22,11 +22,12 @@ import (
 
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func parseIPSourceRangeAnnotation(annotations map[string]string, key string) []string {
-	value, ok := annotations[key]
+func parseIPSourceRangeAnnotation(defaults controllerDefaults, ing *networkingv1.Ingress, annotation string) []string {
+	value, ok := defaults.annotation(ing, annotation)
 	if !ok {
 		return nil
 	}
@@ -38,6 +39,7 @@ func parseIPSourceRangeAnnotation(annotations map[string]string, key string) []s
 }
 
 func (p *Provider) applyIPRangeControlToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	defaults := p.storage.controllerDefaults()
 	for key, pRouteCtx := range pIR.HTTPRoutes {
 		eRouteCtx, ok := eIR.HTTPRoutes[key]
 		if !ok {
@@ -57,8 +59,8 @@ func (p *Provider) applyIPRangeControlToEmitterIR(pIR providerir.ProviderIR, eIR
 
 			var allowList, denyList []string
 
-			allowList = parseIPSourceRangeAnnotation(ing.Annotations, WhiteListSourceRangeAnnotation)
-			denyList = parseIPSourceRangeAnnotation(ing.Annotations, DenyListSourceRangeAnnotation)
+			allowList = parseIPSourceRangeAnnotation(defaults, ing, WhiteListSourceRangeAnnotation)
+			denyList = parseIPSourceRangeAnnotation(defaults, ing, DenyListSourceRangeAnnotation)
 
 			if len(allowList) == 0 && len(denyList) == 0 {
 				continue
@@ -77,10 +79,10 @@ func (p *Provider) applyIPRangeControlToEmitterIR(pIR providerir.ProviderIR, eIR
 				message := "IP-based authorization is not supported"
 				paths := make([]*field.Path, 0, 2)
 				if len(allowList) > 0 {
-					paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", WhiteListSourceRangeAnnotation)))
+					paths = append(paths, defaults.path(ing, WhiteListSourceRangeAnnotation))
 				}
 				if len(denyList) > 0 {
-					paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", DenyListSourceRangeAnnotation)))
+					paths = append(paths, defaults.path(ing, DenyListSourceRangeAnnotation))
 				}
 				ipRangeControl.Metadata = emitterir.NewExtensionFeatureMetadata(
 					source,
@@ -0,0 +1,193 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// nginxVariableRegex matches an upstream-hash-by made of a single nginx variable.
+var nginxVariableRegex = regexp.MustCompile(`^\$([a-z0-9_]+)$`)
+
+// loadBalancingFeature stores the load-balance and upstream-hash-by annotations as
+// LoadBalancer intent of the Services they apply to. Like ingress-nginx, canary
+// Ingresses keep their own load balancing for their backends.
+func (c *resourcesToIRConverter) loadBalancingFeature(notify notifications.NotifyFunc, _ []networkingv1.Ingress, _ map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	defaults := c.storage.controllerDefaults()
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.LoadBalancer{}
+
+	routeKeys := make([]types.NamespacedName, 0, len(ir.HTTPRoutes))
+	for key := range ir.HTTPRoutes {
+		routeKeys = append(routeKeys, key)
+	}
+	sort.Slice(routeKeys, func(i, j int) bool {
+		return routeKeys[i].String() < routeKeys[j].String()
+	})
+
+	for _, routeKey := range routeKeys {
+		for _, sources := range ir.HTTPRoutes[routeKey].RuleBackendSources {
+			for _, source := range sources {
+				serviceName := backendSourceServiceName(source)
+				if source.Ingress == nil || serviceName == "" {
+					continue
+				}
+
+				ingKey := types.NamespacedName{Namespace: source.Ingress.Namespace, Name: source.Ingress.Name}
+				loadBalancer, found := parsed[ingKey]
+				if !found {
+					loadBalancer = parseLoadBalancer(notify, defaults, source.Ingress)
+					parsed[ingKey] = loadBalancer
+				}
+				if loadBalancer == nil {
+					continue
+				}
+
+				svcKey := types.NamespacedName{Namespace: source.Ingress.Namespace, Name: serviceName}
+				svc := ir.Services[svcKey]
+				if svc.LoadBalancer != nil {
+					if !sameLoadBalancing(svc.LoadBalancer, loadBalancer) {
+						notify(notifications.WarningNotification, fmt.Sprintf("Service %s is load balanced differently by %s, the load balancing of %s is ignored", svcKey, svc.LoadBalancer.Metadata.Source(), loadBalancer.Metadata.Source()), source.Ingress)
+					}
+					continue
+				}
+				svc.LoadBalancer = loadBalancer
+				if ir.Services == nil {
+					ir.Services = make(map[types.NamespacedName]providerir.ProviderSpecificServiceIR)
+				}
+				ir.Services[svcKey] = svc
+			}
+		}
+	}
+	return nil
+}
+
+// backendSourceServiceName returns the name of the Service of the backend, or
+// an empty string if it isn't a Service.
+func backendSourceServiceName(source providerir.BackendSource) string {
+	var backend *networkingv1.IngressBackend
+	if source.Path != nil {
+		backend = &source.Path.Backend
+	} else {
+		backend = source.DefaultBackend
+	}
+	if backend == nil || backend.Service == nil {
+		return ""
+	}
+	return backend.Service.Name
+}
+
+// sameLoadBalancing reports whether both intents balance requests the same way.
+func sameLoadBalancing(a, b *emitterir.LoadBalancer) bool {
+	return a.Algorithm == b.Algorithm && a.HashKey == b.HashKey
+}
+
+// parseLoadBalancer returns the load balancing intent of the Ingress, or nil if
+// it doesn't configure load balancing or it can't be converted.
+func parseLoadBalancer(notify notifications.NotifyFunc, defaults controllerDefaults, ing *networkingv1.Ingress) *emitterir.LoadBalancer {
+	var paths []*field.Path
+	for _, annotation := range []string{LoadBalanceAnnotation, UpstreamHashByAnnotation} {
+		if _, ok := defaults.annotation(ing, annotation); ok {
+			paths = append(paths, defaults.path(ing, annotation))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// ingress-nginx balances requests with the session affinity cookie instead.
+	if ing.Annotations[AffinityAnnotation] == "cookie" {
+		notify(notifications.InfoNotification, "load-balance and upstream-hash-by are ignored by ingress-nginx with cookie affinity", ing)
+		return nil
+	}
+
+	loadBalancer := emitterir.LoadBalancer{
+		Metadata: emitterir.NewExtensionFeatureMetadata(
+			fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+			paths,
+			"Load balancing is not supported, the default load balancing algorithm of the implementation is used",
+		),
+	}
+
+	// upstream-hash-by takes precedence over load-balance.
+	if hashBy := strings.TrimSpace(ing.Annotations[UpstreamHashByAnnotation]); hashBy != "" {
+		if _, ok := ing.Annotations[UpstreamHashBySubsetAnnotation]; ok {
+			notify(notifications.WarningNotification, "upstream-hash-by-subset is not supported, requests are hashed to any endpoint", ing)
+		}
+		if hashKey := parseUpstreamHashBy(notify, ing, hashBy); hashKey != nil {
+			loadBalancer.Algorithm = emitterir.LoadBalancerConsistentHash
+			loadBalancer.HashKey = *hashKey
+			return &loadBalancer
+		}
+	}
+
+	algorithm, _ := defaults.annotation(ing, LoadBalanceAnnotation)
+	source, obj := defaults.source(ing, LoadBalanceAnnotation)
+	switch algorithm = strings.TrimSpace(algorithm); algorithm {
+	case "":
+		return nil
+	case "round_robin":
+		loadBalancer.Algorithm = emitterir.LoadBalancerRoundRobin
+	case "ewma":
+		notify(notifications.WarningNotification, fmt.Sprintf("load-balance \"ewma\" from %s is converted to least request load balancing, which doesn't weigh endpoints by their latency", source), obj)
+		loadBalancer.Algorithm = emitterir.LoadBalancerLeastRequest
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("Unsupported load-balance %q from %s, the default load balancing algorithm of the implementation is used", algorithm, source), obj)
+		return nil
+	}
+	return &loadBalancer
+}
+
+// parseUpstreamHashBy returns the hash key matching the nginx variable, or nil
+// if it can't be translated.
+func parseUpstreamHashBy(notify notifications.NotifyFunc, ing *networkingv1.Ingress, hashBy string) *emitterir.LoadBalancerHashKey {
+	match := nginxVariableRegex.FindStringSubmatch(hashBy)
+	if match == nil {
+		notify(notifications.WarningNotification, fmt.Sprintf("upstream-hash-by %q can't be translated, only a single nginx variable is supported, requests are not hashed", hashBy), ing)
+		return nil
+	}
+
+	variable := match[1]
+	switch {
+	case variable == "remote_addr" || variable == "binary_remote_addr":
+		return &emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashSourceIP}
+	case variable == "request_uri":
+		return &emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashURI}
+	case variable == "uri" || variable == "document_uri":
+		notify(notifications.WarningNotification, fmt.Sprintf("upstream-hash-by %q is converted to a hash of the request URI, which includes the query string", hashBy), ing)
+		return &emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashURI}
+	case strings.HasPrefix(variable, "http_") && len(variable) > len("http_"):
+		// nginx exposes headers with dashes replaced by underscores.
+		name := strings.ReplaceAll(strings.TrimPrefix(variable, "http_"), "_", "-")
+		return &emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashHeader, Name: name}
+	case strings.HasPrefix(variable, "cookie_") && len(variable) > len("cookie_"):
+		return &emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashCookie, Name: strings.TrimPrefix(variable, "cookie_")}
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("upstream-hash-by variable %q can't be translated, requests are not hashed", hashBy), ing)
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestLoadBalancingFeature(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedAlgorithm     emitterir.LoadBalancerAlgorithm
+		expectedHashKey       emitterir.LoadBalancerHashKey
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name:                  "no annotations",
+			annotations:           map[string]string{},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "round robin",
+			annotations:           map[string]string{LoadBalanceAnnotation: "round_robin"},
+			expectedAlgorithm:     emitterir.LoadBalancerRoundRobin,
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "ewma",
+			annotations:           map[string]string{LoadBalanceAnnotation: "ewma"},
+			expectedAlgorithm:     emitterir.LoadBalancerLeastRequest,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name:                  "unknown algorithm",
+			annotations:           map[string]string{LoadBalanceAnnotation: "least_conn"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "hash by request URI takes precedence",
+			annotations: map[string]string{
+				LoadBalanceAnnotation:    "ewma",
+				UpstreamHashByAnnotation: "$request_uri",
+			},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashURI},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "hash by URI without query",
+			annotations:           map[string]string{UpstreamHashByAnnotation: "$uri"},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashURI},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name:                  "hash by client address",
+			annotations:           map[string]string{UpstreamHashByAnnotation: "$binary_remote_addr"},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashSourceIP},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "hash by header",
+			annotations:           map[string]string{UpstreamHashByAnnotation: "$http_x_user"},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashHeader, Name: "x-user"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "hash by cookie",
+			annotations:           map[string]string{UpstreamHashByAnnotation: "$cookie_session_id"},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashCookie, Name: "session_id"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "untranslatable hash falls back to load-balance",
+			annotations: map[string]string{
+				LoadBalanceAnnotation:    "round_robin",
+				UpstreamHashByAnnotation: "$host$request_uri",
+			},
+			expectedAlgorithm:     emitterir.LoadBalancerRoundRobin,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "hash subsets",
+			annotations: map[string]string{
+				UpstreamHashByAnnotation:       "$remote_addr",
+				UpstreamHashBySubsetAnnotation: "true",
+			},
+			expectedAlgorithm:     emitterir.LoadBalancerConsistentHash,
+			expectedHashKey:       emitterir.LoadBalancerHashKey{Type: emitterir.LoadBalancerHashSourceIP},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "cookie affinity",
+			annotations: map[string]string{
+				AffinityAnnotation:       "cookie",
+				UpstreamHashByAnnotation: "$remote_addr",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.InfoNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cache",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			path := networkingv1.HTTPIngressPath{
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: "cache"},
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			ir := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+							},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing, Path: &path}},
+							{{Ingress: &ing, Path: &path}},
+						},
+					},
+				},
+				Services: map[types.NamespacedName]providerir.ProviderSpecificServiceIR{},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			if errs := (&resourcesToIRConverter{}).loadBalancingFeature(notify, []networkingv1.Ingress{ing}, nil, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			loadBalancer := ir.Services[types.NamespacedName{Namespace: "default", Name: "cache"}].LoadBalancer
+			if tc.expectedAlgorithm == "" {
+				if loadBalancer != nil {
+					t.Errorf("Expected no load balancing, got %+v", *loadBalancer)
+				}
+			} else {
+				if loadBalancer == nil {
+					t.Fatalf("Expected %s load balancing, got none", tc.expectedAlgorithm)
+				}
+				if loadBalancer.Algorithm != tc.expectedAlgorithm {
+					t.Errorf("Expected algorithm %s, got %s", tc.expectedAlgorithm, loadBalancer.Algorithm)
+				}
+				if diff := cmp.Diff(tc.expectedHashKey, loadBalancer.HashKey); diff != "" {
+					t.Errorf("Unexpected hash key (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
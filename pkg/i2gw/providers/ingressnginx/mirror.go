@@ -0,0 +1,190 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// mirrorFeature converts the mirror-target annotation to Gateway API RequestMirror filters.
+// ingress-nginx mirrors requests to a URL, while RequestMirror filters mirror them to a
+// Service, so the URL is resolved to a Service of the cluster. Requests mirrored to
+// external URLs can't be converted.
+func mirrorFeature(notify notifications.NotifyFunc, _ []networkingv1.Ingress, servicePorts map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*gatewayv1.BackendObjectReference{}
+
+	for key, httpRouteContext := range ir.HTTPRoutes {
+		for ruleIndex := range httpRouteContext.HTTPRoute.Spec.Rules {
+			if ruleIndex >= len(httpRouteContext.RuleBackendSources) {
+				continue
+			}
+			ingress := getNonCanaryIngress(httpRouteContext.RuleBackendSources[ruleIndex])
+			if ingress == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+			service, found := parsed[ingKey]
+			if !found {
+				service = parseMirrorTarget(notify, ingress, servicePorts)
+				parsed[ingKey] = service
+			}
+			if service == nil {
+				continue
+			}
+
+			backendRef := *service.DeepCopy()
+			if string(*backendRef.Namespace) == httpRouteContext.HTTPRoute.Namespace {
+				backendRef.Namespace = nil
+			} else {
+				addMirrorReferenceGrant(ir, httpRouteContext.HTTPRoute.Namespace, backendRef)
+			}
+			httpRouteContext.HTTPRoute.Spec.Rules[ruleIndex].Filters = append(httpRouteContext.HTTPRoute.Spec.Rules[ruleIndex].Filters, gatewayv1.HTTPRouteFilter{
+				Type: gatewayv1.HTTPRouteFilterRequestMirror,
+				RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+					BackendRef: backendRef,
+				},
+			})
+		}
+		ir.HTTPRoutes[key] = httpRouteContext
+	}
+	return nil
+}
+
+// parseMirrorTarget returns the Service the mirror-target annotation of the
+// Ingress points to, or nil if it has none or it can't be converted.
+func parseMirrorTarget(notify notifications.NotifyFunc, ingress *networkingv1.Ingress, servicePorts map[types.NamespacedName]map[string]int32) *gatewayv1.BackendObjectReference {
+	rawURL := strings.TrimSpace(ingress.Annotations[MirrorTargetAnnotation])
+	if rawURL == "" {
+		return nil
+	}
+
+	// The original request URI is usually appended to the target, often right
+	// after the host, which would otherwise be parsed as part of the port.
+	targetURL, err := url.Parse(strings.TrimSuffix(rawURL, "$request_uri"))
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		notify(notifications.ErrorNotification, fmt.Sprintf("Invalid mirror-target annotation %q: an absolute http or https URL is expected, skipping request mirroring", rawURL), ingress)
+		return nil
+	}
+
+	service := resolveServiceURL(targetURL, ingress.Namespace, servicePorts)
+	if service == nil {
+		notify(notifications.ErrorNotification, fmt.Sprintf("mirror-target %q is not a Service of the cluster, mirroring requests to an external URL requires an implementation-specific Backend resource, skipping request mirroring", rawURL), ingress)
+		return nil
+	}
+
+	serviceKey := types.NamespacedName{Namespace: string(*service.Namespace), Name: string(service.Name)}
+	if ports, known := servicePorts[serviceKey]; known {
+		hasPort := false
+		for _, port := range ports {
+			hasPort = hasPort || port == int32(*service.Port)
+		}
+		if !hasPort {
+			notify(notifications.WarningNotification, fmt.Sprintf("mirror-target Service %s has no port %d", serviceKey, *service.Port), ingress)
+		}
+	}
+	if targetURL.Scheme == "https" {
+		notify(notifications.WarningNotification, fmt.Sprintf("mirror-target %q is served over TLS, a BackendTLSPolicy is needed for the mirror Service %s", rawURL, serviceKey), ingress)
+	}
+	if (targetURL.Path != "" && targetURL.Path != "/") || targetURL.RawQuery != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("Mirrored requests keep their original path and query, the path and query of mirror-target %q are ignored", rawURL), ingress)
+	}
+	if strings.EqualFold(strings.TrimSpace(ingress.Annotations[MirrorRequestBodyAnnotation]), "off") {
+		notify(notifications.WarningNotification, "mirror-request-body \"off\" is not supported, the request body is mirrored", ingress)
+	}
+	if host := strings.TrimSpace(ingress.Annotations[MirrorHostAnnotation]); host != "" {
+		notify(notifications.WarningNotification, fmt.Sprintf("mirror-host %q is not supported, the Host header of mirrored requests can't be set", host), ingress)
+	}
+
+	return service
+}
+
+// resolveServiceURL returns a reference to the Service the URL points to, or
+// nil if it doesn't point to a Service of the cluster. Besides the DNS names of
+// Services, the short "<service>" and "<service>.<namespace>" names are resolved
+// against the known Services.
+func resolveServiceURL(u *url.URL, namespace string, servicePorts map[types.NamespacedName]map[string]int32) *gatewayv1.BackendObjectReference {
+	if service := serviceFromURL(u); service != nil {
+		return service
+	}
+
+	name, serviceNamespace, _ := strings.Cut(u.Hostname(), ".")
+	if serviceNamespace == "" {
+		serviceNamespace = namespace
+	}
+	if strings.Contains(serviceNamespace, ".") {
+		return nil
+	}
+	if _, known := servicePorts[types.NamespacedName{Namespace: serviceNamespace, Name: name}]; !known {
+		return nil
+	}
+
+	serviceURL := *u
+	serviceURL.Host = fmt.Sprintf("%s.%s.svc", name, serviceNamespace)
+	if u.Port() != "" {
+		serviceURL.Host += ":" + u.Port()
+	}
+	return serviceFromURL(&serviceURL)
+}
+
+// addMirrorReferenceGrant allows the HTTPRoutes of fromNamespace to mirror
+// requests to the Service of another namespace.
+func addMirrorReferenceGrant(ir *providerir.ProviderIR, fromNamespace string, service gatewayv1.BackendObjectReference) {
+	key := types.NamespacedName{
+		Namespace: string(*service.Namespace),
+		Name:      fmt.Sprintf("from-%s-httproute-to-service-%s", fromNamespace, service.Name),
+	}
+	if _, ok := ir.ReferenceGrants[key]; ok {
+		return
+	}
+	referenceGrant := gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1.GroupName,
+				Kind:      "HTTPRoute",
+				Namespace: gatewayv1.Namespace(fromNamespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Group: "",
+				Kind:  "Service",
+				Name:  ptr.To(service.Name),
+			}},
+		},
+	}
+	referenceGrant.SetGroupVersionKind(gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant"))
+	if ir.ReferenceGrants == nil {
+		ir.ReferenceGrants = make(map[types.NamespacedName]gatewayv1beta1.ReferenceGrant)
+	}
+	ir.ReferenceGrants[key] = referenceGrant
+}
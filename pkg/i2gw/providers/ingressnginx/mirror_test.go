@@ -0,0 +1,169 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestMirrorFeature(t *testing.T) {
+	servicePorts := map[types.NamespacedName]map[string]int32{
+		{Namespace: "default", Name: "mirror"}: {"http": 8080},
+		{Namespace: "other", Name: "mirror"}:   {"": 80},
+	}
+
+	testCases := []struct {
+		name                    string
+		annotations             map[string]string
+		expectedMirror          *gatewayv1.BackendObjectReference
+		expectedReferenceGrants int
+		expectedNotifications   map[notifications.MessageType]int
+	}{
+		{
+			name: "service DNS name",
+			annotations: map[string]string{
+				MirrorTargetAnnotation: "http://mirror.default.svc.cluster.local:8080$request_uri",
+			},
+			expectedMirror: &gatewayv1.BackendObjectReference{
+				Name: "mirror",
+				Port: ptr.To(gatewayv1.PortNumber(8080)),
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "short service name",
+			annotations: map[string]string{
+				MirrorTargetAnnotation: "http://mirror:8080/$request_uri",
+			},
+			expectedMirror: &gatewayv1.BackendObjectReference{
+				Name: "mirror",
+				Port: ptr.To(gatewayv1.PortNumber(8080)),
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "service of another namespace",
+			annotations: map[string]string{
+				MirrorTargetAnnotation: "http://mirror.other/$request_uri",
+			},
+			expectedMirror: &gatewayv1.BackendObjectReference{
+				Name:      "mirror",
+				Namespace: ptr.To(gatewayv1.Namespace("other")),
+				Port:      ptr.To(gatewayv1.PortNumber(80)),
+			},
+			expectedReferenceGrants: 1,
+			expectedNotifications:   map[notifications.MessageType]int{},
+		},
+		{
+			name: "unsupported options",
+			annotations: map[string]string{
+				MirrorTargetAnnotation:      "http://mirror/mirrored",
+				MirrorRequestBodyAnnotation: "off",
+				MirrorHostAnnotation:        "mirror.example.com",
+			},
+			expectedMirror: &gatewayv1.BackendObjectReference{
+				Name: "mirror",
+				Port: ptr.To(gatewayv1.PortNumber(80)),
+			},
+			// Unknown port, path, request body and host.
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 4},
+		},
+		{
+			name: "external URL",
+			annotations: map[string]string{
+				MirrorTargetAnnotation: "https://mirror.example.com$request_uri",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "invalid URL",
+			annotations: map[string]string{
+				MirrorTargetAnnotation: "/mirror",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mirror",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			ir := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+							Spec: gatewayv1.HTTPRouteSpec{
+								Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+							},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing}},
+							{{Ingress: &ing}},
+						},
+					},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			if errs := mirrorFeature(notify, []networkingv1.Ingress{ing}, servicePorts, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			for idx, rule := range ir.HTTPRoutes[key].HTTPRoute.Spec.Rules {
+				var expectedFilters []gatewayv1.HTTPRouteFilter
+				if tc.expectedMirror != nil {
+					expectedFilters = []gatewayv1.HTTPRouteFilter{{
+						Type:          gatewayv1.HTTPRouteFilterRequestMirror,
+						RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{BackendRef: *tc.expectedMirror},
+					}}
+				}
+				if diff := cmp.Diff(expectedFilters, rule.Filters); diff != "" {
+					t.Errorf("Unexpected filters for rule %d (-want +got):\n%s", idx, diff)
+				}
+			}
+			if len(ir.ReferenceGrants) != tc.expectedReferenceGrants {
+				t.Errorf("Expected %d ReferenceGrants, got %d", tc.expectedReferenceGrants, len(ir.ReferenceGrants))
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
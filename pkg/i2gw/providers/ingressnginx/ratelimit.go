@@ -0,0 +1,132 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// defaultLimitBurstMultiplier is the limit-burst-multiplier used by ingress-nginx
+// when the annotation is not set.
+const defaultLimitBurstMultiplier = 5
+
+// applyRateLimitToEmitterIR reads the ingress-nginx rate limiting annotations from ProviderIR sources
+// and stores provider-neutral rate limiting intent into EmitterIR, which will later be converted by
+// each custom emitter.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/limit-rps
+// - nginx.ingress.kubernetes.io/limit-rpm
+// - nginx.ingress.kubernetes.io/limit-connections
+// - nginx.ingress.kubernetes.io/limit-burst-multiplier
+// - nginx.ingress.kubernetes.io/limit-whitelist
+func (p *Provider) applyRateLimitToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.RateLimit{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			rateLimit, found := parsed[ingKey]
+			if !found {
+				rateLimit = p.parseRateLimit(ing)
+				parsed[ingKey] = rateLimit
+			}
+			if rateLimit == nil {
+				continue
+			}
+
+			if eRouteCtx.RateLimitByRuleIdx == nil {
+				eRouteCtx.RateLimitByRuleIdx = make(map[int]*emitterir.RateLimit)
+			}
+			eRouteCtx.RateLimitByRuleIdx[ruleIdx] = rateLimit
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseRateLimit returns the rate limiting intent of the Ingress, or nil if it
+// has no valid limit.
+func (p *Provider) parseRateLimit(ing *networkingv1.Ingress) *emitterir.RateLimit {
+	rateLimit := emitterir.RateLimit{
+		RequestsPerSecond: p.parseRateLimitValue(ing, LimitRPSAnnotation),
+		RequestsPerMinute: p.parseRateLimitValue(ing, LimitRPMAnnotation),
+		Connections:       p.parseRateLimitValue(ing, LimitConnectionsAnnotation),
+		BurstMultiplier:   p.parseRateLimitValue(ing, LimitBurstMultiplierAnnotation),
+		ClientKey:         emitterir.RateLimitClientIP,
+		AllowList:         parseIPSourceRangeAnnotation(p.storage.controllerDefaults(), ing, LimitWhitelistAnnotation),
+	}
+	if rateLimit.RequestsPerSecond == 0 && rateLimit.RequestsPerMinute == 0 && rateLimit.Connections == 0 {
+		return nil
+	}
+	if rateLimit.BurstMultiplier == 0 {
+		rateLimit.BurstMultiplier = defaultLimitBurstMultiplier
+	}
+
+	source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+	var paths []*field.Path
+	for _, annotation := range []string{LimitRPSAnnotation, LimitRPMAnnotation, LimitConnectionsAnnotation, LimitBurstMultiplierAnnotation, LimitWhitelistAnnotation} {
+		if _, ok := ing.Annotations[annotation]; ok {
+			paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+		}
+	}
+	rateLimit.Metadata = emitterir.NewExtensionFeatureMetadata(
+		source,
+		paths,
+		"Rate limiting is not supported",
+	)
+
+	return &rateLimit
+}
+
+// parseRateLimitValue returns the non-negative integer value of the annotation,
+// or 0 if it is not set or invalid. Like in ingress-nginx, 0 disables the limit.
+func (p *Provider) parseRateLimitValue(ing *networkingv1.Ingress, annotation string) int32 {
+	value, ok := ing.Annotations[annotation]
+	if !ok {
+		return 0
+	}
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil || parsed < 0 {
+		p.notify(notifications.ErrorNotification, fmt.Sprintf("Invalid %s annotation %q: a non-negative integer is expected", annotation, value), ing)
+		return 0
+	}
+	return int32(parsed)
+}
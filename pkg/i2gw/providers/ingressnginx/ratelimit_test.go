@@ -0,0 +1,139 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestApplyRateLimitToEmitterIR(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedRateLimit     *emitterir.RateLimit
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "all annotations",
+			annotations: map[string]string{
+				LimitRPSAnnotation:             "10",
+				LimitRPMAnnotation:             "300",
+				LimitConnectionsAnnotation:     "5",
+				LimitBurstMultiplierAnnotation: "3",
+				LimitWhitelistAnnotation:       "10.0.0.0/8, 192.168.0.0/16",
+			},
+			expectedRateLimit: &emitterir.RateLimit{
+				RequestsPerSecond: 10,
+				RequestsPerMinute: 300,
+				Connections:       5,
+				BurstMultiplier:   3,
+				ClientKey:         emitterir.RateLimitClientIP,
+				AllowList:         []string{"10.0.0.0/8", "192.168.0.0/16"},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "default burst multiplier",
+			annotations: map[string]string{
+				LimitRPMAnnotation: "60",
+			},
+			expectedRateLimit: &emitterir.RateLimit{
+				RequestsPerMinute: 60,
+				BurstMultiplier:   5,
+				ClientKey:         emitterir.RateLimitClientIP,
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "invalid limit",
+			annotations: map[string]string{
+				LimitRPSAnnotation: "ten",
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+		{
+			name: "whitelist without limits",
+			annotations: map[string]string{
+				LimitWhitelistAnnotation: "10.0.0.0/8",
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "rate-limit",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			route := gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{{}},
+				},
+			}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute:          route,
+						RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &ing}}},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {HTTPRoute: route},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			(&Provider{notify: notify}).applyRateLimitToEmitterIR(pIR, &eIR)
+
+			var rateLimit *emitterir.RateLimit
+			if result := eIR.HTTPRoutes[key].RateLimitByRuleIdx; result != nil {
+				rateLimit = result[0]
+			}
+			if diff := cmp.Diff(tc.expectedRateLimit, rateLimit, cmpopts.IgnoreFields(emitterir.RateLimit{}, "Metadata")); diff != "" {
+				t.Errorf("Unexpected RateLimit (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
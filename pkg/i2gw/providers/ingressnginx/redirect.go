@@ -19,6 +19,8 @@ package ingressnginx
 import (
 	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -62,16 +64,6 @@ func redirectFeature(notify notifications.NotifyFunc, ingresses []networkingv1.I
 				continue
 			}
 
-			// Warn about unsupported proxy-redirect annotations.
-			if ingress.Annotations[ProxyRedirectFromAnnotation] != "" {
-				notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s uses unsupported annotation %s",
-					ingress.Namespace, ingress.Name, ProxyRedirectFromAnnotation), ingress)
-			}
-			if ingress.Annotations[ProxyRedirectToAnnotation] != "" {
-				notify(notifications.WarningNotification, fmt.Sprintf("ingress %s/%s uses unsupported annotation %s",
-					ingress.Namespace, ingress.Name, ProxyRedirectToAnnotation), ingress)
-			}
-
 			temporalRedirectURL, hasTemporal := ingress.Annotations[TemporalRedirectAnnotation]
 			permanentRedirectURL, hasPermanent := ingress.Annotations[PermanentRedirectAnnotation]
 
@@ -135,53 +127,17 @@ func redirectFeature(notify notifications.NotifyFunc, ingresses []networkingv1.I
 			}
 
 			// Parse the redirect URL.
-			parsedURL, err := url.Parse(redirectURL)
+			redirectFilterConfig, dropped, err := parseRedirectURL(redirectURL)
 			if err != nil {
 				notify(notifications.ErrorNotification, fmt.Sprintf("Invalid redirect URL in %s annotation: %v, skipping redirect",
 					annotationUsed, err), ingress)
 				continue
 			}
-
-			// Create the redirect filter.
-			redirectFilterConfig := &gatewayv1.HTTPRequestRedirectFilter{
-				StatusCode: ptr.To(statusCode),
-			}
-
-			// Set scheme if present.
-			if parsedURL.Scheme != "" {
-				redirectFilterConfig.Scheme = ptr.To(parsedURL.Scheme)
-			}
-
-			// Set hostname if present.
-			if parsedURL.Hostname() != "" {
-				hostname := gatewayv1.PreciseHostname(parsedURL.Hostname())
-				redirectFilterConfig.Hostname = &hostname
-			}
-
-			// Set port if present.
-			if parsedURL.Port() != "" {
-				port, err := strconv.Atoi(parsedURL.Port())
-				if err == nil {
-					portNumber := gatewayv1.PortNumber(port)
-					redirectFilterConfig.Port = &portNumber
-				} else {
-					notify(notifications.ErrorNotification, fmt.Sprintf("Invalid port in redirect URL %q: %v, skipping redirect",
-						redirectURL, err), ingress)
-					continue
-				}
-			}
-
-			// Set path - default to root path if not specified in redirect URL
-			// This matches ingress-nginx behavior where redirects override the request path.
-			path := parsedURL.Path
-			if path == "" {
-				path = "/"
-			}
-			pathType := gatewayv1.FullPathHTTPPathModifier
-			redirectFilterConfig.Path = &gatewayv1.HTTPPathModifier{
-				Type:            pathType,
-				ReplaceFullPath: ptr.To(path),
+			if dropped != "" {
+				notify(notifications.WarningNotification, fmt.Sprintf("Gateway API redirects can't set a query or fragment, %q of the redirect URL in %s annotation is dropped",
+					dropped, annotationUsed), ingress)
 			}
+			redirectFilterConfig.StatusCode = ptr.To(statusCode)
 
 			redirectFilter := gatewayv1.HTTPRouteFilter{
 				Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
@@ -205,6 +161,222 @@ func redirectFeature(notify notifications.NotifyFunc, ingresses []networkingv1.I
 	return nil
 }
 
+// redirectVariableRegex matches the nginx variables of a redirect URL.
+var redirectVariableRegex = regexp.MustCompile(`\$(\{\w+\}|\w+)`)
+
+// parseRedirectURL converts the URL of an nginx redirect to a RequestRedirect filter
+// without status code. The URL may keep the scheme of the request with $scheme, its
+// host with $host or $http_host and its path with a trailing $request_uri, which are
+// the redirect defaults of Gateway API. Other variables can't be expressed.
+//
+// Like ingress-nginx, the request path is replaced by the path of the URL, which
+// defaults to the root path. The query and fragment of the URL can't be expressed,
+// so the redirect drops them and they are returned.
+func parseRedirectURL(rawURL string) (*gatewayv1.HTTPRequestRedirectFilter, string, error) {
+	target, keepPath := strings.CutSuffix(rawURL, "$request_uri")
+	if scheme, rest, found := strings.Cut(target, "://"); found {
+		host, path := rest, ""
+		if i := strings.Index(rest, "/"); i >= 0 {
+			host, path = rest[:i], rest[i:]
+		}
+		if host == "$host" || host == "$http_host" {
+			host = ""
+		}
+		if scheme == "$scheme" {
+			scheme = ""
+		}
+		target = "//" + host + path
+		if scheme != "" {
+			target = scheme + ":" + target
+		}
+	}
+	if variable := redirectVariableRegex.FindString(target); variable != "" {
+		return nil, "", fmt.Errorf("variable %s can't be expressed by a Gateway API redirect", variable)
+	}
+
+	parsedURL, err := url.Parse(target)
+	if err != nil {
+		return nil, "", err
+	}
+	if keepPath && parsedURL.Path != "" {
+		return nil, "", fmt.Errorf("a path before $request_uri in %q can't be expressed by a Gateway API redirect", rawURL)
+	}
+	var dropped string
+	if parsedURL.RawQuery != "" {
+		dropped = "?" + parsedURL.RawQuery
+	}
+	if parsedURL.Fragment != "" {
+		dropped += "#" + parsedURL.EscapedFragment()
+	}
+
+	redirect := &gatewayv1.HTTPRequestRedirectFilter{}
+	if parsedURL.Scheme != "" {
+		redirect.Scheme = ptr.To(parsedURL.Scheme)
+	}
+	if parsedURL.Hostname() != "" {
+		redirect.Hostname = ptr.To(gatewayv1.PreciseHostname(parsedURL.Hostname()))
+	}
+	if parsedURL.Port() != "" {
+		port, err := strconv.Atoi(parsedURL.Port())
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid port in redirect URL %q: %w", rawURL, err)
+		}
+		redirect.Port = ptr.To(gatewayv1.PortNumber(port))
+	}
+	if !keepPath {
+		path := parsedURL.Path
+		if path == "" {
+			path = "/"
+		}
+		redirect.Path = &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: ptr.To(path),
+		}
+	}
+	return redirect, dropped, nil
+}
+
+// addAppRootRedirects redirects the requests to the root path of the hosts to the
+// app-root path of their Ingresses with a 302, like ingress-nginx does in the location
+// of the root path. The rule is added to the HTTPRoutes before the SSL redirects, so
+// that the HTTP side redirects to HTTPS first.
+func (p *Provider) addAppRootRedirects(pir *providerir.ProviderIR, eir *emitterir.EmitterIR) {
+	for key, pRouteCtx := range pir.HTTPRoutes {
+		eRouteCtx, ok := eir.HTTPRoutes[key]
+		if !ok || len(eRouteCtx.Spec.Rules) != len(pRouteCtx.RuleBackendSources) {
+			continue
+		}
+
+		for ruleIdx, sources := range pRouteCtx.RuleBackendSources {
+			ingress := getNonCanaryIngress(sources)
+			if ingress == nil || ingress.Annotations[AppRootAnnotation] == "" || !isRootRule(eRouteCtx.Spec.Rules[ruleIdx]) {
+				continue
+			}
+
+			appRoot := ingress.Annotations[AppRootAnnotation]
+			if !strings.HasPrefix(appRoot, "/") || appRoot == "/" || strings.ContainsAny(appRoot, "$?#") {
+				p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid app-root annotation %q, it must be a path other than /", appRoot), ingress)
+				break
+			}
+			if slices.ContainsFunc(eRouteCtx.Spec.Rules, isExactRootRule) {
+				p.notify(notifications.WarningNotification,
+					fmt.Sprintf("HTTPRoute %s already has a rule for the exact root path, app-root %s is not converted", key, appRoot), ingress)
+				break
+			}
+
+			eRouteCtx.Spec.Rules = append(eRouteCtx.Spec.Rules, gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{{
+					Path: &gatewayv1.HTTPPathMatch{
+						Type:  ptr.To(gatewayv1.PathMatchExact),
+						Value: ptr.To("/"),
+					},
+				}},
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						StatusCode: ptr.To(302),
+						Path: &gatewayv1.HTTPPathModifier{
+							Type:            gatewayv1.FullPathHTTPPathModifier,
+							ReplaceFullPath: ptr.To(appRoot),
+						},
+					},
+				}},
+			})
+			pRouteCtx.RuleBackendSources = append(pRouteCtx.RuleBackendSources, sources)
+			eir.HTTPRoutes[key] = eRouteCtx
+			pir.HTTPRoutes[key] = pRouteCtx
+			break
+		}
+	}
+}
+
+// isExactRootRule returns whether the rule matches the exact root path.
+func isExactRootRule(rule gatewayv1.HTTPRouteRule) bool {
+	return slices.ContainsFunc(rule.Matches, func(match gatewayv1.HTTPRouteMatch) bool {
+		return match.Path != nil && ptr.Deref(match.Path.Type, gatewayv1.PathMatchPathPrefix) == gatewayv1.PathMatchExact && ptr.Deref(match.Path.Value, "") == "/"
+	})
+}
+
+// applyProxyRedirectToEmitterIR reads the proxy-redirect-from and proxy-redirect-to
+// annotations from ProviderIR sources and stores Location header rewrite intent into
+// EmitterIR, which will later be converted by each custom emitter.
+func (p *Provider) applyProxyRedirectToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.LocationRewrite{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			locationRewrite, found := parsed[ingKey]
+			if !found {
+				locationRewrite = p.parseProxyRedirect(ing)
+				parsed[ingKey] = locationRewrite
+			}
+			if locationRewrite == nil {
+				continue
+			}
+
+			if eRouteCtx.LocationRewriteByRuleIdx == nil {
+				eRouteCtx.LocationRewriteByRuleIdx = make(map[int]*emitterir.LocationRewrite)
+			}
+			eRouteCtx.LocationRewriteByRuleIdx[ruleIdx] = locationRewrite
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseProxyRedirect returns the Location header rewrite intent of the Ingress, or nil
+// if it doesn't rewrite the Location headers. Like ingress-nginx, "off" disables the
+// rewrite, and "default" only rewrites the URL of the upstream of the controller,
+// which the backends don't send.
+func (p *Provider) parseProxyRedirect(ing *networkingv1.Ingress) *emitterir.LocationRewrite {
+	from := ing.Annotations[ProxyRedirectFromAnnotation]
+	to := ing.Annotations[ProxyRedirectToAnnotation]
+	if from == "" || from == "off" || from == "default" || to == "off" {
+		if from == "" && to != "" {
+			p.notify(notifications.WarningNotification, "proxy-redirect-to annotation requires proxy-redirect-from annotation, it is ignored", ing)
+		}
+		return nil
+	}
+	if to == "" {
+		p.notify(notifications.WarningNotification, "proxy-redirect-from annotation requires proxy-redirect-to annotation, it is ignored", ing)
+		return nil
+	}
+	if strings.HasPrefix(from, "~") || strings.Contains(from, "$") || strings.Contains(to, "$") {
+		p.notify(notifications.ErrorNotification,
+			fmt.Sprintf("Regular expressions and variables of proxy-redirect-from %q and proxy-redirect-to %q can't be converted, the Location headers are not rewritten", from, to), ing)
+		return nil
+	}
+
+	var paths []*field.Path
+	for _, annotation := range []string{ProxyRedirectFromAnnotation, ProxyRedirectToAnnotation} {
+		paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+	}
+	return &emitterir.LocationRewrite{
+		Metadata: emitterir.NewExtensionFeatureMetadata(
+			fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+			paths,
+			"Location header rewriting is not supported, the Location headers of the backends are returned unchanged",
+		),
+		From: from,
+		To:   to,
+	}
+}
+
 // addSSLAndTrailingSlashRedirects adds HTTP→HTTPS redirect routes and trailing slash
 // redirect rules to match ingress-nginx behavior.
 //
@@ -277,7 +449,7 @@ func (p *Provider) addSSLAndTrailingSlashRedirects(ingresses []networkingv1.Ingr
 			}
 
 			enableRedirect := true
-			if val, ok := ingress.Annotations[SSLRedirectAnnotation]; ok {
+			if val, ok := p.storage.controllerDefaults().annotation(ingress, SSLRedirectAnnotation); ok {
 				enableRedirect, _ = strconv.ParseBool(val)
 			}
 
@@ -344,7 +516,7 @@ func (p *Provider) addSSLAndTrailingSlashRedirects(ingresses []networkingv1.Ingr
 			eRouteCtx.Spec.ParentRefs[i].Port = ptr.To[int32](443)
 			if len(eRouteCtx.Spec.Hostnames) > 0 {
 				hostname := string(eRouteCtx.Spec.Hostnames[0])
-				sectionName := gatewayv1.SectionName(fmt.Sprintf("%s-https", common.NameFromHost(hostname)))
+				sectionName := httpsListenerName(eir, eRouteCtx.Namespace, eRouteCtx.Spec.ParentRefs[i], hostname)
 				eRouteCtx.Spec.ParentRefs[i].SectionName = &sectionName
 			}
 		}
@@ -352,6 +524,36 @@ func (p *Provider) addSSLAndTrailingSlashRedirects(ingresses []networkingv1.Ingr
 	}
 }
 
+// httpsListenerName returns the name of the HTTPS listener serving the hostname
+// on the parent Gateway. Per-host listeners may have been replaced by a
+// wildcard listener, which is used when there's no listener for the hostname
+// itself.
+func httpsListenerName(eir *emitterir.EmitterIR, namespace string, parentRef gatewayv1.ParentReference, hostname string) gatewayv1.SectionName {
+	gwKey := types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}
+	if parentRef.Namespace != nil {
+		gwKey.Namespace = string(*parentRef.Namespace)
+	}
+
+	var wildcardListener *gatewayv1.SectionName
+	if _, parent, found := strings.Cut(hostname, "."); found {
+		for _, l := range eir.Gateways[gwKey].Spec.Listeners {
+			if l.Port != 443 || l.Hostname == nil {
+				continue
+			}
+			if string(*l.Hostname) == hostname {
+				return l.Name
+			}
+			if string(*l.Hostname) == "*."+parent && wildcardListener == nil {
+				wildcardListener = ptr.To(l.Name)
+			}
+		}
+	}
+	if wildcardListener != nil {
+		return *wildcardListener
+	}
+	return gatewayv1.SectionName(fmt.Sprintf("%s-https", common.NameFromHost(hostname)))
+}
+
 // isValidTemporalRedirectCode returns true if the code is in the intersection of
 // ingress-nginx temporal-redirect codes (300-307) and Gateway API codes (301,302,303,307,308).
 // Result: 301, 302, 303, 307.
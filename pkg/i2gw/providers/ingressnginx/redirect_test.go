@@ -19,6 +19,8 @@ package ingressnginx
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
@@ -28,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -1656,3 +1659,285 @@ func TestAddWWWRedirect_https_listener(t *testing.T) {
 		t.Fatalf("expected fourth listener to be HTTPS for www.example.com")
 	}
 }
+
+func TestParseRedirectURL(t *testing.T) {
+	fullPath := func(path string) *gatewayv1.HTTPPathModifier {
+		return &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To(path)}
+	}
+
+	testCases := []struct {
+		name            string
+		url             string
+		expected        *gatewayv1.HTTPRequestRedirectFilter
+		expectedDropped string
+		expectError     bool
+	}{
+		{
+			name: "fixed URL",
+			url:  "https://example.com:8443/new",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:   ptr.To("https"),
+				Hostname: ptr.To(gatewayv1.PreciseHostname("example.com")),
+				Port:     ptr.To(gatewayv1.PortNumber(8443)),
+				Path:     fullPath("/new"),
+			},
+		},
+		{
+			name: "host without path",
+			url:  "https://example.com",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:   ptr.To("https"),
+				Hostname: ptr.To(gatewayv1.PreciseHostname("example.com")),
+				Path:     fullPath("/"),
+			},
+		},
+		{
+			name: "request host and path",
+			url:  "https://$host$request_uri",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme: ptr.To("https"),
+			},
+		},
+		{
+			name: "request scheme and path",
+			url:  "$scheme://new.example.com$request_uri",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Hostname: ptr.To(gatewayv1.PreciseHostname("new.example.com")),
+			},
+		},
+		{
+			name: "request http host with fixed path",
+			url:  "https://$http_host/maintenance",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme: ptr.To("https"),
+				Path:   fullPath("/maintenance"),
+			},
+		},
+		{
+			name:        "other variable",
+			url:         "https://$hostname/",
+			expectError: true,
+		},
+		{
+			name:        "variable in path",
+			url:         "https://example.com/$1",
+			expectError: true,
+		},
+		{
+			name:        "path before request uri",
+			url:         "https://example.com/new$request_uri",
+			expectError: true,
+		},
+		{
+			name: "query is dropped",
+			url:  "https://example.com/new?from=old",
+			expected: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:   ptr.To("https"),
+				Hostname: ptr.To(gatewayv1.PreciseHostname("example.com")),
+				Path:     fullPath("/new"),
+			},
+			expectedDropped: "?from=old",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redirect, dropped, err := parseRedirectURL(tc.url)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected an error, got redirect %+v", redirect)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, redirect); diff != "" {
+				t.Errorf("Unexpected redirect (-want +got):\n%s", diff)
+			}
+			if dropped != tc.expectedDropped {
+				t.Errorf("Expected %q to be dropped, got %q", tc.expectedDropped, dropped)
+			}
+		})
+	}
+}
+
+func TestAddAppRootRedirects(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "route"}
+	pathMatch := func(pathType gatewayv1.PathMatchType, value string) []gatewayv1.HTTPRouteMatch {
+		return []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Type: ptr.To(pathType), Value: ptr.To(value)}}}
+	}
+	appRootRule := gatewayv1.HTTPRouteRule{
+		Matches: pathMatch(gatewayv1.PathMatchExact, "/"),
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				StatusCode: ptr.To(302),
+				Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/app")},
+			},
+		}},
+	}
+
+	testCases := []struct {
+		name                  string
+		appRoot               string
+		rules                 []gatewayv1.HTTPRouteRule
+		expectedRules         []gatewayv1.HTTPRouteRule
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name:                  "root path",
+			appRoot:               "/app",
+			rules:                 []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")}},
+			expectedRules:         []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")}, appRootRule},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "no root path",
+			appRoot:               "/app",
+			rules:                 []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}},
+			expectedRules:         []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/api")}},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:    "exact root path already routed",
+			appRoot: "/app",
+			rules: []gatewayv1.HTTPRouteRule{
+				{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")},
+				{Matches: pathMatch(gatewayv1.PathMatchExact, "/")},
+			},
+			expectedRules: []gatewayv1.HTTPRouteRule{
+				{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")},
+				{Matches: pathMatch(gatewayv1.PathMatchExact, "/")},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name:                  "invalid app root",
+			appRoot:               "https://example.com/app",
+			rules:                 []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")}},
+			expectedRules:         []gatewayv1.HTTPRouteRule{{Matches: pathMatch(gatewayv1.PathMatchPathPrefix, "/")}},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{AppRootAnnotation: tc.appRoot}},
+			}
+			sources := make([][]providerir.BackendSource, len(tc.rules))
+			for i := range sources {
+				sources[i] = []providerir.BackendSource{{Ingress: &ing, Path: &networkingv1.HTTPIngressPath{}}}
+			}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					routeKey: {RuleBackendSources: sources},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					routeKey: {HTTPRoute: gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Rules: tc.rules}}},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify}
+			p.addAppRootRedirects(&pIR, &eIR)
+
+			if diff := cmp.Diff(tc.expectedRules, eIR.HTTPRoutes[routeKey].Spec.Rules); diff != "" {
+				t.Errorf("Unexpected rules (-want +got):\n%s", diff)
+			}
+			if len(pIR.HTTPRoutes[routeKey].RuleBackendSources) != len(tc.expectedRules) {
+				t.Errorf("Expected %d rule backend sources, got %d", len(tc.expectedRules), len(pIR.HTTPRoutes[routeKey].RuleBackendSources))
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyProxyRedirectToEmitterIR(t *testing.T) {
+	routeKey := types.NamespacedName{Namespace: "default", Name: "route"}
+
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expected              *emitterir.LocationRewrite
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "from and to",
+			annotations: map[string]string{
+				ProxyRedirectFromAnnotation: "http://backend.local/",
+				ProxyRedirectToAnnotation:   "https://example.com/app/",
+			},
+			expected:              &emitterir.LocationRewrite{From: "http://backend.local/", To: "https://example.com/app/"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "off",
+			annotations:           map[string]string{ProxyRedirectFromAnnotation: "off", ProxyRedirectToAnnotation: "/"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "default",
+			annotations:           map[string]string{ProxyRedirectFromAnnotation: "default"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:                  "to without from",
+			annotations:           map[string]string{ProxyRedirectToAnnotation: "/"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name:                  "variable",
+			annotations:           map[string]string{ProxyRedirectFromAnnotation: "http://backend.local/", ProxyRedirectToAnnotation: "https://$host/"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.ErrorNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: tc.annotations}}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					routeKey: {
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing, Path: &networkingv1.HTTPIngressPath{}}},
+							{{Ingress: &ing, Path: &networkingv1.HTTPIngressPath{}}},
+						},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					routeKey: {
+						HTTPRoute: gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}}}},
+					},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify}
+			p.applyProxyRedirectToEmitterIR(pIR, &eIR)
+
+			locationRewrites := eIR.HTTPRoutes[routeKey].LocationRewriteByRuleIdx
+			for ruleIdx := range 2 {
+				if diff := cmp.Diff(tc.expected, locationRewrites[ruleIdx], cmpopts.IgnoreFields(emitterir.LocationRewrite{}, "Metadata")); diff != "" {
+					t.Errorf("Unexpected Location rewrite of rule %d (-want +got):\n%s", ruleIdx, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
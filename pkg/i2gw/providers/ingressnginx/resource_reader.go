@@ -18,31 +18,46 @@ package ingressnginx
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // converter implements the i2gw.CustomResourceReader interface.
 type resourceReader struct {
 	conf         *i2gw.ProviderConf
 	ingressClass string
+	// tcpServicesConfigMap and udpServicesConfigMap are the namespace/name of the
+	// ConfigMaps exposing TCP and UDP services, empty when they are not configured.
+	tcpServicesConfigMap string
+	udpServicesConfigMap string
+	// controllerConfigMap is the namespace/name of the ConfigMap configuring the
+	// controller, empty when it is not configured.
+	controllerConfigMap string
 }
 
 // newResourceReader returns a resourceReader instance.
 func newResourceReader(conf *i2gw.ProviderConf) *resourceReader {
-	var ingressClass string
+	reader := &resourceReader{conf: conf}
 
 	if ps := conf.ProviderSpecificFlags[Name]; ps != nil {
-		ingressClass = ps[NginxIngressClassFlag]
+		reader.ingressClass = ps[NginxIngressClassFlag]
+		reader.tcpServicesConfigMap = ps[TCPServicesConfigMapFlag]
+		reader.udpServicesConfigMap = ps[UDPServicesConfigMapFlag]
+		reader.controllerConfigMap = ps[ControllerConfigMapFlag]
 	}
 
-	return &resourceReader{
-		conf:         conf,
-		ingressClass: ingressClass,
-	}
+	return reader
 }
 
 func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage, error) {
@@ -59,6 +74,51 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 		return nil, err
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
+	storage.Services = services
+
+	notify := r.conf.Report.Notifier(Name)
+	secrets, err := common.ReadTLSSecretsFromCluster(ctx, r.conf.Client, notify)
+	if err != nil {
+		return nil, err
+	}
+	// The Secrets and ConfigMaps referenced by the annotations may live in other
+	// namespaces, so they are read one by one with the client of every namespace.
+	referenced, err := common.ReadSecretsFromCluster(ctx, r.clusterClient(), notify, sortedKeys(referencedSecrets(storage.Ingresses.List())))
+	if err != nil {
+		return nil, err
+	}
+	if secrets == nil {
+		secrets = map[types.NamespacedName]*apiv1.Secret{}
+	}
+	maps.Copy(secrets, referenced)
+	storage.Secrets = secrets
+
+	configMaps, err := common.ReadConfigMapsFromCluster(ctx, r.clusterClient(), notify, sortedKeys(referencedConfigMaps(storage.Ingresses.List())))
+	if err != nil {
+		return nil, err
+	}
+	storage.ConfigMaps = configMaps
+
+	// The ConfigMaps named by the flags usually live in the namespace of the
+	// controller, which the client scoped to the converted namespace can't read.
+	storage.TCPServices, err = readConfigMapFlag(TCPServicesConfigMapFlag, r.tcpServicesConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromCluster(ctx, r.clusterClient(), key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage.UDPServices, err = readConfigMapFlag(UDPServicesConfigMapFlag, r.udpServicesConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromCluster(ctx, r.clusterClient(), key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage.ControllerConfig, err = readConfigMapFlag(ControllerConfigMapFlag, r.controllerConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromCluster(ctx, r.clusterClient(), key)
+	})
+	if err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
 
@@ -76,5 +136,113 @@ func (r *resourceReader) readResourcesFromFile(reader io.Reader) (*storage, erro
 		return nil, err
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
+	storage.Services = services
+
+	secrets, err := common.ReadSecretsFromFile(reader, "", secretFilter(r.conf.Namespace, storage.Ingresses.List()))
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
+
+	configMaps, err := common.ReadConfigMapsFromFile(reader, configMapFilter(storage.Ingresses.List()))
+	if err != nil {
+		return nil, err
+	}
+	storage.ConfigMaps = configMaps
+
+	storage.TCPServices, err = readConfigMapFlag(TCPServicesConfigMapFlag, r.tcpServicesConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromFile(reader, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage.UDPServices, err = readConfigMapFlag(UDPServicesConfigMapFlag, r.udpServicesConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromFile(reader, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage.ControllerConfig, err = readConfigMapFlag(ControllerConfigMapFlag, r.controllerConfigMap, func(key types.NamespacedName) (*apiv1.ConfigMap, error) {
+		return common.ReadConfigMapFromFile(reader, key)
+	})
+	if err != nil {
+		return nil, err
+	}
 	return storage, nil
 }
+
+// sortedKeys returns the keys sorted by namespace and name, so that the resources
+// are read in the same order on every run.
+func sortedKeys(keys sets.Set[types.NamespacedName]) []types.NamespacedName {
+	sorted := keys.UnsortedList()
+	slices.SortFunc(sorted, func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return sorted
+}
+
+// clusterClient returns the client reading the resources of every namespace.
+func (r *resourceReader) clusterClient() client.Client {
+	if r.conf.ClusterClient != nil {
+		return r.conf.ClusterClient
+	}
+	return r.conf.Client
+}
+
+// readConfigMapFlag reads the ConfigMap named by the namespace/name value of a
+// provider-specific flag. It returns nil when the flag is not set.
+func readConfigMapFlag(flag, value string, read func(types.NamespacedName) (*apiv1.ConfigMap, error)) (*apiv1.ConfigMap, error) {
+	if value == "" {
+		return nil, nil
+	}
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid --%s-%s value %q, expected namespace/name", Name, flag, value)
+	}
+	return read(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// referencedSecrets returns the Secrets referenced by the annotations of the Ingresses.
+func referencedSecrets(ingresses []networkingv1.Ingress) sets.Set[types.NamespacedName] {
+	referenced := sets.New[types.NamespacedName]()
+	for _, ing := range ingresses {
+		for _, annotation := range []string{AuthSecretAnnotation, AuthTLSSecretAnnotation} {
+			if secret, ok := annotationObjectRef(&ing, annotation); ok {
+				referenced.Insert(secret)
+			}
+		}
+	}
+	return referenced
+}
+
+// secretFilter accepts the TLS Secrets of the namespace, or of every namespace
+// when it is empty, and the Secrets referenced by the annotations of the Ingresses
+// from any namespace.
+func secretFilter(namespace string, ingresses []networkingv1.Ingress) func(*apiv1.Secret) bool {
+	referenced := referencedSecrets(ingresses)
+	return func(secret *apiv1.Secret) bool {
+		if referenced.Has(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}) {
+			return true
+		}
+		return common.IsTLSSecret(secret) && (namespace == "" || secret.Namespace == namespace)
+	}
+}
+
+// referencedConfigMaps returns the ConfigMaps referenced by the annotations of the Ingresses.
+func referencedConfigMaps(ingresses []networkingv1.Ingress) sets.Set[types.NamespacedName] {
+	referenced := sets.New[types.NamespacedName]()
+	for _, ing := range ingresses {
+		if configMap, ok := annotationObjectRef(&ing, CustomHeadersAnnotation); ok {
+			referenced.Insert(configMap)
+		}
+	}
+	return referenced
+}
+
+// configMapFilter accepts the ConfigMaps referenced by the annotations of the Ingresses.
+func configMapFilter(ingresses []networkingv1.Ingress) func(*apiv1.ConfigMap) bool {
+	referenced := referencedConfigMaps(ingresses)
+	return func(configMap *apiv1.ConfigMap) bool {
+		return referenced.Has(types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name})
+	}
+}
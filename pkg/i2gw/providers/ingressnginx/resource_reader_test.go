@@ -17,11 +17,22 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"context"
+	"maps"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
 	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var ingressText = `
@@ -86,3 +97,203 @@ func TestResourceReader_FiltersByIngressClass_FromFile(t *testing.T) {
 	assert.Len(t, ingresses, 1, "Expected exactly one ingress to be selected")
 	assert.Equal(t, IngressClass, *ingresses[0].Spec.IngressClassName, "Ingresses fetched should have the provided ingressClass")
 }
+
+var streamServicesText = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tcp-services
+  namespace: ingress-nginx
+data:
+  "9000": "default/db:5432"
+`
+
+// Test that the ConfigMaps named by the tcp-services-configmap and
+// udp-services-configmap flags are read from the input file.
+func TestResourceReader_ReadsStreamServices_FromFile(t *testing.T) {
+	conf := &i2gw.ProviderConf{
+		Namespace: "default",
+		ProviderSpecificFlags: map[string]map[string]string{
+			Name: {
+				NginxIngressClassFlag:    IngressClass,
+				TCPServicesConfigMapFlag: "ingress-nginx/tcp-services",
+			},
+		},
+	}
+
+	storage, err := newResourceReader(conf).readResourcesFromFile(strings.NewReader(streamServicesText))
+	if err != nil {
+		t.Fatalf("readResourcesFromFile() error = %v", err)
+	}
+	assert.Equal(t, map[string]string{"9000": "default/db:5432"}, storage.TCPServices.Data)
+	assert.Nil(t, storage.UDPServices)
+
+	conf.ProviderSpecificFlags[Name][UDPServicesConfigMapFlag] = "ingress-nginx/udp-services"
+	_, err = newResourceReader(conf).readResourcesFromFile(strings.NewReader(streamServicesText))
+	assert.Error(t, err, "Expected an error for a missing ConfigMap")
+
+	conf.ProviderSpecificFlags[Name][UDPServicesConfigMapFlag] = "udp-services"
+	_, err = newResourceReader(conf).readResourcesFromFile(strings.NewReader(streamServicesText))
+	assert.Error(t, err, "Expected an error for a ConfigMap without namespace")
+}
+
+var customHeadersText = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: app
+  namespace: default
+  annotations:
+    nginx.ingress.kubernetes.io/custom-headers: shared/custom-headers
+    nginx.ingress.kubernetes.io/auth-secret: auth/basic-auth
+spec:
+  ingressClassName: nginx
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: test
+            port:
+              number: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: custom-headers
+  namespace: shared
+data:
+  X-Served-By: ingress
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unreferenced
+  namespace: default
+data:
+  X-Served-By: ingress
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: basic-auth
+  namespace: auth
+type: Opaque
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+  namespace: other
+type: kubernetes.io/tls
+`
+
+// Test that the ConfigMaps and Secrets referenced by the annotations are read from
+// the input file, even from another namespace than the converted one.
+func TestResourceReader_ReadsReferencedConfigMaps_FromFile(t *testing.T) {
+	conf := &i2gw.ProviderConf{
+		Namespace: "default",
+		ProviderSpecificFlags: map[string]map[string]string{
+			Name: {
+				NginxIngressClassFlag: IngressClass,
+			},
+		},
+	}
+
+	storage, err := newResourceReader(conf).readResourcesFromFile(strings.NewReader(customHeadersText))
+	if err != nil {
+		t.Fatalf("readResourcesFromFile() error = %v", err)
+	}
+	assert.Len(t, storage.ConfigMaps, 1, "Expected only the referenced ConfigMap to be read")
+	assert.Contains(t, storage.ConfigMaps, types.NamespacedName{Namespace: "shared", Name: "custom-headers"})
+	assert.Equal(t, []types.NamespacedName{{Namespace: "auth", Name: "basic-auth"}}, slices.Collect(maps.Keys(storage.Secrets)),
+		"Expected only the referenced Secret to be read from another namespace")
+}
+
+// newFakeClients returns a fake client of the objects, and the client scoped to
+// the default namespace which reads the converted resources.
+func newFakeClients(objects ...client.Object) (client.Client, client.Client) {
+	cl := fake.NewClientBuilder().
+		WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme)).
+		WithObjects(objects...).
+		WithIndex(&apiv1.Secret{}, "type", func(obj client.Object) []string {
+			return []string{string(obj.(*apiv1.Secret).Type)}
+		}).
+		Build()
+	return client.NewNamespacedClient(cl, "default"), cl
+}
+
+// Test that the ConfigMaps named by the tcp-services-configmap,
+// udp-services-configmap and controller-configmap flags are read from the
+// namespace of the controller when the resources are read from a namespace of
+// the cluster.
+func TestResourceReader_ReadsFlagConfigMaps_FromCluster(t *testing.T) {
+	namespacedClient, clusterClient := newFakeClients(
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "tcp-services"}, Data: map[string]string{"9000": "default/db:5432"}},
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "udp-services"}, Data: map[string]string{"53": "default/dns:53"}},
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"}, Data: map[string]string{"proxy-body-size": "8m"}},
+	)
+	conf := &i2gw.ProviderConf{
+		Client:        namespacedClient,
+		ClusterClient: clusterClient,
+		Namespace:     "default",
+		ProviderSpecificFlags: map[string]map[string]string{
+			Name: {
+				NginxIngressClassFlag:    IngressClass,
+				TCPServicesConfigMapFlag: "ingress-nginx/tcp-services",
+				UDPServicesConfigMapFlag: "ingress-nginx/udp-services",
+				ControllerConfigMapFlag:  "ingress-nginx/ingress-nginx-controller",
+			},
+		},
+	}
+
+	storage, err := newResourceReader(conf).readResourcesFromCluster(context.Background())
+	if err != nil {
+		t.Fatalf("readResourcesFromCluster() error = %v", err)
+	}
+	assert.Equal(t, map[string]string{"9000": "default/db:5432"}, storage.TCPServices.Data)
+	assert.Equal(t, map[string]string{"53": "default/dns:53"}, storage.UDPServices.Data)
+	assert.Equal(t, map[string]string{"proxy-body-size": "8m"}, storage.ControllerConfig.Data)
+}
+
+// Test that the ConfigMaps and Secrets referenced by the annotations are read from
+// other namespaces than the converted one, like from an input file.
+func TestResourceReader_ReadsReferencedResources_FromCluster(t *testing.T) {
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "app",
+			Annotations: map[string]string{
+				CustomHeadersAnnotation: "shared/custom-headers",
+				AuthSecretAnnotation:    "auth/basic-auth",
+			},
+		},
+		Spec: networkingv1.IngressSpec{IngressClassName: &IngressClass},
+	}
+	namespacedClient, clusterClient := newFakeClients(
+		ing,
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shared", Name: "custom-headers"}, Data: map[string]string{"X-Served-By": "ingress"}},
+		&apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unreferenced"}},
+		&apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "auth", Name: "basic-auth"}, Type: apiv1.SecretTypeOpaque},
+		&apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unreferenced"}, Type: apiv1.SecretTypeOpaque},
+	)
+	conf := &i2gw.ProviderConf{
+		Client:        namespacedClient,
+		ClusterClient: clusterClient,
+		Namespace:     "default",
+		ProviderSpecificFlags: map[string]map[string]string{
+			Name: {
+				NginxIngressClassFlag: IngressClass,
+			},
+		},
+	}
+
+	storage, err := newResourceReader(conf).readResourcesFromCluster(context.Background())
+	if err != nil {
+		t.Fatalf("readResourcesFromCluster() error = %v", err)
+	}
+	assert.Equal(t, []types.NamespacedName{{Namespace: "shared", Name: "custom-headers"}}, slices.Collect(maps.Keys(storage.ConfigMaps)))
+	assert.Equal(t, []types.NamespacedName{{Namespace: "auth", Name: "basic-auth"}}, slices.Collect(maps.Keys(storage.Secrets)))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// defaultProxyNextUpstream is the default of proxy-next-upstream.
+	defaultProxyNextUpstream = "error timeout"
+	// defaultProxyNextUpstreamTries is the default of proxy-next-upstream-tries,
+	// which counts the initial request.
+	defaultProxyNextUpstreamTries = 3
+)
+
+// proxyNextUpstreamStatusCodes maps the proxy-next-upstream conditions
+// retrying responses to their status code.
+var proxyNextUpstreamStatusCodes = map[string]int32{
+	"http_500": 500,
+	"http_502": 502,
+	"http_503": 503,
+	"http_504": 504,
+	"http_403": 403,
+	"http_404": 404,
+	"http_429": 429,
+}
+
+// applyRetryToEmitterIR reads the ingress-nginx retry annotations from ProviderIR sources and
+// stores provider-neutral retry intent into EmitterIR, which will later be converted by the
+// common emitter or by each custom emitter.
+//
+// ingress-nginx always passes failed requests to the next upstream, so retry intent is only
+// generated for Ingresses which configure it explicitly.
+//
+// Currently supported annotations are:
+// - nginx.ingress.kubernetes.io/proxy-next-upstream
+// - nginx.ingress.kubernetes.io/proxy-next-upstream-tries
+// - nginx.ingress.kubernetes.io/proxy-next-upstream-timeout
+func (p *Provider) applyRetryToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.Retry{}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			retry, found := parsed[ingKey]
+			if !found {
+				retry = p.parseRetry(ing)
+				parsed[ingKey] = retry
+			}
+			if retry == nil {
+				continue
+			}
+
+			if eRouteCtx.RetryByRuleIdx == nil {
+				eRouteCtx.RetryByRuleIdx = make(map[int]*emitterir.Retry)
+			}
+			eRouteCtx.RetryByRuleIdx[ruleIdx] = retry
+		}
+
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// parseRetry returns the retry intent of the Ingress, or nil if it doesn't
+// configure retries or turns them off.
+func (p *Provider) parseRetry(ing *networkingv1.Ingress) *emitterir.Retry {
+	defaults := p.storage.controllerDefaults()
+	var paths []*field.Path
+	for _, annotation := range []string{ProxyNextUpstreamAnnotation, ProxyNextUpstreamTriesAnnotation, ProxyNextUpstreamTimeoutAnnotation} {
+		if _, ok := defaults.annotation(ing, annotation); ok {
+			paths = append(paths, defaults.path(ing, annotation))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	retry := emitterir.Retry{
+		Metadata: emitterir.NewExtensionFeatureMetadata(
+			fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+			paths,
+			"Retries are not supported, failed requests are not retried",
+		),
+	}
+
+	rawConditions, _ := defaults.annotation(ing, ProxyNextUpstreamAnnotation)
+	conditions := strings.TrimSpace(rawConditions)
+	if conditions == "" {
+		conditions = defaultProxyNextUpstream
+	}
+	var ignored []string
+	nonIdempotent := false
+	for _, condition := range strings.Fields(conditions) {
+		switch condition {
+		case "off":
+			return nil
+		case "error":
+			retry.Conditions = append(retry.Conditions, emitterir.RetryOnConnectFailure)
+		case "timeout":
+			retry.Conditions = append(retry.Conditions, emitterir.RetryOnTimeout)
+		case "non_idempotent":
+			// Gateways retry requests regardless of their method.
+			nonIdempotent = true
+		default:
+			if code, isStatusCode := proxyNextUpstreamStatusCodes[condition]; isStatusCode {
+				retry.StatusCodes = append(retry.StatusCodes, code)
+			} else {
+				ignored = append(ignored, condition)
+			}
+		}
+	}
+	if len(ignored) > 0 {
+		source, obj := defaults.source(ing, ProxyNextUpstreamAnnotation)
+		p.notify(notifications.WarningNotification, fmt.Sprintf("proxy-next-upstream conditions %s from %s are not supported and are ignored", strings.Join(ignored, ", "), source), obj)
+	}
+	if len(retry.Conditions) == 0 && len(retry.StatusCodes) == 0 {
+		return nil
+	}
+
+	tries := int64(defaultProxyNextUpstreamTries)
+	rawTries, _ := defaults.annotation(ing, ProxyNextUpstreamTriesAnnotation)
+	if rawTries = strings.TrimSpace(rawTries); rawTries != "" {
+		parsedTries, err := strconv.ParseInt(rawTries, 10, 32)
+		if err != nil || parsedTries < 0 {
+			source, obj := defaults.source(ing, ProxyNextUpstreamTriesAnnotation)
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid proxy-next-upstream-tries %q from %s, the default of %d tries is used", rawTries, source, defaultProxyNextUpstreamTries), obj)
+		} else {
+			tries = parsedTries
+		}
+	}
+	switch tries {
+	case 0:
+		source, obj := defaults.source(ing, ProxyNextUpstreamTriesAnnotation)
+		p.notify(notifications.WarningNotification, fmt.Sprintf("proxy-next-upstream-tries \"0\" from %s doesn't limit the retries, the default number of retries of the implementation is used", source), obj)
+	case 1:
+		// The initial request is the only try.
+		return nil
+	default:
+		retry.Attempts = ptr.To(int32(tries - 1))
+	}
+
+	rawTimeout, _ := defaults.annotation(ing, ProxyNextUpstreamTimeoutAnnotation)
+	if rawTimeout = strings.TrimSpace(rawTimeout); rawTimeout != "" && rawTimeout != "0" {
+		timeout, err := parseIngressNginxTimeout(rawTimeout)
+		if err != nil {
+			source, obj := defaults.source(ing, ProxyNextUpstreamTimeoutAnnotation)
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid proxy-next-upstream-timeout %q from %s: %v, retries are not limited in time", rawTimeout, source, err), obj)
+		} else {
+			// The timeout limits the time of all the tries in ingress-nginx. The
+			// implementations can't limit it, so each try is limited to it instead.
+			retry.Timeout = ptr.To(gatewayv1.Duration(timeout.String()))
+			retry.PerTryTimeout = retry.Timeout
+		}
+	}
+
+	if !nonIdempotent {
+		p.notify(notifications.WarningNotification, fmt.Sprintf("ingress-nginx doesn't retry non-idempotent requests, such as POST requests, unless proxy-next-upstream lists non_idempotent, the retries of ingress %s/%s are converted for every request method",
+			ing.Namespace, ing.Name), ing)
+	}
+
+	return &retry
+}
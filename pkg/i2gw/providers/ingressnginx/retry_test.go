@@ -0,0 +1,174 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestApplyRetryToEmitterIR(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		expectedRetry         *emitterir.Retry
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name:                  "no retry annotations",
+			annotations:           map[string]string{},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "conditions and status codes",
+			annotations: map[string]string{
+				ProxyNextUpstreamAnnotation: "error timeout http_502 http_503 non_idempotent",
+			},
+			expectedRetry: &emitterir.Retry{
+				Attempts:    ptr.To[int32](2),
+				Conditions:  []emitterir.RetryCondition{emitterir.RetryOnConnectFailure, emitterir.RetryOnTimeout},
+				StatusCodes: []int32{502, 503},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "default conditions with tries and timeout",
+			annotations: map[string]string{
+				ProxyNextUpstreamTriesAnnotation:   "5",
+				ProxyNextUpstreamTimeoutAnnotation: "30",
+			},
+			expectedRetry: &emitterir.Retry{
+				Attempts:      ptr.To[int32](4),
+				Conditions:    []emitterir.RetryCondition{emitterir.RetryOnConnectFailure, emitterir.RetryOnTimeout},
+				PerTryTimeout: ptr.To(gatewayv1.Duration("30s")),
+				Timeout:       ptr.To(gatewayv1.Duration("30s")),
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "unlimited tries and unsupported condition",
+			annotations: map[string]string{
+				ProxyNextUpstreamAnnotation:      "invalid_header http_504",
+				ProxyNextUpstreamTriesAnnotation: "0",
+			},
+			expectedRetry: &emitterir.Retry{
+				StatusCodes: []int32{504},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 3},
+		},
+		{
+			name: "retries turned off",
+			annotations: map[string]string{
+				ProxyNextUpstreamAnnotation: "off",
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "single try",
+			annotations: map[string]string{
+				ProxyNextUpstreamTriesAnnotation: "1",
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "invalid tries",
+			annotations: map[string]string{
+				ProxyNextUpstreamTriesAnnotation: "many",
+			},
+			expectedRetry: &emitterir.Retry{
+				Attempts:   ptr.To[int32](2),
+				Conditions: []emitterir.RetryCondition{emitterir.RetryOnConnectFailure, emitterir.RetryOnTimeout},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "retry",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+
+			key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, "example.com")}
+			route := gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{{}, {}},
+				},
+			}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: route,
+						RuleBackendSources: [][]providerir.BackendSource{
+							{{Ingress: &ing}},
+							{{Ingress: &ing}},
+						},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {HTTPRoute: route},
+				},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify}
+
+			p.applyRetryToEmitterIR(pIR, &eIR)
+
+			result := eIR.HTTPRoutes[key].RetryByRuleIdx
+			if tc.expectedRetry == nil {
+				if result != nil {
+					t.Fatalf("Expected no Retry, got %v", result)
+				}
+			} else {
+				if len(result) != 2 {
+					t.Fatalf("Expected Retry for 2 rules, got %d", len(result))
+				}
+				for idx, retry := range result {
+					if diff := cmp.Diff(tc.expectedRetry, retry, cmpopts.IgnoreFields(emitterir.Retry{}, "Metadata")); diff != "" {
+						t.Errorf("Unexpected Retry for rule %d (-want +got):\n%s", idx, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
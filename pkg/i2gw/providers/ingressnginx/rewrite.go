@@ -18,11 +18,15 @@ package ingressnginx
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -32,8 +36,10 @@ import (
 //
 // It reads ingress-nginx rewrite annotations from ProviderIR sources and stores
 // provider-neutral rewrite intent into EmitterIR, which will later be converted
-// to Gateway API URLRewrite filters by the common emitter.
-func applyRewriteTargetToEmitterIR(ingresses []networkingv1.Ingress,
+// to Gateway API URLRewrite filters by the common emitter. Rewrites referencing
+// the capture groups of a regex path carry the regex rewrite instead, which only
+// implementation-specific emitters can apply.
+func applyRewriteTargetToEmitterIR(notify notifications.NotifyFunc, ingresses []networkingv1.Ingress,
 	pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
 	hostsWithRegex := regexHosts(ingresses)
 
@@ -75,13 +81,9 @@ func applyRewriteTargetToEmitterIR(ingresses []networkingv1.Ingress,
 				pathRewriteIR.Headers["X-Forwarded-Prefix"] = val
 			}
 
-			if hasRegex && strings.Contains(rewriteTarget, "\\$") {
-				pathRewriteIR.RegexCaptureGroupReferences = true
-			}
-
 			source := fmt.Sprintf("rewrite-target from Ingress %s/%s", ing.Namespace, ing.Name)
 			paths := []*field.Path{field.NewPath("metadata", "annotations", fmt.Sprintf("%q", RewriteTargetAnnotation))}
-			if hasRegex && strings.Contains(rewriteTarget, "\\$") {
+			if hasRegex && captureGroupReferenceRegex.MatchString(rewriteTarget) {
 				// Otherwise, rewrites without capture group references work.
 				pathRewriteIR.RegexCaptureGroupReferences = true
 
@@ -90,6 +92,28 @@ func applyRewriteTargetToEmitterIR(ingresses []networkingv1.Ingress,
 					paths,
 					"Path rewrites with capture group references are not supported",
 				)
+
+				ingressPath := ingressPathOf(pRouteCtx.RuleBackendSources[ruleIdx], ing)
+				if ingressPath != "" {
+					if prefix, ok := prefixRewrite(ingressPath, rewriteTarget); ok && len(eRouteCtx.Spec.Rules[ruleIdx].Matches) == 1 {
+						// The regex only strips a path prefix, which Gateway API can express.
+						match := eRouteCtx.Spec.Rules[ruleIdx].Matches[0]
+						match.Path = &gatewayv1.HTTPPathMatch{
+							Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+							Value: ptr.To(prefix),
+						}
+						eRouteCtx.Spec.Rules[ruleIdx].Matches = []gatewayv1.HTTPRouteMatch{match}
+						pathRewriteIR.RegexCaptureGroupReferences = false
+						pathRewriteIR.ReplaceFullPath = ""
+						pathRewriteIR.ReplacePrefixMatch = "/"
+						if strings.ToLower(prefix) != strings.ToUpper(prefix) {
+							notify(notifications.WarningNotification,
+								fmt.Sprintf("Regex path %q of ingress %s/%s matches case-insensitively, its PathPrefix match on %q is case-sensitive", ingressPath, ing.Namespace, ing.Name, prefix), ing)
+						}
+					} else {
+						pathRewriteIR.Regex = regexRewrite(ingressPath, rewriteTarget)
+					}
+				}
 			} else {
 				pathRewriteIR.Metadata = emitterir.NewExtensionFeatureMetadata(
 					source,
@@ -104,3 +128,50 @@ func applyRewriteTargetToEmitterIR(ingresses []networkingv1.Ingress,
 		eIR.HTTPRoutes[key] = eRouteCtx
 	}
 }
+
+// captureGroupReferenceRegex matches the references of a rewrite target to the capture
+// groups of the path, like $1.
+var captureGroupReferenceRegex = regexp.MustCompile(`\$([1-9])`)
+
+// prefixRewriteSuffixes are the suffixes of the regex paths whose rewrite to the last
+// capture group, like "/foo(/|$)(.*)" to "/$2", only strips the literal path prefix.
+var prefixRewriteSuffixes = map[string]string{
+	"(/|$)(.*)":   "/$2",
+	"(?:/|$)(.*)": "/$1",
+}
+
+// prefixRewrite returns the literal path prefix of the regex path when the rewrite
+// target only strips it, which is equivalent to replacing the prefix match with "/".
+func prefixRewrite(path, rewriteTarget string) (string, bool) {
+	for suffix, target := range prefixRewriteSuffixes {
+		prefix, found := strings.CutSuffix(path, suffix)
+		if !found || rewriteTarget != target {
+			continue
+		}
+		if !strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") || regexp.QuoteMeta(prefix) != prefix {
+			return "", false
+		}
+		return prefix, true
+	}
+	return "", false
+}
+
+// regexRewrite returns the rewrite of the path matching the regex path of the Ingress
+// into the rewrite target. Like ingress-nginx, the case-insensitive regex matches the
+// start of the path and the rewrite target replaces the whole path.
+func regexRewrite(path, rewriteTarget string) *emitterir.RegexPathRewrite {
+	return &emitterir.RegexPathRewrite{
+		Pattern:      "(?i)^" + path + ".*",
+		Substitution: captureGroupReferenceRegex.ReplaceAllString(rewriteTarget, `\${1}`),
+	}
+}
+
+// ingressPathOf returns the path of the Ingress contributing the backend sources of a rule.
+func ingressPathOf(sources []providerir.BackendSource, ing *networkingv1.Ingress) string {
+	for _, source := range sources {
+		if source.Ingress == ing && source.Path != nil {
+			return source.Path.Path
+		}
+	}
+	return ""
+}
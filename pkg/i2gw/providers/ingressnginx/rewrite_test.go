@@ -20,11 +20,16 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -69,7 +74,7 @@ func TestApplyRewriteTargetToEmitterIR_SetsRewriteHeadersAndRegex(t *testing.T)
 		},
 	}
 
-	applyRewriteTargetToEmitterIR([]networkingv1.Ingress{ing}, pIR, &eIR)
+	applyRewriteTargetToEmitterIR(notifications.NoopNotify, []networkingv1.Ingress{ing}, pIR, &eIR)
 
 	got := eIR.HTTPRoutes[key].PathRewriteByRuleIdx[0]
 	if got == nil {
@@ -130,7 +135,7 @@ func TestApplyRewriteTargetToEmitterIR_PrefersNonCanaryIngressSource(t *testing.
 		},
 	}
 
-	applyRewriteTargetToEmitterIR([]networkingv1.Ingress{canary, main}, pIR, &eIR)
+	applyRewriteTargetToEmitterIR(notifications.NoopNotify, []networkingv1.Ingress{canary, main}, pIR, &eIR)
 
 	got := eIR.HTTPRoutes[key].PathRewriteByRuleIdx[0]
 	if got == nil {
@@ -144,3 +149,126 @@ func TestApplyRewriteTargetToEmitterIR_PrefersNonCanaryIngressSource(t *testing.
 		t.Fatalf("expected headers %v, got %v", wantHeaders, got.Headers)
 	}
 }
+
+func TestApplyRewriteTargetToEmitterIR_CaptureGroups(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "route"}
+
+	testCases := []struct {
+		name          string
+		path          string
+		rewriteTarget string
+		wantRewrite   emitterir.PathRewrite
+		wantMatch     gatewayv1.HTTPPathMatch
+		// wantWarnings counts the warnings about the case-sensitive prefix match.
+		wantWarnings int
+	}{
+		{
+			name:          "prefix capture group is downgraded to prefix match",
+			path:          "/api(/|$)(.*)",
+			rewriteTarget: "/$2",
+			wantRewrite:   emitterir.PathRewrite{ReplacePrefixMatch: "/"},
+			wantMatch:     gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+			wantWarnings:  1,
+		},
+		{
+			name:          "non-capturing prefix group is downgraded to prefix match",
+			path:          "/api(?:/|$)(.*)",
+			rewriteTarget: "/$1",
+			wantRewrite:   emitterir.PathRewrite{ReplacePrefixMatch: "/"},
+			wantMatch:     gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/api")},
+			wantWarnings:  1,
+		},
+		{
+			name:          "prefix without letters is downgraded without warning",
+			path:          "/2024(/|$)(.*)",
+			rewriteTarget: "/$2",
+			wantRewrite:   emitterir.PathRewrite{ReplacePrefixMatch: "/"},
+			wantMatch:     gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchPathPrefix), Value: ptr.To("/2024")},
+		},
+		{
+			name:          "prefix capture group with another target keeps the regex",
+			path:          "/api(/|$)(.*)",
+			rewriteTarget: "/v1/$2",
+			wantRewrite: emitterir.PathRewrite{
+				ReplaceFullPath:             "/v1/$2",
+				RegexCaptureGroupReferences: true,
+				Regex:                       &emitterir.RegexPathRewrite{Pattern: "(?i)^/api(/|$)(.*).*", Substitution: "/v1/\\2"},
+			},
+			wantMatch: gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To("(?i)/api(/|$)(.*).*")},
+		},
+		{
+			name:          "several capture groups",
+			path:          "/api/(v1|v2)/(.*)",
+			rewriteTarget: "/$2/$1",
+			wantRewrite: emitterir.PathRewrite{
+				ReplaceFullPath:             "/$2/$1",
+				RegexCaptureGroupReferences: true,
+				Regex:                       &emitterir.RegexPathRewrite{Pattern: "(?i)^/api/(v1|v2)/(.*).*", Substitution: "/\\2/\\1"},
+			},
+			wantMatch: gatewayv1.HTTPPathMatch{Type: ptr.To(gatewayv1.PathMatchRegularExpression), Value: ptr.To("(?i)/api/(v1|v2)/(.*).*")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "ing",
+					Annotations: map[string]string{RewriteTargetAnnotation: tc.rewriteTarget},
+				},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{Host: "example.com"}},
+				},
+			}
+			ingressPath := networkingv1.HTTPIngressPath{Path: tc.path, PathType: ptr.To(networkingv1.PathTypeImplementationSpecific)}
+
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							Spec: gatewayv1.HTTPRouteSpec{
+								Hostnames: []gatewayv1.Hostname{"example.com"},
+							},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &ing, Path: &ingressPath}}},
+					},
+				},
+			}
+			eIR := emitterir.EmitterIR{
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{
+							Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{
+								Type:  ptr.To(gatewayv1.PathMatchRegularExpression),
+								Value: ptr.To("(?i)" + tc.path + ".*"),
+							}}},
+						}}}},
+					},
+				},
+			}
+
+			warnings := 0
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				if mType == notifications.WarningNotification {
+					warnings++
+				}
+			}
+			applyRewriteTargetToEmitterIR(notify, []networkingv1.Ingress{ing}, pIR, &eIR)
+
+			got := eIR.HTTPRoutes[key].PathRewriteByRuleIdx[0]
+			if got == nil {
+				t.Fatalf("expected PathRewriteByRuleIdx[0] to be set")
+			}
+			if diff := cmp.Diff(tc.wantRewrite, *got, cmpopts.IgnoreFields(emitterir.PathRewrite{}, "Metadata", "Headers")); diff != "" {
+				t.Errorf("Unexpected path rewrite (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantMatch, *eIR.HTTPRoutes[key].Spec.Rules[0].Matches[0].Path); diff != "" {
+				t.Errorf("Unexpected path match (-want +got):\n%s", diff)
+			}
+			if warnings != tc.wantWarnings {
+				t.Errorf("Expected %d warnings, got %d", tc.wantWarnings, warnings)
+			}
+		})
+	}
+}
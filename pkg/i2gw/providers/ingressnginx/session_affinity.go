@@ -19,6 +19,7 @@ package ingressnginx
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
@@ -28,7 +29,26 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// defaultSessionCookieName is the default of session-cookie-name.
+const defaultSessionCookieName = "INGRESSCOOKIE"
+
+// sessionAffinityAnnotations are the annotations configuring the session affinity cookie.
+var sessionAffinityAnnotations = []string{
+	AffinityAnnotation,
+	AffinityModeAnnotation,
+	SessionCookieNameAnnotation,
+	SessionCookiePathAnnotation,
+	SessionCookieExpiresAnnotation,
+	SessionCookieMaxAgeAnnotation,
+	SessionCookieSameSiteAnnotation,
+	SessionCookieSecureAnnotation,
+	SessionCookieChangeOnFailureAnnotation,
+}
+
 func sessionAffinityFeature(notify notifications.NotifyFunc, _ []networkingv1.Ingress, _ map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName]*emitterir.SessionAffinity{}
+
 	// Iterate over all HTTPRoutes to find backend services and apply generic SessionAffinity
 	for _, httpRouteCtx := range ir.HTTPRoutes {
 		for ruleIdx := range httpRouteCtx.Spec.Rules {
@@ -46,42 +66,25 @@ func sessionAffinityFeature(notify notifications.NotifyFunc, _ []networkingv1.In
 			// Ingress-Nginx usually maps path -> backend service.
 			// We check the Ingress sources for the annotation.
 
-			var affinityType string
-			var cookieTTL *int64
-			var sourceIngress *networkingv1.Ingress
-
+			var sessionAffinity *emitterir.SessionAffinity
 			for _, source := range sources {
-				if val, ok := source.Ingress.Annotations[AffinityAnnotation]; ok && val == "cookie" {
-					affinityType = "Cookie"
-					sourceIngress = source.Ingress
-
-					// Check for Max Age (Expires)
-					if ttlVal, ok := source.Ingress.Annotations[SessionCookieExpiresAnnotation]; ok {
-						if ttl, err := strconv.ParseInt(ttlVal, 10, 64); err == nil {
-							cookieTTL = &ttl
-						}
-					}
-
-					break
+				if source.Ingress.Annotations[AffinityAnnotation] != "cookie" {
+					continue
 				}
+				ingKey := types.NamespacedName{Namespace: source.Ingress.Namespace, Name: source.Ingress.Name}
+				var found bool
+				sessionAffinity, found = parsed[ingKey]
+				if !found {
+					sessionAffinity = parseSessionAffinity(notify, source.Ingress)
+					parsed[ingKey] = sessionAffinity
+				}
+				break
 			}
 
-			if affinityType == "" {
+			if sessionAffinity == nil {
 				continue
 			}
 
-			// Build metadata following the same pattern as IPRangeControl:
-			// source is namespace/name, paths list all parsed annotations.
-			source := fmt.Sprintf("%s/%s", sourceIngress.Namespace, sourceIngress.Name)
-			message := "Session affinity is not supported"
-			paths := []*field.Path{
-				field.NewPath(sourceIngress.Namespace, sourceIngress.Name, "metadata", "annotations", fmt.Sprintf("%q", AffinityAnnotation)),
-			}
-			if cookieTTL != nil {
-				paths = append(paths, field.NewPath(sourceIngress.Namespace, sourceIngress.Name, "metadata", "annotations", fmt.Sprintf("%q", SessionCookieExpiresAnnotation)))
-			}
-			metadata := emitterir.NewExtensionFeatureMetadata(source, paths, message)
-
 			// Apply to all backend refs in this rule?
 			// Session Affinity is per Backend Service.
 			// We need to update the ServiceIR for the referenced services.
@@ -94,30 +97,79 @@ func sessionAffinityFeature(notify notifications.NotifyFunc, _ []networkingv1.In
 					Name:      refName,
 				}
 
-				if svc, ok := ir.Services[svcKey]; ok {
-					if svc.SessionAffinity == nil {
-						svc.SessionAffinity = &emitterir.SessionAffinity{}
-					}
-
-					svc.SessionAffinity.Type = affinityType
-					svc.SessionAffinity.CookieTTLSec = cookieTTL
-					svc.SessionAffinity.Metadata = metadata
-
-					// Update the map
-					ir.Services[svcKey] = svc
-				} else {
-					// Service doesn't exist yet, create it
-					svc = providerir.ProviderSpecificServiceIR{
-						SessionAffinity: &emitterir.SessionAffinity{
-							Metadata:     metadata,
-							Type:         affinityType,
-							CookieTTLSec: cookieTTL,
-						},
-					}
-					ir.Services[svcKey] = svc
-				}
+				// Update the map, creating the Service if it doesn't exist yet
+				svc := ir.Services[svcKey]
+				svc.SessionAffinity = sessionAffinity
+				ir.Services[svcKey] = svc
 			}
 		}
 	}
 	return nil
 }
+
+// parseSessionAffinity returns the cookie affinity of the Ingress.
+func parseSessionAffinity(notify notifications.NotifyFunc, ing *networkingv1.Ingress) *emitterir.SessionAffinity {
+	// Build metadata following the same pattern as IPRangeControl:
+	// source is namespace/name, paths list all parsed annotations.
+	var paths []*field.Path
+	for _, annotation := range sessionAffinityAnnotations {
+		if _, ok := ing.Annotations[annotation]; ok {
+			paths = append(paths, field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", annotation)))
+		}
+	}
+
+	sessionAffinity := &emitterir.SessionAffinity{
+		Metadata:   emitterir.NewExtensionFeatureMetadata(fmt.Sprintf("%s/%s", ing.Namespace, ing.Name), paths, "Session affinity is not supported"),
+		Type:       "Cookie",
+		Mode:       emitterir.SessionAffinityBalanced,
+		CookieName: defaultSessionCookieName,
+		CookiePath: strings.TrimSpace(ing.Annotations[SessionCookiePathAnnotation]),
+	}
+
+	switch mode := strings.TrimSpace(ing.Annotations[AffinityModeAnnotation]); mode {
+	case "", "balanced":
+	case "persistent":
+		sessionAffinity.Mode = emitterir.SessionAffinityPersistent
+	default:
+		notify(notifications.WarningNotification, fmt.Sprintf("Unsupported affinity-mode annotation %q, balanced mode is used", mode), ing)
+	}
+
+	if name := strings.TrimSpace(ing.Annotations[SessionCookieNameAnnotation]); name != "" {
+		sessionAffinity.CookieName = name
+	}
+
+	// Max-Age takes precedence over Expires in browsers.
+	for _, annotation := range []string{SessionCookieMaxAgeAnnotation, SessionCookieExpiresAnnotation} {
+		ttlVal, ok := ing.Annotations[annotation]
+		if !ok || sessionAffinity.CookieTTLSec != nil {
+			continue
+		}
+		ttl, err := strconv.ParseInt(strings.TrimSpace(ttlVal), 10, 64)
+		if err != nil || ttl < 0 {
+			notify(notifications.WarningNotification, fmt.Sprintf("Invalid %s annotation %q, the cookie expires with the browser session", annotation, ttlVal), ing)
+			continue
+		}
+		sessionAffinity.CookieTTLSec = &ttl
+	}
+
+	if sameSite := strings.TrimSpace(ing.Annotations[SessionCookieSameSiteAnnotation]); sameSite != "" {
+		switch strings.ToLower(sameSite) {
+		case "strict":
+			sessionAffinity.CookieSameSite = "Strict"
+		case "lax":
+			sessionAffinity.CookieSameSite = "Lax"
+		case "none":
+			sessionAffinity.CookieSameSite = "None"
+		default:
+			notify(notifications.WarningNotification, fmt.Sprintf("Invalid session-cookie-samesite annotation %q, the SameSite attribute is not set", sameSite), ing)
+		}
+	}
+
+	sessionAffinity.CookieSecure, _ = strconv.ParseBool(strings.TrimSpace(ing.Annotations[SessionCookieSecureAnnotation]))
+
+	if changeOnFailure, _ := strconv.ParseBool(strings.TrimSpace(ing.Annotations[SessionCookieChangeOnFailureAnnotation])); changeOnFailure {
+		notify(notifications.InfoNotification, "session-cookie-change-on-failure is not converted, whether sessions move to another endpoint when theirs fails is implementation specific", ing)
+	}
+
+	return sessionAffinity
+}
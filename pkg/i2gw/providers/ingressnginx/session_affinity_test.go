@@ -66,7 +66,9 @@ func TestGCEFeature(t *testing.T) {
 					[]*field.Path{field.NewPath("default", "cookie-affinity", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/affinity"))},
 					"Session affinity is not supported",
 				),
-				Type: "Cookie",
+				Type:       "Cookie",
+				Mode:       emitterir.SessionAffinityBalanced,
+				CookieName: "INGRESSCOOKIE",
 			},
 		},
 		{
@@ -91,11 +93,13 @@ func TestGCEFeature(t *testing.T) {
 					"Session affinity is not supported",
 				),
 				Type:         "Cookie",
+				Mode:         emitterir.SessionAffinityBalanced,
+				CookieName:   "INGRESSCOOKIE",
 				CookieTTLSec: ptr.To(int64(3600)),
 			},
 		},
 		{
-			name: "Cookie Affinity with Name (unparsed - no emitter consumes it)",
+			name: "Cookie Affinity with Name",
 			ingress: networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "cookie-affinity-name",
@@ -109,10 +113,59 @@ func TestGCEFeature(t *testing.T) {
 			expectedSessionAffinity: &emitterir.SessionAffinity{
 				Metadata: emitterir.NewExtensionFeatureMetadata(
 					"default/cookie-affinity-name",
-					[]*field.Path{field.NewPath("default", "cookie-affinity-name", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/affinity"))},
+					[]*field.Path{
+						field.NewPath("default", "cookie-affinity-name", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/affinity")),
+						field.NewPath("default", "cookie-affinity-name", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-name")),
+					},
+					"Session affinity is not supported",
+				),
+				Type:       "Cookie",
+				Mode:       emitterir.SessionAffinityBalanced,
+				CookieName: "MY_COOKIE",
+			},
+		},
+		{
+			name: "Cookie Affinity with all options",
+			ingress: networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cookie-affinity-options",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"nginx.ingress.kubernetes.io/affinity":                         "cookie",
+						"nginx.ingress.kubernetes.io/affinity-mode":                    "persistent",
+						"nginx.ingress.kubernetes.io/session-cookie-name":              "route",
+						"nginx.ingress.kubernetes.io/session-cookie-path":              "/app",
+						"nginx.ingress.kubernetes.io/session-cookie-expires":           "172800",
+						"nginx.ingress.kubernetes.io/session-cookie-max-age":           "3600",
+						"nginx.ingress.kubernetes.io/session-cookie-samesite":          "strict",
+						"nginx.ingress.kubernetes.io/session-cookie-secure":            "true",
+						"nginx.ingress.kubernetes.io/session-cookie-change-on-failure": "true",
+					},
+				},
+			},
+			expectedSessionAffinity: &emitterir.SessionAffinity{
+				Metadata: emitterir.NewExtensionFeatureMetadata(
+					"default/cookie-affinity-options",
+					[]*field.Path{
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/affinity")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/affinity-mode")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-name")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-path")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-expires")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-max-age")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-samesite")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-secure")),
+						field.NewPath("default", "cookie-affinity-options", "metadata", "annotations", fmt.Sprintf("%q", "nginx.ingress.kubernetes.io/session-cookie-change-on-failure")),
+					},
 					"Session affinity is not supported",
 				),
-				Type: "Cookie",
+				Type:           "Cookie",
+				Mode:           emitterir.SessionAffinityPersistent,
+				CookieName:     "route",
+				CookiePath:     "/app",
+				CookieTTLSec:   ptr.To(int64(3600)),
+				CookieSameSite: "Strict",
+				CookieSecure:   true,
 			},
 		},
 	}
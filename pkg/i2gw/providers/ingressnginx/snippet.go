@@ -0,0 +1,519 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// snippetDirective is a directive of an nginx snippet.
+type snippetDirective struct {
+	name string
+	args []string
+	// block is set for block directives, such as if and location, whose
+	// content isn't parsed.
+	block bool
+	// text is the directive as written in the snippet.
+	text string
+}
+
+// snippetConfig is the configuration of the directives of a snippet which are
+// converted to Gateway API.
+type snippetConfig struct {
+	// ingress and annotation are the source of the snippet.
+	ingress    *networkingv1.Ingress
+	annotation string
+
+	requestHeaders  gatewayv1.HTTPHeaderFilter
+	responseHeaders gatewayv1.HTTPHeaderFilter
+	redirect        *gatewayv1.HTTPRequestRedirectFilter
+	rewritePath     string
+	allowList       []string
+	denyList        []string
+	// unsupported lists the directives which aren't converted.
+	unsupported []string
+	// accessOverridden is set once the allow and deny directives are reported
+	// as overridden by the source range annotations.
+	accessOverridden bool
+}
+
+// snippetKey identifies the snippet annotation of an Ingress.
+type snippetKey struct {
+	ingress    types.NamespacedName
+	annotation string
+}
+
+// matchAllRegexes are the rewrite regexes matching every path.
+var matchAllRegexes = []string{"^", ".*", "(.*)", "^.*", "^.*$", "^(.*)", "^(.*)$", "^/", "^/.*", "^/.*$", "^/(.*)", "^/(.*)$"}
+
+// parseSnippet splits an nginx snippet into its directives. Comments are skipped
+// and quotes are removed from the arguments.
+func parseSnippet(snippet string) []snippetDirective {
+	var directives []snippetDirective
+	var tokens []string
+	var token strings.Builder
+	hasToken := false
+	start := -1
+
+	endToken := func() {
+		if hasToken {
+			tokens = append(tokens, token.String())
+			token.Reset()
+			hasToken = false
+		}
+	}
+	endDirective := func(end int, block bool) {
+		endToken()
+		if len(tokens) > 0 {
+			directives = append(directives, snippetDirective{
+				name:  tokens[0],
+				args:  tokens[1:],
+				block: block,
+				text:  strings.Join(strings.Fields(snippet[start:end]), " "),
+			})
+		}
+		tokens = nil
+		start = -1
+	}
+
+	for i := 0; i < len(snippet); i++ {
+		c := snippet[i]
+		switch {
+		case c == '#' && !hasToken:
+			for i < len(snippet) && snippet[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			if start == -1 {
+				start = i
+			}
+			hasToken = true
+			for i++; i < len(snippet) && snippet[i] != c; i++ {
+				if snippet[i] == '\\' && i+1 < len(snippet) {
+					i++
+				}
+				token.WriteByte(snippet[i])
+			}
+		case c == ';':
+			if start != -1 {
+				endDirective(i+1, false)
+			}
+		case c == '{':
+			if start == -1 {
+				start = i
+			}
+			depth := 1
+			for i++; i < len(snippet) && depth > 0; i++ {
+				switch snippet[i] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+			}
+			endDirective(i, true)
+			i--
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			endToken()
+		default:
+			if start == -1 {
+				start = i
+			}
+			hasToken = true
+			token.WriteByte(c)
+		}
+	}
+	if start != -1 {
+		endDirective(len(snippet), false)
+	}
+	return directives
+}
+
+// parseSnippetConfig converts the directives of a snippet which have a Gateway API
+// equivalent: more_set_headers, add_header, proxy_set_header, return of a redirect,
+// rewrite of every path with break, and allow and deny. The headers passing the
+// upgrade of the client are converted when the backends serve WebSocket.
+func parseSnippetConfig(snippet string, webSocket bool) *snippetConfig {
+	config := &snippetConfig{}
+	var access []snippetDirective
+
+	for _, directive := range parseSnippet(snippet) {
+		converted := false
+		if !directive.block {
+			switch directive.name {
+			case "more_set_headers":
+				converted = config.parseMoreSetHeaders(directive.args)
+			case "add_header":
+				converted = config.parseAddHeader(directive.args)
+			case "proxy_set_header":
+				converted = config.parseProxySetHeader(directive.args, webSocket)
+			case "return":
+				converted = config.parseReturn(directive.args)
+			case "rewrite":
+				converted = config.parseRewrite(directive.args)
+			case "allow", "deny":
+				if len(directive.args) == 1 {
+					access = append(access, directive)
+					converted = true
+				}
+			}
+		}
+		if !converted {
+			config.unsupported = append(config.unsupported, directive.text)
+		}
+	}
+
+	config.parseAccess(access)
+	return config
+}
+
+// parseMoreSetHeaders converts more_set_headers "Name: value"..., which sets the
+// response headers, or removes them without a value.
+func (c *snippetConfig) parseMoreSetHeaders(args []string) bool {
+	var set []gatewayv1.HTTPHeader
+	var remove []string
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, ":")
+		value = strings.TrimSpace(value)
+		if !found || !headerNameRegex.MatchString(name) || strings.Contains(value, "$") {
+			return false
+		}
+		if value == "" {
+			remove = append(remove, name)
+		} else {
+			set = append(set, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(name), Value: value})
+		}
+	}
+	if len(set) == 0 && len(remove) == 0 {
+		return false
+	}
+	for _, header := range set {
+		setHeader(&c.responseHeaders, header)
+	}
+	c.responseHeaders.Remove = append(c.responseHeaders.Remove, remove...)
+	return true
+}
+
+// parseAddHeader converts add_header name value [always], which adds a response header.
+func (c *snippetConfig) parseAddHeader(args []string) bool {
+	if len(args) < 2 || len(args) > 3 || (len(args) == 3 && args[2] != "always") {
+		return false
+	}
+	if !headerNameRegex.MatchString(args[0]) || strings.Contains(args[1], "$") {
+		return false
+	}
+	c.responseHeaders.Add = append(c.responseHeaders.Add, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(args[0]), Value: args[1]})
+	return true
+}
+
+// parseProxySetHeader converts proxy_set_header name value, which sets a request
+// header, or removes it with an empty value.
+func (c *snippetConfig) parseProxySetHeader(args []string, webSocket bool) bool {
+	// ingress-nginx passes the upgrade of the client by default, and the
+	// WebSocket backends get their appProtocol, see addServiceAppProtocolPatches.
+	if isUpgradeHeader(args) {
+		return webSocket
+	}
+	if len(args) != 2 || !headerNameRegex.MatchString(args[0]) || strings.Contains(args[1], "$") {
+		return false
+	}
+	if args[1] == "" {
+		c.requestHeaders.Remove = append(c.requestHeaders.Remove, args[0])
+	} else {
+		setHeader(&c.requestHeaders, gatewayv1.HTTPHeader{Name: gatewayv1.HTTPHeaderName(args[0]), Value: args[1]})
+	}
+	return true
+}
+
+// isUpgradeHeader reports whether the proxy_set_header arguments pass the
+// protocol upgrade of the client, such as a WebSocket handshake, to the backend.
+func isUpgradeHeader(args []string) bool {
+	if len(args) != 2 {
+		return false
+	}
+	switch strings.ToLower(args[0]) {
+	case "upgrade":
+		return args[1] == "$http_upgrade"
+	case "connection":
+		return args[1] == "$connection_upgrade" || strings.EqualFold(args[1], "upgrade")
+	}
+	return false
+}
+
+// parseReturn converts return code URL with a redirect status code. The URL may
+// keep the scheme, host and path of the request, see parseRedirectURL.
+func (c *snippetConfig) parseReturn(args []string) bool {
+	if len(args) != 2 {
+		return false
+	}
+	code, err := strconv.Atoi(args[0])
+	if err != nil || !isValidPermanentRedirectCode(code) {
+		return false
+	}
+
+	// The URLs with a query or fragment are left to the snippet warning.
+	redirect, dropped, err := parseRedirectURL(args[1])
+	if err != nil || dropped != "" {
+		return false
+	}
+	redirect.StatusCode = ptr.To(code)
+	c.redirect = redirect
+	return true
+}
+
+// parseRewrite converts rewrite regex replacement break when the regex matches
+// every path and the replacement is a fixed path.
+func (c *snippetConfig) parseRewrite(args []string) bool {
+	if len(args) != 3 || args[2] != "break" || !slices.Contains(matchAllRegexes, args[0]) {
+		return false
+	}
+	replacement := args[1]
+	if !strings.HasPrefix(replacement, "/") || strings.ContainsAny(replacement, "$?") {
+		return false
+	}
+	c.rewritePath = replacement
+	return true
+}
+
+// parseAccess converts the allow and deny directives, which nginx evaluates in
+// order, when they are an allow list ending with deny all, or a deny list
+// optionally ending with allow all.
+func (c *snippetConfig) parseAccess(access []snippetDirective) {
+	if len(access) == 0 {
+		return
+	}
+
+	addresses := func(directives []snippetDirective, name string) ([]string, bool) {
+		var addresses []string
+		for _, directive := range directives {
+			address := directive.args[0]
+			if directive.name != name || !isIPOrCIDR(address) {
+				return nil, false
+			}
+			addresses = append(addresses, address)
+		}
+		return addresses, true
+	}
+
+	last := access[len(access)-1]
+	rest := access[:len(access)-1]
+	if last.args[0] == "all" && len(rest) > 0 {
+		if last.name == "deny" {
+			if allowList, ok := addresses(rest, "allow"); ok {
+				c.allowList = allowList
+				return
+			}
+		} else if denyList, ok := addresses(rest, "deny"); ok {
+			c.denyList = denyList
+			return
+		}
+	} else if denyList, ok := addresses(access, "deny"); ok {
+		c.denyList = denyList
+		return
+	}
+
+	for _, directive := range access {
+		c.unsupported = append(c.unsupported, directive.text)
+	}
+}
+
+func isIPOrCIDR(address string) bool {
+	if _, _, err := net.ParseCIDR(address); err == nil {
+		return true
+	}
+	return net.ParseIP(address) != nil
+}
+
+// setHeader sets the header in the filter, replacing a header of the same name.
+func setHeader(filter *gatewayv1.HTTPHeaderFilter, header gatewayv1.HTTPHeader) {
+	idx := slices.IndexFunc(filter.Set, func(h gatewayv1.HTTPHeader) bool {
+		return strings.EqualFold(string(h.Name), string(header.Name))
+	})
+	if idx == -1 {
+		filter.Set = append(filter.Set, header)
+		return
+	}
+	filter.Set[idx] = header
+}
+
+// mergeHeaderFilter merges the headers into the header modifier filter of the type of the rule.
+func mergeHeaderFilter(rule *gatewayv1.HTTPRouteRule, filterType gatewayv1.HTTPRouteFilterType, headers gatewayv1.HTTPHeaderFilter) {
+	if len(headers.Set) == 0 && len(headers.Add) == 0 && len(headers.Remove) == 0 {
+		return
+	}
+
+	filterIdx := slices.IndexFunc(rule.Filters, func(f gatewayv1.HTTPRouteFilter) bool {
+		return f.Type == filterType
+	})
+	if filterIdx == -1 {
+		filter := gatewayv1.HTTPRouteFilter{Type: filterType}
+		if filterType == gatewayv1.HTTPRouteFilterRequestHeaderModifier {
+			filter.RequestHeaderModifier = &gatewayv1.HTTPHeaderFilter{}
+		} else {
+			filter.ResponseHeaderModifier = &gatewayv1.HTTPHeaderFilter{}
+		}
+		rule.Filters = append(rule.Filters, filter)
+		filterIdx = len(rule.Filters) - 1
+	}
+
+	target := rule.Filters[filterIdx].RequestHeaderModifier
+	if filterType == gatewayv1.HTTPRouteFilterResponseHeaderModifier {
+		target = rule.Filters[filterIdx].ResponseHeaderModifier
+	}
+	for _, header := range headers.Set {
+		setHeader(target, header)
+	}
+	target.Add = append(target.Add, headers.Add...)
+	target.Remove = append(target.Remove, headers.Remove...)
+}
+
+// applySnippetsToEmitterIR converts the supported directives of the server-snippet
+// and configuration-snippet annotations. A server snippet applies to every rule of
+// the host, and the configuration snippet of the rule is applied after it. The
+// webSocketIngresses are the Ingresses whose backends get a WebSocket appProtocol.
+func (p *Provider) applySnippetsToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR, webSocketIngresses map[types.NamespacedName]bool) {
+	// Snippets are parsed once, so that their unsupported directives are only reported once.
+	parsed := map[snippetKey]*snippetConfig{}
+	configOf := func(ing *networkingv1.Ingress, annotation string) *snippetConfig {
+		snippet, ok := ing.Annotations[annotation]
+		if !ok {
+			return nil
+		}
+		key := snippetKey{ingress: types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}, annotation: annotation}
+		config, found := parsed[key]
+		if !found {
+			config = parseSnippetConfig(snippet, webSocketIngresses[key.ingress])
+			config.ingress = ing
+			config.annotation = annotation
+			for _, directive := range config.unsupported {
+				p.notify(notifications.WarningNotification,
+					fmt.Sprintf("Unsupported directive %q in %s annotation of Ingress %s/%s is not converted", directive, annotation, ing.Namespace, ing.Name), ing)
+			}
+			parsed[key] = config
+		}
+		return config
+	}
+
+	for key, pRouteCtx := range pIR.HTTPRoutes {
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok {
+			continue
+		}
+
+		var serverConfigs []*snippetConfig
+		var serverIngresses []*networkingv1.Ingress
+		for _, sources := range pRouteCtx.RuleBackendSources {
+			ing := getNonCanaryIngress(sources)
+			if ing == nil || slices.Contains(serverIngresses, ing) {
+				continue
+			}
+			serverIngresses = append(serverIngresses, ing)
+			if config := configOf(ing, ServerSnippetAnnotation); config != nil {
+				serverConfigs = append(serverConfigs, config)
+			}
+		}
+
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			configs := slices.Clone(serverConfigs)
+			if config := configOf(ing, ConfigurationSnippetAnnotation); config != nil {
+				configs = append(configs, config)
+			}
+			// The IP range control set before the snippets comes from the source
+			// range annotations.
+			sourceRanges := eRouteCtx.IPRangeControlByRuleIdx[ruleIdx] != nil
+			for _, config := range configs {
+				p.applySnippetConfig(&eRouteCtx, ruleIdx, config, sourceRanges)
+			}
+		}
+		eIR.HTTPRoutes[key] = eRouteCtx
+	}
+}
+
+// applySnippetConfig applies the converted directives of a snippet to the rule.
+// The allow and deny directives are dropped when the rule has source range
+// annotations, and otherwise replace the ones of a snippet applied before, as
+// those of a location replace the ones of its server in NGINX.
+func (p *Provider) applySnippetConfig(eRouteCtx *emitterir.HTTPRouteContext, ruleIdx int, config *snippetConfig, sourceRanges bool) {
+	rule := &eRouteCtx.Spec.Rules[ruleIdx]
+	ing := config.ingress
+	source := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+	paths := []*field.Path{field.NewPath(ing.Namespace, ing.Name, "metadata", "annotations", fmt.Sprintf("%q", config.annotation))}
+
+	mergeHeaderFilter(rule, gatewayv1.HTTPRouteFilterRequestHeaderModifier, config.requestHeaders)
+	mergeHeaderFilter(rule, gatewayv1.HTTPRouteFilterResponseHeaderModifier, config.responseHeaders)
+
+	if config.redirect != nil {
+		rule.Filters = slices.DeleteFunc(rule.Filters, func(f gatewayv1.HTTPRouteFilter) bool {
+			return f.Type == gatewayv1.HTTPRouteFilterRequestRedirect || f.Type == gatewayv1.HTTPRouteFilterURLRewrite
+		})
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+			Type:            gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: config.redirect.DeepCopy(),
+		})
+		// Redirects don't route to backends.
+		rule.BackendRefs = nil
+		delete(eRouteCtx.PathRewriteByRuleIdx, ruleIdx)
+	} else if config.rewritePath != "" {
+		if eRouteCtx.PathRewriteByRuleIdx == nil {
+			eRouteCtx.PathRewriteByRuleIdx = make(map[int]*emitterir.PathRewrite)
+		}
+		eRouteCtx.PathRewriteByRuleIdx[ruleIdx] = &emitterir.PathRewrite{
+			Metadata:        emitterir.NewExtensionFeatureMetadata(source, paths, "Could not apply the rewrite directive of the snippet"),
+			ReplaceFullPath: config.rewritePath,
+		}
+	}
+
+	if len(config.allowList) > 0 || len(config.denyList) > 0 {
+		if sourceRanges {
+			if !config.accessOverridden {
+				config.accessOverridden = true
+				p.notify(notifications.WarningNotification,
+					fmt.Sprintf("The allow and deny directives in %s annotation of Ingress %s/%s are evaluated after the source range annotations, which take precedence", config.annotation, ing.Namespace, ing.Name), ing)
+			}
+			return
+		}
+		if eRouteCtx.IPRangeControlByRuleIdx == nil {
+			eRouteCtx.IPRangeControlByRuleIdx = make(map[int]*emitterir.IPRangeControl)
+		}
+		eRouteCtx.IPRangeControlByRuleIdx[ruleIdx] = &emitterir.IPRangeControl{
+			Metadata:  emitterir.NewExtensionFeatureMetadata(source, paths, "IP-based authorization is not supported"),
+			AllowList: config.allowList,
+			DenyList:  config.denyList,
+		}
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func Test_parseSnippet(t *testing.T) {
+	snippet := `# security headers
+more_set_headers "X-Frame-Options: DENY" 'X-Served-By: nginx';
+if ($http_user_agent ~* "bot") {
+    return 403;
+}
+proxy_set_header   X-Team  platform;`
+
+	expected := []snippetDirective{
+		{name: "more_set_headers", args: []string{"X-Frame-Options: DENY", "X-Served-By: nginx"}, text: `more_set_headers "X-Frame-Options: DENY" 'X-Served-By: nginx';`},
+		{name: "if", args: []string{"($http_user_agent", "~*", "bot)"}, block: true, text: `if ($http_user_agent ~* "bot") { return 403; }`},
+		{name: "proxy_set_header", args: []string{"X-Team", "platform"}, text: "proxy_set_header X-Team platform;"},
+	}
+	if diff := cmp.Diff(expected, parseSnippet(snippet), cmp.AllowUnexported(snippetDirective{})); diff != "" {
+		t.Errorf("Unexpected directives (-want +got):\n%s", diff)
+	}
+}
+
+func Test_parseSnippetConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		snippet   string
+		webSocket bool
+		expected  *snippetConfig
+	}{
+		{
+			name: "headers",
+			snippet: `more_set_headers "X-Frame-Options: DENY" "Server:";
+add_header Cache-Control no-store always;
+proxy_set_header X-Team platform;
+proxy_set_header Accept-Encoding "";`,
+			expected: &snippetConfig{
+				requestHeaders: gatewayv1.HTTPHeaderFilter{
+					Set:    []gatewayv1.HTTPHeader{{Name: "X-Team", Value: "platform"}},
+					Remove: []string{"Accept-Encoding"},
+				},
+				responseHeaders: gatewayv1.HTTPHeaderFilter{
+					Set:    []gatewayv1.HTTPHeader{{Name: "X-Frame-Options", Value: "DENY"}},
+					Add:    []gatewayv1.HTTPHeader{{Name: "Cache-Control", Value: "no-store"}},
+					Remove: []string{"Server"},
+				},
+			},
+		},
+		{
+			name: "WebSocket upgrade headers",
+			snippet: `proxy_set_header Upgrade $http_upgrade;
+proxy_set_header Connection "upgrade";`,
+			webSocket: true,
+			expected:  &snippetConfig{},
+		},
+		{
+			name: "WebSocket upgrade headers without a WebSocket appProtocol",
+			snippet: `proxy_set_header Upgrade $http_upgrade;
+proxy_set_header Connection "upgrade";`,
+			expected: &snippetConfig{
+				unsupported: []string{
+					"proxy_set_header Upgrade $http_upgrade;",
+					`proxy_set_header Connection "upgrade";`,
+				},
+			},
+		},
+		{
+			name:    "redirect keeping the host and path",
+			snippet: "return 301 https://$host$request_uri;",
+			expected: &snippetConfig{
+				redirect: &gatewayv1.HTTPRequestRedirectFilter{Scheme: ptr.To("https"), StatusCode: ptr.To(301)},
+			},
+		},
+		{
+			name:    "redirect to a URL",
+			snippet: "return 302 https://new.example.com:8443/landing;",
+			expected: &snippetConfig{
+				redirect: &gatewayv1.HTTPRequestRedirectFilter{
+					Scheme:     ptr.To("https"),
+					Hostname:   ptr.To(gatewayv1.PreciseHostname("new.example.com")),
+					Port:       ptr.To(gatewayv1.PortNumber(8443)),
+					Path:       &gatewayv1.HTTPPathModifier{Type: gatewayv1.FullPathHTTPPathModifier, ReplaceFullPath: ptr.To("/landing")},
+					StatusCode: ptr.To(302),
+				},
+			},
+		},
+		{
+			name:     "rewrite",
+			snippet:  "rewrite ^/(.*)$ /maintenance.html break;",
+			expected: &snippetConfig{rewritePath: "/maintenance.html"},
+		},
+		{
+			name:     "allow list",
+			snippet:  "allow 10.0.0.0/8; allow 192.168.1.1; deny all;",
+			expected: &snippetConfig{allowList: []string{"10.0.0.0/8", "192.168.1.1"}},
+		},
+		{
+			name:     "deny list",
+			snippet:  "deny 10.0.0.0/8; allow all;",
+			expected: &snippetConfig{denyList: []string{"10.0.0.0/8"}},
+		},
+		{
+			name: "unsupported directives",
+			snippet: `proxy_set_header X-Real-IP $remote_addr;
+return 403;
+rewrite ^/old/(.*)$ /new/$1 last;
+allow 10.0.0.0/8; deny 10.1.0.0/16; allow all;
+location /internal { deny all; }`,
+			expected: &snippetConfig{
+				unsupported: []string{
+					"proxy_set_header X-Real-IP $remote_addr;",
+					"return 403;",
+					"rewrite ^/old/(.*)$ /new/$1 last;",
+					"location /internal { deny all; }",
+					"allow 10.0.0.0/8;",
+					"deny 10.1.0.0/16;",
+					"allow all;",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, parseSnippetConfig(tc.snippet, tc.webSocket), cmp.AllowUnexported(snippetConfig{})); diff != "" {
+				t.Errorf("Unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplySnippetsToEmitterIR(t *testing.T) {
+	server := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "server",
+			Annotations: map[string]string{ServerSnippetAnnotation: "more_set_headers 'X-Frame-Options: DENY'; ssl_stapling on;"},
+		},
+	}
+	location := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "location",
+			Annotations: map[string]string{
+				ConfigurationSnippetAnnotation: "more_set_headers 'X-Frame-Options: SAMEORIGIN'; allow 10.0.0.0/8; deny all;",
+			},
+		},
+	}
+
+	key := types.NamespacedName{Namespace: "default", Name: "server-example-com"}
+	route := gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}, {}}},
+	}
+	pIR := providerir.ProviderIR{
+		HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+			key: {
+				HTTPRoute:          route,
+				RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &server}}, {{Ingress: &location}}},
+			},
+		},
+	}
+	eIR := providerir.ToEmitterIR(pIR)
+
+	notificationCount := 0
+	p := &Provider{notify: func(notifications.MessageType, string, ...client.Object) {
+		notificationCount++
+	}}
+	p.applySnippetsToEmitterIR(pIR, &eIR, nil)
+
+	rules := eIR.HTTPRoutes[key].Spec.Rules
+	expectedHeaders := []string{"DENY", "SAMEORIGIN"}
+	for i, rule := range rules {
+		expected := []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set: []gatewayv1.HTTPHeader{{Name: "X-Frame-Options", Value: expectedHeaders[i]}},
+			},
+		}}
+		if diff := cmp.Diff(expected, rule.Filters); diff != "" {
+			t.Errorf("Unexpected filters of rule %d (-want +got):\n%s", i, diff)
+		}
+	}
+
+	ipRangeControl := eIR.HTTPRoutes[key].IPRangeControlByRuleIdx
+	if _, ok := ipRangeControl[0]; ok {
+		t.Errorf("Expected no IP range control on rule 0, got %+v", ipRangeControl[0])
+	}
+	if diff := cmp.Diff([]string{"10.0.0.0/8"}, ipRangeControl[1].AllowList); diff != "" {
+		t.Errorf("Unexpected allow list of rule 1 (-want +got):\n%s", diff)
+	}
+	if ipRangeControl[1].Metadata.Source() != "default/location" {
+		t.Errorf("Unexpected IP range control source %q", ipRangeControl[1].Metadata.Source())
+	}
+
+	// ssl_stapling is reported once.
+	if notificationCount != 1 {
+		t.Errorf("Expected 1 notification, got %d", notificationCount)
+	}
+}
+
+func TestApplySnippetsToEmitterIR_AccessDirectives(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		sourceRanges         []string
+		expectedAllowList    []string
+		expectedAnnotation   string
+		expectedWarningCount int
+	}{{
+		name:               "server snippet",
+		annotations:        map[string]string{ServerSnippetAnnotation: "allow 10.0.0.0/8; deny all;"},
+		expectedAllowList:  []string{"10.0.0.0/8"},
+		expectedAnnotation: ServerSnippetAnnotation,
+	}, {
+		name: "configuration snippet replaces the server snippet",
+		annotations: map[string]string{
+			ServerSnippetAnnotation:        "allow 10.0.0.0/8; deny all;",
+			ConfigurationSnippetAnnotation: "allow 192.168.0.0/16; deny all;",
+		},
+		expectedAllowList:  []string{"192.168.0.0/16"},
+		expectedAnnotation: ConfigurationSnippetAnnotation,
+	}, {
+		name: "source range annotations take precedence",
+		annotations: map[string]string{
+			ServerSnippetAnnotation:        "allow 10.0.0.0/8; deny all;",
+			ConfigurationSnippetAnnotation: "allow 192.168.0.0/16; deny all;",
+		},
+		sourceRanges:         []string{"172.16.0.0/12"},
+		expectedAllowList:    []string{"172.16.0.0/12"},
+		expectedAnnotation:   WhiteListSourceRangeAnnotation,
+		expectedWarningCount: 2,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing", Annotations: tc.annotations},
+			}
+			key := types.NamespacedName{Namespace: "default", Name: "ing-example-com"}
+			pIR := providerir.ProviderIR{
+				HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{
+					key: {
+						HTTPRoute: gatewayv1.HTTPRoute{
+							ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+							Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{}}},
+						},
+						RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &ing}}},
+					},
+				},
+			}
+			eIR := providerir.ToEmitterIR(pIR)
+			if len(tc.sourceRanges) > 0 {
+				eRouteCtx := eIR.HTTPRoutes[key]
+				eRouteCtx.IPRangeControlByRuleIdx = map[int]*emitterir.IPRangeControl{0: {
+					Metadata: emitterir.NewExtensionFeatureMetadata("default/ing",
+						[]*field.Path{field.NewPath("default", "ing", "metadata", "annotations", fmt.Sprintf("%q", WhiteListSourceRangeAnnotation))}, ""),
+					AllowList: tc.sourceRanges,
+				}}
+				eIR.HTTPRoutes[key] = eRouteCtx
+			}
+
+			warningCount := 0
+			p := &Provider{notify: func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				if mType == notifications.WarningNotification {
+					warningCount++
+				}
+			}}
+			p.applySnippetsToEmitterIR(pIR, &eIR, nil)
+
+			ipRangeControl := eIR.HTTPRoutes[key].IPRangeControlByRuleIdx[0]
+			if ipRangeControl == nil {
+				t.Fatalf("Expected an IP range control on rule 0")
+			}
+			if diff := cmp.Diff(tc.expectedAllowList, ipRangeControl.AllowList); diff != "" {
+				t.Errorf("Unexpected allow list (-want +got):\n%s", diff)
+			}
+			expectedPath := field.NewPath("default", "ing", "metadata", "annotations", fmt.Sprintf("%q", tc.expectedAnnotation)).String()
+			if paths := ipRangeControl.Metadata.Paths(); len(paths) != 1 || paths[0].String() != expectedPath {
+				t.Errorf("Expected IP range control paths [%s], got %v", expectedPath, paths)
+			}
+			if warningCount != tc.expectedWarningCount {
+				t.Errorf("Expected %d warnings, got %d", tc.expectedWarningCount, warningCount)
+			}
+		})
+	}
+}
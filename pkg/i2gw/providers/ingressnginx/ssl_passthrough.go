@@ -0,0 +1,204 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// passthroughHost identifies a host whose TLS connections are passed through on a Gateway.
+type passthroughHost struct {
+	gateway types.NamespacedName
+	host    string
+}
+
+// sslPassthroughFeature converts the Ingresses with SSL passthrough into TLSRoutes,
+// routing TLS connections by SNI to the backend of the root path of each host, and
+// a Passthrough listener on port 443 for each host. The backends terminate TLS, so
+// the HTTP rules and listeners of those hosts are removed.
+func sslPassthroughFeature(notify notifications.NotifyFunc, ingresses []networkingv1.Ingress, servicePorts map[types.NamespacedName]map[string]int32, ir *providerir.ProviderIR) field.ErrorList {
+	var errs field.ErrorList
+	passthroughIngresses := map[passthroughHost]string{}
+
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		value, ok := ingress.Annotations[SSLPassthroughAnnotation]
+		if !ok {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Invalid %s annotation %q, SSL passthrough is disabled", SSLPassthroughAnnotation, value), ingress)
+			continue
+		}
+		if !enabled {
+			continue
+		}
+
+		ingressClass := common.GetIngressClass(*ingress)
+		for ruleIdx, rule := range ingress.Spec.Rules {
+			rulePath := field.NewPath(ingress.Namespace, ingress.Name, "spec", "rules").Index(ruleIdx)
+			if rule.Host == "" {
+				notify(notifications.WarningNotification,
+					fmt.Sprintf("SSL passthrough requires a host, rule %d of Ingress %s/%s is converted to HTTP rules", ruleIdx, ingress.Namespace, ingress.Name), ingress)
+				continue
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+
+			// Like ingress-nginx, only the backend of the root path receives the connections.
+			rootPathIdx := slices.IndexFunc(rule.HTTP.Paths, func(path networkingv1.HTTPIngressPath) bool {
+				return path.Path == "" || path.Path == "/"
+			})
+			if rootPathIdx == -1 {
+				notify(notifications.WarningNotification,
+					fmt.Sprintf("SSL passthrough requires a root path, host %q of Ingress %s/%s is converted to HTTP rules", rule.Host, ingress.Namespace, ingress.Name), ingress)
+				continue
+			}
+
+			key := passthroughHost{
+				gateway: types.NamespacedName{Namespace: ingress.Namespace, Name: ingressClass},
+				host:    rule.Host,
+			}
+			if owner, exists := passthroughIngresses[key]; exists {
+				if owner != ingress.Name {
+					notify(notifications.WarningNotification,
+						fmt.Sprintf("TLS connections to host %q are already passed through by Ingress %s/%s, the SSL passthrough of Ingress %s/%s is ignored", rule.Host, ingress.Namespace, owner, ingress.Namespace, ingress.Name), ingress)
+				}
+				continue
+			}
+
+			backendRef, fieldErr := common.ToBackendRef(ingress.Namespace, rule.HTTP.Paths[rootPathIdx].Backend, servicePorts, rulePath.Child("http", "paths").Index(rootPathIdx).Child("backend"))
+			if fieldErr != nil {
+				errs = append(errs, fieldErr)
+				continue
+			}
+			passthroughIngresses[key] = ingress.Name
+
+			tlsRoute := buildPassthroughTLSRoute(ingress, ingressClass, rule.Host, *backendRef)
+			ir.TLSRoutes[types.NamespacedName{Namespace: tlsRoute.Namespace, Name: tlsRoute.Name}] = tlsRoute
+			removedRoutes := removeHostHTTPRoutes(ir, key)
+			addPassthroughListener(ir, key)
+
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("TLS connections to host %q are passed through to backend %s, so path-based routing is lost and the HTTP rules of the host are removed: %v", rule.Host, backendRef.Name, removedRoutes), ingress)
+		}
+	}
+
+	return errs
+}
+
+// buildPassthroughTLSRoute returns the TLSRoute passing the TLS connections to the host through to the backend.
+func buildPassthroughTLSRoute(ingress *networkingv1.Ingress, ingressClass, host string, backendRef gatewayv1.BackendRef) gatewayv1.TLSRoute {
+	tlsRoute := gatewayv1.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.RouteName(ingress.Name, host),
+			Namespace: ingress.Namespace,
+		},
+		Spec: gatewayv1.TLSRouteSpec{
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(host)},
+			Rules: []gatewayv1.TLSRouteRule{{
+				BackendRefs: []gatewayv1.BackendRef{backendRef},
+			}},
+		},
+		Status: gatewayv1.TLSRouteStatus{
+			RouteStatus: gatewayv1.RouteStatus{
+				Parents: []gatewayv1.RouteParentStatus{},
+			},
+		},
+	}
+	tlsRoute.SetGroupVersionKind(common.TLSRouteGVK)
+
+	if ingressClass != "" {
+		tlsRoute.Spec.ParentRefs = []gatewayv1.ParentReference{{
+			Name:        gatewayv1.ObjectName(ingressClass),
+			SectionName: ptr.To(passthroughListenerName(host)),
+		}}
+	}
+	return tlsRoute
+}
+
+// passthroughListenerName returns the name of the Passthrough listener of the host.
+func passthroughListenerName(host string) gatewayv1.SectionName {
+	return gatewayv1.SectionName(fmt.Sprintf("%s-passthrough", common.NameFromHost(host)))
+}
+
+// removeHostHTTPRoutes removes the HTTPRoutes and GRPCRoutes of the host attached to the
+// Gateway, and returns their names.
+func removeHostHTTPRoutes(ir *providerir.ProviderIR, key passthroughHost) []string {
+	var removed []string
+	for routeKey, routeCtx := range ir.HTTPRoutes {
+		if routeAttachedToHost(routeCtx.HTTPRoute.ObjectMeta, routeCtx.HTTPRoute.Spec.CommonRouteSpec, routeCtx.HTTPRoute.Spec.Hostnames, key) {
+			delete(ir.HTTPRoutes, routeKey)
+			removed = append(removed, fmt.Sprintf("HTTPRoute %s", routeKey))
+		}
+	}
+	for routeKey, routeCtx := range ir.GRPCRoutes {
+		if routeAttachedToHost(routeCtx.GRPCRoute.ObjectMeta, routeCtx.GRPCRoute.Spec.CommonRouteSpec, routeCtx.GRPCRoute.Spec.Hostnames, key) {
+			delete(ir.GRPCRoutes, routeKey)
+			removed = append(removed, fmt.Sprintf("GRPCRoute %s", routeKey))
+		}
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+func routeAttachedToHost(meta metav1.ObjectMeta, spec gatewayv1.CommonRouteSpec, hostnames []gatewayv1.Hostname, key passthroughHost) bool {
+	if meta.Namespace != key.gateway.Namespace || !slices.Contains(hostnames, gatewayv1.Hostname(key.host)) {
+		return false
+	}
+	if len(spec.ParentRefs) == 0 {
+		return key.gateway.Name == ""
+	}
+	return slices.ContainsFunc(spec.ParentRefs, func(parentRef gatewayv1.ParentReference) bool {
+		return string(parentRef.Name) == key.gateway.Name
+	})
+}
+
+// addPassthroughListener replaces the listeners of the host with a TLS listener on
+// port 443 in Passthrough mode.
+func addPassthroughListener(ir *providerir.ProviderIR, key passthroughHost) {
+	gatewayCtx := getOrBuildGateway(ir, key.gateway)
+
+	gatewayCtx.Gateway.Spec.Listeners = slices.DeleteFunc(gatewayCtx.Gateway.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+		return listener.Hostname != nil && string(*listener.Hostname) == key.host
+	})
+	gatewayCtx.Gateway.Spec.Listeners = append(gatewayCtx.Gateway.Spec.Listeners, gatewayv1.Listener{
+		Name:     passthroughListenerName(key.host),
+		Hostname: ptr.To(gatewayv1.Hostname(key.host)),
+		Port:     443,
+		Protocol: gatewayv1.TLSProtocolType,
+		TLS: &gatewayv1.ListenerTLSConfig{
+			Mode: ptr.To(gatewayv1.TLSModePassthrough),
+		},
+	})
+	ir.Gateways[key.gateway] = gatewayCtx
+}
@@ -0,0 +1,171 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSSLPassthroughFeature(t *testing.T) {
+	ingressPath := func(path, service string, port networkingv1.ServiceBackendPort) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: service, Port: port},
+			},
+		}
+	}
+	ingress := func(name string, annotations map[string]string, host string, paths ...networkingv1.HTTPIngressPath) networkingv1.Ingress {
+		return networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To("nginx"),
+				Rules: []networkingv1.IngressRule{{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+					},
+				}},
+			},
+		}
+	}
+	passthrough := map[string]string{SSLPassthroughAnnotation: "true"}
+	servicePorts := map[types.NamespacedName]map[string]int32{
+		{Namespace: "default", Name: "secure"}: {"https": 8443},
+	}
+
+	testCases := []struct {
+		name                  string
+		ingresses             []networkingv1.Ingress
+		expectedTLSRoutes     []gatewayv1.TLSRoute
+		expectedHTTPRoutes    []string
+		expectedListeners     []gatewayv1.SectionName
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "passthrough host",
+			ingresses: []networkingv1.Ingress{
+				ingress("app", nil, "app.example.com", ingressPath("/", "app", networkingv1.ServiceBackendPort{Number: 80})),
+				ingress("secure", passthrough, "secure.example.com",
+					ingressPath("/", "secure", networkingv1.ServiceBackendPort{Name: "https"}),
+					ingressPath("/api", "api", networkingv1.ServiceBackendPort{Number: 80})),
+				ingress("secure-other", nil, "secure.example.com", ingressPath("/other", "other", networkingv1.ServiceBackendPort{Number: 80})),
+			},
+			expectedTLSRoutes: []gatewayv1.TLSRoute{{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "TLSRoute"},
+				ObjectMeta: metav1.ObjectMeta{Name: "secure-secure-example-com", Namespace: "default"},
+				Spec: gatewayv1.TLSRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{
+						ParentRefs: []gatewayv1.ParentReference{{
+							Name:        "nginx",
+							SectionName: ptr.To(gatewayv1.SectionName("secure-example-com-passthrough")),
+						}},
+					},
+					Hostnames: []gatewayv1.Hostname{"secure.example.com"},
+					Rules: []gatewayv1.TLSRouteRule{{
+						BackendRefs: []gatewayv1.BackendRef{{
+							BackendObjectReference: gatewayv1.BackendObjectReference{
+								Name: "secure",
+								Port: ptr.To(gatewayv1.PortNumber(8443)),
+							},
+						}},
+					}},
+				},
+				Status: gatewayv1.TLSRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{}}},
+			}},
+			expectedHTTPRoutes:    []string{"app-app-example-com"},
+			expectedListeners:     []gatewayv1.SectionName{"app-example-com-http", "secure-example-com-passthrough"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "passthrough without root path",
+			ingresses: []networkingv1.Ingress{
+				ingress("secure", passthrough, "secure.example.com", ingressPath("/api", "api", networkingv1.ServiceBackendPort{Number: 80})),
+			},
+			expectedHTTPRoutes:    []string{"secure-secure-example-com"},
+			expectedListeners:     []gatewayv1.SectionName{"secure-example-com-http"},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "disabled passthrough",
+			ingresses: []networkingv1.Ingress{
+				ingress("secure", map[string]string{SSLPassthroughAnnotation: "false"}, "secure.example.com", ingressPath("/", "secure", networkingv1.ServiceBackendPort{Number: 443})),
+			},
+			expectedHTTPRoutes:    []string{"secure-secure-example-com"},
+			expectedListeners:     []gatewayv1.SectionName{"secure-example-com-http"},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ir, errs := common.ToIR(tc.ingresses, nil, servicePorts, i2gw.ProviderImplementationSpecificOptions{})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors converting the Ingresses: %v", errs)
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			if errs = sslPassthroughFeature(notify, tc.ingresses, servicePorts, &ir); len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			var tlsRoutes []gatewayv1.TLSRoute
+			for _, tlsRoute := range ir.TLSRoutes {
+				tlsRoutes = append(tlsRoutes, tlsRoute)
+			}
+			if diff := cmp.Diff(tc.expectedTLSRoutes, tlsRoutes); diff != "" {
+				t.Errorf("Unexpected TLSRoutes (-want +got):\n%s", diff)
+			}
+
+			var httpRoutes []string
+			for key := range ir.HTTPRoutes {
+				httpRoutes = append(httpRoutes, key.Name)
+			}
+			if diff := cmp.Diff(tc.expectedHTTPRoutes, httpRoutes); diff != "" {
+				t.Errorf("Unexpected HTTPRoutes (-want +got):\n%s", diff)
+			}
+
+			var listeners []gatewayv1.SectionName
+			for _, listener := range ir.Gateways[types.NamespacedName{Namespace: "default", Name: "nginx"}].Spec.Listeners {
+				listeners = append(listeners, listener.Name)
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package ingressnginx
 import (
 	"sort"
 
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -30,6 +31,27 @@ type OrderedIngressMap struct {
 type storage struct {
 	Ingresses    OrderedIngressMap
 	ServicePorts map[types.NamespacedName]map[string]int32
+	Secrets      map[types.NamespacedName]*apiv1.Secret
+	// Services holds the Services, whose ports may get an appProtocol.
+	Services map[types.NamespacedName]*apiv1.Service
+	// ConfigMaps holds the ConfigMaps referenced by the annotations of the Ingresses.
+	ConfigMaps map[types.NamespacedName]*apiv1.ConfigMap
+	// TCPServices and UDPServices are the ConfigMaps exposing TCP and UDP
+	// services, when they are configured.
+	TCPServices *apiv1.ConfigMap
+	UDPServices *apiv1.ConfigMap
+	// ControllerConfig is the ConfigMap configuring the controller, when it is
+	// configured. Its settings are the global defaults of the annotations.
+	ControllerConfig *apiv1.ConfigMap
+}
+
+// controllerDefaults returns the global defaults of the annotations set by the
+// controller ConfigMap, if any.
+func (s *storage) controllerDefaults() controllerDefaults {
+	if s == nil {
+		return controllerDefaults{}
+	}
+	return controllerDefaults{configMap: s.ControllerConfig}
 }
 
 func newResourcesStorage() *storage {
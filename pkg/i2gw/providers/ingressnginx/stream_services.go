@@ -0,0 +1,194 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// proxyProtocolOption is the option of a stream service decoding or sending the PROXY protocol.
+const proxyProtocolOption = "PROXY"
+
+// streamService is a TCP or UDP service exposed on a port of the controller.
+type streamService struct {
+	port    int32
+	service types.NamespacedName
+	backend gatewayv1.BackendRef
+}
+
+// convertStreamServices converts the services of the tcp-services and udp-services
+// ConfigMaps into TCP and UDP listeners of the Gateway of the ingress class in the
+// namespace of each service, and TCPRoutes and UDPRoutes attached to them.
+func convertStreamServices(notify notifications.NotifyFunc, storage *storage, ingressClass string, ir *providerir.ProviderIR) {
+	if storage.TCPServices != nil {
+		for _, svc := range parseStreamServices(notify, storage.TCPServices, gatewayv1.TCPProtocolType, storage.ServicePorts) {
+			route := gatewayv1alpha2.TCPRoute{
+				ObjectMeta: streamRouteMeta(svc, gatewayv1.TCPProtocolType),
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: streamRouteSpec(svc, gatewayv1.TCPProtocolType, ingressClass),
+					Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: []gatewayv1.BackendRef{svc.backend}}},
+				},
+				Status: gatewayv1alpha2.TCPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{}}},
+			}
+			route.SetGroupVersionKind(common.TCPRouteGVK)
+			ir.TCPRoutes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+			addStreamListener(ir, svc, gatewayv1.TCPProtocolType, ingressClass)
+		}
+	}
+
+	if storage.UDPServices != nil {
+		for _, svc := range parseStreamServices(notify, storage.UDPServices, gatewayv1.UDPProtocolType, storage.ServicePorts) {
+			route := gatewayv1alpha2.UDPRoute{
+				ObjectMeta: streamRouteMeta(svc, gatewayv1.UDPProtocolType),
+				Spec: gatewayv1alpha2.UDPRouteSpec{
+					CommonRouteSpec: streamRouteSpec(svc, gatewayv1.UDPProtocolType, ingressClass),
+					Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: []gatewayv1.BackendRef{svc.backend}}},
+				},
+				Status: gatewayv1alpha2.UDPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{}}},
+			}
+			route.SetGroupVersionKind(common.UDPRouteGVK)
+			ir.UDPRoutes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = route
+			addStreamListener(ir, svc, gatewayv1.UDPProtocolType, ingressClass)
+		}
+	}
+}
+
+// parseStreamServices parses the entries of a tcp-services or udp-services ConfigMap,
+// "<port>": "<namespace>/<service>:<service port>[:PROXY][:PROXY]", sorted by port.
+// Like ingress-nginx, invalid entries are skipped.
+func parseStreamServices(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap, protocol gatewayv1.ProtocolType, servicePorts map[types.NamespacedName]map[string]int32) []streamService {
+	var services []streamService
+	for _, key := range slices.Sorted(maps.Keys(configMap.Data)) {
+		value := configMap.Data[key]
+		port, err := strconv.ParseInt(key, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Invalid %s port %q in ConfigMap %s/%s, the service is ignored", protocol, key, configMap.Namespace, configMap.Name), configMap)
+			continue
+		}
+		if port == 80 || port == 443 {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("%s port %d in ConfigMap %s/%s is reserved for HTTP and HTTPS, the service is ignored", protocol, port, configMap.Namespace, configMap.Name), configMap)
+			continue
+		}
+
+		parts := strings.Split(value, ":")
+		namespace, name, found := strings.Cut(parts[0], "/")
+		if len(parts) < 2 || !found || namespace == "" || name == "" {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Invalid %s service %q for port %d in ConfigMap %s/%s, expected <namespace>/<service>:<port>", protocol, value, port, configMap.Namespace, configMap.Name), configMap)
+			continue
+		}
+		svc := streamService{
+			port:    int32(port),
+			service: types.NamespacedName{Namespace: namespace, Name: name},
+		}
+
+		servicePort, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			resolved, ok := servicePorts[svc.service][parts[1]]
+			if !ok {
+				notify(notifications.WarningNotification,
+					fmt.Sprintf("Cannot find port %q of Service %s for %s port %d in ConfigMap %s/%s, the service is ignored", parts[1], svc.service, protocol, port, configMap.Namespace, configMap.Name), configMap)
+				continue
+			}
+			servicePort = int64(resolved)
+		}
+		svc.backend = gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(name),
+				Port: ptr.To(gatewayv1.PortNumber(servicePort)),
+			},
+		}
+
+		notifyStreamServiceOptions(notify, configMap, protocol, port, parts[2:])
+		services = append(services, svc)
+	}
+
+	slices.SortFunc(services, func(a, b streamService) int {
+		return int(a.port - b.port)
+	})
+	return services
+}
+
+// notifyStreamServiceOptions warns about the options of a stream service. The first
+// PROXY option decodes the PROXY protocol of the clients and the second one sends it
+// to the service, which Gateway API doesn't support.
+func notifyStreamServiceOptions(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap, protocol gatewayv1.ProtocolType, port int64, options []string) {
+	for i, option := range options {
+		switch {
+		case option == "":
+		case option == proxyProtocolOption && protocol == gatewayv1.TCPProtocolType && i == 0:
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("TCP port %d in ConfigMap %s/%s accepts the PROXY protocol from clients, which is not supported by Gateway API and must be configured on the Gateway implementation", port, configMap.Namespace, configMap.Name), configMap)
+		case option == proxyProtocolOption && protocol == gatewayv1.TCPProtocolType && i == 1:
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("TCP port %d in ConfigMap %s/%s sends the PROXY protocol to the service, which is not supported by Gateway API and must be configured on the Gateway implementation", port, configMap.Namespace, configMap.Name), configMap)
+		default:
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Unsupported option %q of %s port %d in ConfigMap %s/%s is ignored", option, protocol, port, configMap.Namespace, configMap.Name), configMap)
+		}
+	}
+}
+
+// streamListenerName returns the name of the listener of the stream service port.
+func streamListenerName(protocol gatewayv1.ProtocolType, port int32) gatewayv1.SectionName {
+	return gatewayv1.SectionName(fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), port))
+}
+
+func streamRouteMeta(svc streamService, protocol gatewayv1.ProtocolType) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      fmt.Sprintf("%s-%s", svc.service.Name, streamListenerName(protocol, svc.port)),
+		Namespace: svc.service.Namespace,
+	}
+}
+
+func streamRouteSpec(svc streamService, protocol gatewayv1.ProtocolType, ingressClass string) gatewayv1.CommonRouteSpec {
+	return gatewayv1.CommonRouteSpec{
+		ParentRefs: []gatewayv1.ParentReference{{
+			Name:        gatewayv1.ObjectName(ingressClass),
+			SectionName: ptr.To(streamListenerName(protocol, svc.port)),
+		}},
+	}
+}
+
+// addStreamListener adds the listener of the stream service to the Gateway of the
+// ingress class in the namespace of the service.
+func addStreamListener(ir *providerir.ProviderIR, svc streamService, protocol gatewayv1.ProtocolType, ingressClass string) {
+	key := types.NamespacedName{Namespace: svc.service.Namespace, Name: ingressClass}
+	gatewayCtx := getOrBuildGateway(ir, key)
+	gatewayCtx.Gateway.Spec.Listeners = append(gatewayCtx.Gateway.Spec.Listeners, gatewayv1.Listener{
+		Name:     streamListenerName(protocol, svc.port),
+		Port:     gatewayv1.PortNumber(svc.port),
+		Protocol: protocol,
+	})
+	ir.Gateways[key] = gatewayCtx
+}
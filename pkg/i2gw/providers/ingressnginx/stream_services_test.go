@@ -0,0 +1,183 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestConvertStreamServices(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		tcpServices           map[string]string
+		udpServices           map[string]string
+		expectedTCPBackends   map[string]gatewayv1.BackendObjectReference
+		expectedUDPBackends   map[string]gatewayv1.BackendObjectReference
+		expectedListeners     map[types.NamespacedName][]gatewayv1.Listener
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name:        "tcp and udp services",
+			tcpServices: map[string]string{"9000": "default/db:5432"},
+			udpServices: map[string]string{"53": "kube-system/kube-dns:dns"},
+			expectedTCPBackends: map[string]gatewayv1.BackendObjectReference{
+				"db-tcp-9000": {Name: "db", Port: ptr.To(gatewayv1.PortNumber(5432))},
+			},
+			expectedUDPBackends: map[string]gatewayv1.BackendObjectReference{
+				"kube-dns-udp-53": {Name: "kube-dns", Port: ptr.To(gatewayv1.PortNumber(5353))},
+			},
+			expectedListeners: map[types.NamespacedName][]gatewayv1.Listener{
+				{Namespace: "default", Name: "nginx"}: {
+					{Name: "default-http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+					{Name: "tcp-9000", Port: 9000, Protocol: gatewayv1.TCPProtocolType},
+				},
+				{Namespace: "kube-system", Name: "nginx"}: {
+					{Name: "udp-53", Port: 53, Protocol: gatewayv1.UDPProtocolType},
+				},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name:        "proxy protocol",
+			tcpServices: map[string]string{"9000": "default/db:5432:PROXY:PROXY", "9001": "default/cache:6379::PROXY"},
+			expectedTCPBackends: map[string]gatewayv1.BackendObjectReference{
+				"db-tcp-9000":    {Name: "db", Port: ptr.To(gatewayv1.PortNumber(5432))},
+				"cache-tcp-9001": {Name: "cache", Port: ptr.To(gatewayv1.PortNumber(6379))},
+			},
+			expectedUDPBackends: map[string]gatewayv1.BackendObjectReference{},
+			expectedListeners: map[types.NamespacedName][]gatewayv1.Listener{
+				{Namespace: "default", Name: "nginx"}: {
+					{Name: "default-http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+					{Name: "tcp-9000", Port: 9000, Protocol: gatewayv1.TCPProtocolType},
+					{Name: "tcp-9001", Port: 9001, Protocol: gatewayv1.TCPProtocolType},
+				},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 3},
+		},
+		{
+			name: "invalid entries",
+			tcpServices: map[string]string{
+				"http": "default/db:5432",
+				"443":  "default/db:5432",
+				"9000": "db:5432",
+				"9001": "default/db:unknown",
+			},
+			udpServices:         map[string]string{"53": "kube-system/kube-dns:53:PROXY"},
+			expectedTCPBackends: map[string]gatewayv1.BackendObjectReference{},
+			expectedUDPBackends: map[string]gatewayv1.BackendObjectReference{
+				"kube-dns-udp-53": {Name: "kube-dns", Port: ptr.To(gatewayv1.PortNumber(53))},
+			},
+			expectedListeners: map[types.NamespacedName][]gatewayv1.Listener{
+				{Namespace: "default", Name: "nginx"}: {
+					{Name: "default-http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				},
+				{Namespace: "kube-system", Name: "nginx"}: {
+					{Name: "udp-53", Port: 53, Protocol: gatewayv1.UDPProtocolType},
+				},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newResourcesStorage()
+			storage.ServicePorts = map[types.NamespacedName]map[string]int32{
+				{Namespace: "kube-system", Name: "kube-dns"}: {"dns": 5353},
+			}
+			if tc.tcpServices != nil {
+				storage.TCPServices = &apiv1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "tcp-services"},
+					Data:       tc.tcpServices,
+				}
+			}
+			if tc.udpServices != nil {
+				storage.UDPServices = &apiv1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "udp-services"},
+					Data:       tc.udpServices,
+				}
+			}
+
+			ir := providerir.ProviderIR{
+				Gateways: map[types.NamespacedName]providerir.GatewayContext{
+					{Namespace: "default", Name: "nginx"}: {
+						Gateway: gatewayv1.Gateway{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+							Spec: gatewayv1.GatewaySpec{
+								GatewayClassName: "nginx",
+								Listeners: []gatewayv1.Listener{
+									{Name: "default-http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+								},
+							},
+						},
+					},
+				},
+				TCPRoutes: map[types.NamespacedName]gatewayv1alpha2.TCPRoute{},
+				UDPRoutes: map[types.NamespacedName]gatewayv1alpha2.UDPRoute{},
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+
+			convertStreamServices(notify, storage, "nginx", &ir)
+
+			tcpBackends := map[string]gatewayv1.BackendObjectReference{}
+			for key, route := range ir.TCPRoutes {
+				if len(route.Spec.ParentRefs) != 1 || ptr.Deref(route.Spec.ParentRefs[0].SectionName, "") == "" {
+					t.Errorf("Expected TCPRoute %s to be attached to a listener, got %+v", key, route.Spec.ParentRefs)
+				}
+				tcpBackends[key.Name] = route.Spec.Rules[0].BackendRefs[0].BackendObjectReference
+			}
+			if diff := cmp.Diff(tc.expectedTCPBackends, tcpBackends); diff != "" {
+				t.Errorf("Unexpected TCPRoutes (-want +got):\n%s", diff)
+			}
+
+			udpBackends := map[string]gatewayv1.BackendObjectReference{}
+			for key, route := range ir.UDPRoutes {
+				udpBackends[key.Name] = route.Spec.Rules[0].BackendRefs[0].BackendObjectReference
+			}
+			if diff := cmp.Diff(tc.expectedUDPBackends, udpBackends); diff != "" {
+				t.Errorf("Unexpected UDPRoutes (-want +got):\n%s", diff)
+			}
+
+			listeners := map[types.NamespacedName][]gatewayv1.Listener{}
+			for key, gatewayCtx := range ir.Gateways {
+				listeners[key] = gatewayCtx.Spec.Listeners
+			}
+			if diff := cmp.Diff(tc.expectedListeners, listeners); diff != "" {
+				t.Errorf("Unexpected listeners (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
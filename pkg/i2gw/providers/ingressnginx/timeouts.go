@@ -94,14 +94,16 @@ func (p *Provider) applyTimeoutsToEmitterIR(pIR providerir.ProviderIR, eIR *emit
 }
 
 func (p *Provider) parseIngressNginxTimeoutAnnotation(ingress *networkingv1.Ingress, annotation string) *gatewayv1.Duration {
-	val, ok := ingress.Annotations[annotation]
+	defaults := p.storage.controllerDefaults()
+	val, ok := defaults.annotation(ingress, annotation)
 	if !ok || val == "" {
 		return nil
 	}
 	d, err := parseIngressNginxTimeout(val)
 	if err != nil {
-		p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid timeout annotation %s=%q: %v, skipping timeout",
-			annotation, val, err), ingress)
+		source, obj := defaults.source(ingress, annotation)
+		p.notify(notifications.WarningNotification, fmt.Sprintf("Invalid timeout %q from %s: %v, skipping timeout",
+			val, source, err), obj)
 		return nil
 	}
 	gwDur := gatewayv1.Duration(d.String())
@@ -0,0 +1,305 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// tlsVersions are the TLS versions in increasing order, with the ssl-protocols
+// names of ingress-nginx.
+var tlsVersions = []struct {
+	protocol string
+	version  string
+}{
+	{"TLSv1", "1.0"},
+	{"TLSv1.1", "1.1"},
+	{"TLSv1.2", "1.2"},
+	{"TLSv1.3", "1.3"},
+}
+
+// cipherNameRegex matches the OpenSSL names of the cipher suites, as opposed to the
+// keywords and operators of cipher lists, such as HIGH or !aNULL.
+var cipherNameRegex = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)+$`)
+
+// controllerTLSOptions returns the TLS options of the controller ConfigMap, which
+// apply to every server with TLS, or nil when the ConfigMap doesn't configure any.
+func controllerTLSOptions(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap) *emitterir.ListenerTLSOptions {
+	options := &emitterir.ListenerTLSOptions{}
+	var paths []*field.Path
+	keyPath := func(key string) *field.Path {
+		return field.NewPath(configMap.Namespace, configMap.Name, "data", key)
+	}
+
+	if value, ok := configMap.Data[sslProtocolsKey]; ok {
+		paths = append(paths, keyPath(sslProtocolsKey))
+		options.MinVersion, options.MaxVersion = parseSSLProtocols(notify, configMap, value)
+	}
+
+	if _, ok := configMap.Data[useHTTP2Key]; ok {
+		paths = append(paths, keyPath(useHTTP2Key))
+		if !controllerBool(notify, configMap, useHTTP2Key, true) {
+			options.ALPNProtocols = []string{"http/1.1"}
+		}
+	}
+
+	options.HSTS = controllerHSTS(notify, configMap)
+	if options.HSTS != nil {
+		hstsPaths := len(paths)
+		for _, key := range hstsKeys {
+			if _, ok := configMap.Data[key]; ok {
+				paths = append(paths, keyPath(key))
+			}
+		}
+		if len(paths) == hstsPaths {
+			// HSTS is enabled by default.
+			paths = append(paths, keyPath(hstsKey))
+		}
+	}
+
+	if !options.HasTLSParameters() && options.HSTS == nil {
+		return nil
+	}
+	options.Metadata = emitterir.NewExtensionFeatureMetadata(fmt.Sprintf("%s/%s", configMap.Namespace, configMap.Name), paths, "")
+	return options
+}
+
+// parseSSLProtocols returns the lowest and highest TLS versions of the ssl-protocols
+// of the controller ConfigMap. SSL versions aren't supported by Gateway API
+// implementations, and the versions between the lowest and highest are enabled.
+func parseSSLProtocols(notify notifications.NotifyFunc, configMap *apiv1.ConfigMap, value string) (string, string) {
+	var enabled []int
+	for _, protocol := range strings.Fields(value) {
+		idx := slices.IndexFunc(tlsVersions, func(v struct{ protocol, version string }) bool { return v.protocol == protocol })
+		if idx == -1 {
+			notify(notifications.WarningNotification,
+				fmt.Sprintf("Unsupported protocol %q in %s of controller ConfigMap %s/%s is ignored", protocol, sslProtocolsKey, configMap.Namespace, configMap.Name), configMap)
+			continue
+		}
+		enabled = append(enabled, idx)
+	}
+	if len(enabled) == 0 {
+		return "", ""
+	}
+
+	slices.Sort(enabled)
+	enabled = slices.Compact(enabled)
+	minIdx, maxIdx := enabled[0], enabled[len(enabled)-1]
+	if len(enabled) != maxIdx-minIdx+1 {
+		notify(notifications.WarningNotification,
+			fmt.Sprintf("The %s of controller ConfigMap %s/%s skip TLS versions, every version from %s to %s is enabled",
+				sslProtocolsKey, configMap.Namespace, configMap.Name, tlsVersions[minIdx].protocol, tlsVersions[maxIdx].protocol), configMap)
+	}
+	return tlsVersions[minIdx].version, tlsVersions[maxIdx].version
+}
+
+// parseCipherSuites returns the cipher suites of the ssl-ciphers annotation of the
+// Ingress. The keywords and operators of OpenSSL cipher lists aren't converted.
+func (p *Provider) parseCipherSuites(ing *networkingv1.Ingress) []string {
+	defaults := p.storage.controllerDefaults()
+	value, _ := defaults.annotation(ing, SSLCiphersAnnotation)
+	if value == "" {
+		return nil
+	}
+
+	var cipherSuites, ignored []string
+	for _, cipher := range strings.FieldsFunc(value, func(r rune) bool { return r == ':' || r == ' ' || r == ',' }) {
+		if cipherNameRegex.MatchString(cipher) {
+			cipherSuites = append(cipherSuites, cipher)
+		} else {
+			ignored = append(ignored, cipher)
+		}
+	}
+	if len(ignored) > 0 {
+		source, obj := defaults.source(ing, SSLCiphersAnnotation)
+		p.notify(notifications.WarningNotification, fmt.Sprintf("OpenSSL cipher list expressions %s of %s are not converted, only the cipher names are",
+			strings.Join(ignored, ":"), source), obj)
+	}
+
+	if value, ok := defaults.annotation(ing, SSLPreferServerCiphersAnnotation); ok {
+		if preferServer, err := strconv.ParseBool(value); err != nil || !preferServer {
+			source, obj := defaults.source(ing, SSLPreferServerCiphersAnnotation)
+			p.notify(notifications.WarningNotification, fmt.Sprintf("ssl-prefer-server-ciphers %q of %s is not converted, the cipher preference of the Gateway implementation is used",
+				value, source), obj)
+		}
+	}
+	return cipherSuites
+}
+
+// hostCipherSuites are the cipher suites of a host and the Ingress configuring them.
+type hostCipherSuites struct {
+	ing          *networkingv1.Ingress
+	cipherSuites []string
+}
+
+// applyListenerTLSOptionsToEmitterIR stores the TLS options of the HTTPS listeners
+// into the EmitterIR Gateways, which will later be converted by each custom emitter.
+// The controller ConfigMap configures every HTTPS listener with:
+// - ssl-protocols
+// - use-http2
+// - hsts, hsts-max-age, hsts-include-subdomains and hsts-preload
+//
+// The cipher suites are configured per host, by the ssl-ciphers annotation or its
+// default in the controller ConfigMap, while Gateways configure them per listener,
+// so hosts sharing a listener share their cipher suites.
+func (p *Provider) applyListenerTLSOptionsToEmitterIR(pIR providerir.ProviderIR, eIR *emitterir.EmitterIR) {
+	var controllerOptions *emitterir.ListenerTLSOptions
+	if p.storage.ControllerConfig != nil {
+		controllerOptions = controllerTLSOptions(p.notify, p.storage.ControllerConfig)
+	}
+
+	// Ingresses are parsed once, so that their errors are only reported once.
+	parsed := map[types.NamespacedName][]string{}
+	// hostsByListener maps the hosts served by each listener to their cipher
+	// suites, which are nil for hosts without cipher suites.
+	hostsByListener := map[listenerKey]map[string]*hostCipherSuites{}
+
+	routeKeys := slices.SortedFunc(maps.Keys(pIR.HTTPRoutes), func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, key := range routeKeys {
+		pRouteCtx := pIR.HTTPRoutes[key]
+		eRouteCtx, ok := eIR.HTTPRoutes[key]
+		if !ok || len(eRouteCtx.Spec.Hostnames) == 0 {
+			continue
+		}
+
+		var host *hostCipherSuites
+		for ruleIdx := range eRouteCtx.Spec.Rules {
+			if ruleIdx >= len(pRouteCtx.RuleBackendSources) {
+				continue
+			}
+			ing := getNonCanaryIngress(pRouteCtx.RuleBackendSources[ruleIdx])
+			if ing == nil {
+				continue
+			}
+
+			ingKey := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+			cipherSuites, found := parsed[ingKey]
+			if !found {
+				cipherSuites = p.parseCipherSuites(ing)
+				parsed[ingKey] = cipherSuites
+			}
+			// The first Ingress configuring the host wins, as in ingress-nginx.
+			if host == nil && len(cipherSuites) > 0 {
+				host = &hostCipherSuites{ing: ing, cipherSuites: cipherSuites}
+			}
+		}
+		if host == nil {
+			continue
+		}
+
+		for _, hostname := range eRouteCtx.Spec.Hostnames {
+			for _, parentRef := range eRouteCtx.Spec.ParentRefs {
+				gwKey := types.NamespacedName{Namespace: eRouteCtx.Namespace, Name: string(parentRef.Name)}
+				if parentRef.Namespace != nil {
+					gwKey.Namespace = string(*parentRef.Namespace)
+				}
+				lKey := listenerKey{gateway: gwKey, listener: httpsListenerName(eIR, eRouteCtx.Namespace, parentRef, string(hostname))}
+				if !hasHTTPSListener(eIR.Gateways[gwKey].Spec.Listeners, lKey.listener) {
+					continue
+				}
+				if hostsByListener[lKey] == nil {
+					hostsByListener[lKey] = map[string]*hostCipherSuites{}
+				}
+				if hostsByListener[lKey][string(hostname)] == nil {
+					hostsByListener[lKey][string(hostname)] = host
+				}
+			}
+		}
+	}
+
+	for gwKey, gatewayContext := range eIR.Gateways {
+		for _, listener := range gatewayContext.Spec.Listeners {
+			if listener.Protocol != gatewayv1.HTTPSProtocolType {
+				continue
+			}
+			lKey := listenerKey{gateway: gwKey, listener: listener.Name}
+			options := p.listenerTLSOptions(lKey, controllerOptions, hostsByListener[lKey])
+			if options == nil {
+				continue
+			}
+			if gatewayContext.TLSOptionsByListener == nil {
+				gatewayContext.TLSOptionsByListener = make(map[gatewayv1.SectionName]*emitterir.ListenerTLSOptions)
+			}
+			gatewayContext.TLSOptionsByListener[listener.Name] = options
+		}
+		eIR.Gateways[gwKey] = gatewayContext
+	}
+}
+
+// listenerTLSOptions merges the TLS options of the controller ConfigMap with the
+// cipher suites of the hosts served by a listener. It returns nil when the listener
+// has no TLS options.
+func (p *Provider) listenerTLSOptions(lKey listenerKey, controllerOptions *emitterir.ListenerTLSOptions, hosts map[string]*hostCipherSuites) *emitterir.ListenerTLSOptions {
+	var host *hostCipherSuites
+	var hostname string
+	for _, name := range slices.Sorted(maps.Keys(hosts)) {
+		switch {
+		case host == nil:
+			host, hostname = hosts[name], name
+		case !slices.Equal(host.cipherSuites, hosts[name].cipherSuites):
+			p.notify(notifications.WarningNotification, fmt.Sprintf("Hosts %s and %s share listener %s of Gateway %s but configure different cipher suites, the ones of %s are used for both",
+				hostname, name, lKey.listener, lKey.gateway, hostname), hosts[name].ing)
+		}
+	}
+	if controllerOptions == nil && host == nil {
+		return nil
+	}
+
+	options := &emitterir.ListenerTLSOptions{}
+	var source string
+	var paths []*field.Path
+	if controllerOptions != nil {
+		*options = *controllerOptions
+		source = controllerOptions.Metadata.Source()
+		paths = slices.Clone(controllerOptions.Metadata.Paths())
+	}
+	// The cipher suites don't apply to TLS 1.3, in nginx and in the implementations.
+	if host != nil && options.MinVersion != "1.3" {
+		options.CipherSuites = host.cipherSuites
+		source = fmt.Sprintf("%s/%s", host.ing.Namespace, host.ing.Name)
+		paths = append(paths, p.storage.controllerDefaults().path(host.ing, SSLCiphersAnnotation))
+	}
+	if !options.HasTLSParameters() && options.HSTS == nil {
+		return nil
+	}
+
+	var messages []string
+	if options.HasTLSParameters() {
+		messages = append(messages, "The TLS versions, cipher suites and ALPN protocols of the listener are not supported, the defaults of the implementation are used")
+	}
+	if options.HSTS != nil {
+		messages = append(messages, "HTTP Strict Transport Security is not supported, the Strict-Transport-Security header is not sent")
+	}
+	options.Metadata = emitterir.NewExtensionFeatureMetadata(source, paths, strings.Join(messages, "; "))
+	return options
+}
@@ -0,0 +1,248 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressnginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
+	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestControllerTLSOptions(t *testing.T) {
+	defaultHSTS := &emitterir.HSTS{MaxAge: 31536000, IncludeSubDomains: true}
+
+	testCases := []struct {
+		name                  string
+		data                  map[string]string
+		expected              *emitterir.ListenerTLSOptions
+		expectedPaths         []string
+		expectedNotifications int
+	}{
+		{
+			name:          "defaults",
+			data:          map[string]string{},
+			expected:      &emitterir.ListenerTLSOptions{HSTS: defaultHSTS},
+			expectedPaths: []string{"ingress-nginx.ingress-nginx-controller.data.hsts"},
+		},
+		{
+			name: "protocols and http2",
+			data: map[string]string{sslProtocolsKey: "TLSv1.2 TLSv1.3", useHTTP2Key: "false", hstsKey: "false"},
+			expected: &emitterir.ListenerTLSOptions{
+				MinVersion:    "1.2",
+				MaxVersion:    "1.3",
+				ALPNProtocols: []string{"http/1.1"},
+			},
+			expectedPaths: []string{
+				"ingress-nginx.ingress-nginx-controller.data.ssl-protocols",
+				"ingress-nginx.ingress-nginx-controller.data.use-http2",
+			},
+		},
+		{
+			name: "unsupported and skipped protocols",
+			data: map[string]string{sslProtocolsKey: "SSLv3 TLSv1 TLSv1.3", hstsMaxAgeKey: "600"},
+			expected: &emitterir.ListenerTLSOptions{
+				MinVersion: "1.0",
+				MaxVersion: "1.3",
+				HSTS:       &emitterir.HSTS{MaxAge: 600, IncludeSubDomains: true},
+			},
+			expectedPaths: []string{
+				"ingress-nginx.ingress-nginx-controller.data.ssl-protocols",
+				"ingress-nginx.ingress-nginx-controller.data.hsts-max-age",
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name:     "disabled",
+			data:     map[string]string{hstsKey: "false", useHTTP2Key: "true"},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configMap := &apiv1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"},
+				Data:       tc.data,
+			}
+			actualNotifications := 0
+			notify := func(notifications.MessageType, string, ...client.Object) {
+				actualNotifications++
+			}
+
+			options := controllerTLSOptions(notify, configMap)
+
+			if diff := cmp.Diff(tc.expected, options, cmpopts.IgnoreFields(emitterir.ListenerTLSOptions{}, "Metadata")); diff != "" {
+				t.Errorf("Unexpected TLS options (-want +got):\n%s", diff)
+			}
+			if options != nil {
+				var paths []string
+				for _, path := range options.Metadata.Paths() {
+					paths = append(paths, path.String())
+				}
+				if diff := cmp.Diff(tc.expectedPaths, paths); diff != "" {
+					t.Errorf("Unexpected paths (-want +got):\n%s", diff)
+				}
+			}
+			if actualNotifications != tc.expectedNotifications {
+				t.Errorf("Expected %d notifications, got %d", tc.expectedNotifications, actualNotifications)
+			}
+		})
+	}
+}
+
+func TestApplyListenerTLSOptionsToEmitterIR(t *testing.T) {
+	gwKey := types.NamespacedName{Namespace: "default", Name: "nginx"}
+
+	testCases := []struct {
+		name string
+		// controllerConfig is the data of the controller ConfigMap, if any.
+		controllerConfig map[string]string
+		// annotations are the annotations of the Ingresses, by hostname.
+		annotations map[string]map[string]string
+		// listeners are the HTTPS listeners of the Gateway, by hostname.
+		listeners             map[string]gatewayv1.SectionName
+		expected              map[gatewayv1.SectionName]*emitterir.ListenerTLSOptions
+		expectedNotifications map[notifications.MessageType]int
+	}{
+		{
+			name: "cipher suites of a host",
+			annotations: map[string]map[string]string{
+				"secure.example.com": {SSLCiphersAnnotation: "ECDHE-RSA-AES128-GCM-SHA256:HIGH:!aNULL"},
+				"open.example.com":   nil,
+			},
+			listeners: map[string]gatewayv1.SectionName{"secure.example.com": "secure", "open.example.com": "open"},
+			expected: map[gatewayv1.SectionName]*emitterir.ListenerTLSOptions{
+				"secure": {CipherSuites: []string{"ECDHE-RSA-AES128-GCM-SHA256"}},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+		{
+			name: "hosts sharing a listener",
+			annotations: map[string]map[string]string{
+				"a.example.com": {SSLCiphersAnnotation: "ECDHE-RSA-AES128-GCM-SHA256"},
+				"b.example.com": {SSLCiphersAnnotation: "ECDHE-RSA-AES256-GCM-SHA384", SSLPreferServerCiphersAnnotation: "false"},
+			},
+			listeners: map[string]gatewayv1.SectionName{"*.example.com": "wildcard"},
+			expected: map[gatewayv1.SectionName]*emitterir.ListenerTLSOptions{
+				"wildcard": {CipherSuites: []string{"ECDHE-RSA-AES128-GCM-SHA256"}},
+			},
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 2},
+		},
+		{
+			name:             "controller config with TLS 1.3 only",
+			controllerConfig: map[string]string{sslProtocolsKey: "TLSv1.3"},
+			annotations: map[string]map[string]string{
+				"secure.example.com": {SSLCiphersAnnotation: "ECDHE-RSA-AES128-GCM-SHA256"},
+			},
+			listeners: map[string]gatewayv1.SectionName{"secure.example.com": "secure", "open.example.com": "open"},
+			expected: map[gatewayv1.SectionName]*emitterir.ListenerTLSOptions{
+				"secure": {MinVersion: "1.3", MaxVersion: "1.3", HSTS: &emitterir.HSTS{MaxAge: 31536000, IncludeSubDomains: true}},
+				"open":   {MinVersion: "1.3", MaxVersion: "1.3", HSTS: &emitterir.HSTS{MaxAge: 31536000, IncludeSubDomains: true}},
+			},
+			expectedNotifications: map[notifications.MessageType]int{},
+		},
+		{
+			name: "no TLS options",
+			annotations: map[string]map[string]string{
+				"secure.example.com": {SSLCiphersAnnotation: "HIGH:!aNULL"},
+			},
+			listeners:             map[string]gatewayv1.SectionName{"secure.example.com": "secure"},
+			expected:              nil,
+			expectedNotifications: map[notifications.MessageType]int{notifications.WarningNotification: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: gwKey.Namespace, Name: gwKey.Name}}
+			for hostname, name := range tc.listeners {
+				gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
+					Name:     name,
+					Hostname: ptr.To(gatewayv1.Hostname(hostname)),
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+				})
+			}
+
+			pIR := providerir.ProviderIR{HTTPRoutes: map[types.NamespacedName]providerir.HTTPRouteContext{}}
+			eIR := emitterir.EmitterIR{
+				Gateways:   map[types.NamespacedName]emitterir.GatewayContext{gwKey: {Gateway: gateway}},
+				HTTPRoutes: map[types.NamespacedName]emitterir.HTTPRouteContext{},
+			}
+			for hostname, annotations := range tc.annotations {
+				ing := networkingv1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        strings.Split(hostname, ".")[0],
+						Namespace:   "default",
+						Annotations: annotations,
+					},
+				}
+				key := types.NamespacedName{Namespace: ing.Namespace, Name: common.RouteName(ing.Name, hostname)}
+				route := gatewayv1.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{Namespace: ing.Namespace, Name: key.Name},
+					Spec: gatewayv1.HTTPRouteSpec{
+						CommonRouteSpec: gatewayv1.CommonRouteSpec{
+							ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gwKey.Name)}},
+						},
+						Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(hostname)},
+						Rules:     []gatewayv1.HTTPRouteRule{{}},
+					},
+				}
+				pIR.HTTPRoutes[key] = providerir.HTTPRouteContext{
+					HTTPRoute:          route,
+					RuleBackendSources: [][]providerir.BackendSource{{{Ingress: &ing}}},
+				}
+				eIR.HTTPRoutes[key] = emitterir.HTTPRouteContext{HTTPRoute: route}
+			}
+
+			actualNotifications := map[notifications.MessageType]int{}
+			notify := func(mType notifications.MessageType, _ string, _ ...client.Object) {
+				actualNotifications[mType]++
+			}
+			p := &Provider{notify: notify, storage: &storage{}}
+			if tc.controllerConfig != nil {
+				p.storage.ControllerConfig = &apiv1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ingress-nginx", Name: "ingress-nginx-controller"},
+					Data:       tc.controllerConfig,
+				}
+			}
+
+			p.applyListenerTLSOptionsToEmitterIR(pIR, &eIR)
+
+			result := eIR.Gateways[gwKey].TLSOptionsByListener
+			if diff := cmp.Diff(tc.expected, result, cmpopts.IgnoreFields(emitterir.ListenerTLSOptions{}, "Metadata")); diff != "" {
+				t.Errorf("Unexpected TLS options (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedNotifications, actualNotifications); diff != "" {
+				t.Errorf("Unexpected notifications (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
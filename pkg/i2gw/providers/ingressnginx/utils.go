@@ -17,12 +17,24 @@ limitations under the License.
 package ingressnginx
 
 import (
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/providers/common"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
+// serviceHostRegex matches the DNS name of a Service, <service>.<namespace>.svc,
+// optionally followed by the cluster domain.
+var serviceHostRegex = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?)\.([a-z0-9]([-a-z0-9]*[a-z0-9])?)\.svc(\..+)?$`)
+
 // getNonCanaryIngress returns the first Ingress source that does not have the canary annotation.
 // If all sources are canaries, or no sources exist, it returns the first available source (or nil).
 // This is used to prioritize the "main" Ingress for reading common annotations.
@@ -43,3 +55,66 @@ func getNonCanaryIngress(sources []providerir.BackendSource) *networkingv1.Ingre
 
 	return nil
 }
+
+// serviceFromURL returns a reference to the Service the URL points to, or nil
+// if the URL host is not the DNS name of a Service of the cluster. The port
+// defaults to the default port of the URL scheme.
+func serviceFromURL(u *url.URL) *gatewayv1.BackendObjectReference {
+	matches := serviceHostRegex.FindStringSubmatch(u.Hostname())
+	if matches == nil {
+		return nil
+	}
+
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		parsed, err := strconv.Atoi(u.Port())
+		if err != nil || parsed < 1 || parsed > 65535 {
+			return nil
+		}
+		port = parsed
+	}
+
+	return &gatewayv1.BackendObjectReference{
+		Name:      gatewayv1.ObjectName(matches[1]),
+		Namespace: ptr.To(gatewayv1.Namespace(matches[3])),
+		Port:      ptr.To(gatewayv1.PortNumber(port)),
+	}
+}
+
+// annotationObjectRef returns the Secret or ConfigMap referenced by the annotation, either
+// as "namespace/name" or as "name" in the namespace of the Ingress.
+func annotationObjectRef(ing *networkingv1.Ingress, annotation string) (types.NamespacedName, bool) {
+	value := strings.TrimSpace(ing.Annotations[annotation])
+	if value == "" {
+		return types.NamespacedName{}, false
+	}
+	if namespace, name, found := strings.Cut(value, "/"); found {
+		return types.NamespacedName{Namespace: namespace, Name: name}, true
+	}
+	return types.NamespacedName{Namespace: ing.Namespace, Name: value}, true
+}
+
+// getOrBuildGateway returns the Gateway context of the key, or a new Gateway named
+// after the ingress class like the Gateways generated from the Ingresses. The caller
+// stores the context back into the IR.
+func getOrBuildGateway(ir *providerir.ProviderIR, key types.NamespacedName) providerir.GatewayContext {
+	if gatewayCtx, ok := ir.Gateways[key]; ok {
+		return gatewayCtx
+	}
+	gatewayCtx := providerir.GatewayContext{
+		Gateway: gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(key.Name),
+			},
+		},
+	}
+	gatewayCtx.Gateway.SetGroupVersionKind(common.GatewayGVK)
+	return gatewayCtx
+}
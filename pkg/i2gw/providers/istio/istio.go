@@ -25,6 +25,7 @@ import (
 	emitterir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/emitter_intermediate"
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/notifications"
 	providerir "github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw/provider_intermediate"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -33,6 +34,12 @@ const ProviderName = "istio"
 
 func init() {
 	i2gw.ProviderConstructorByName[ProviderName] = NewProvider
+	i2gw.ProviderDetectorByName[ProviderName] = i2gw.DetectionRules{
+		Kinds: []schema.GroupKind{
+			{Group: "networking.istio.io", Kind: GatewayKind},
+			{Group: "networking.istio.io", Kind: VirtualServiceKind},
+		},
+	}.Detect
 }
 
 type Provider struct {
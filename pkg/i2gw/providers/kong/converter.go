@@ -61,6 +61,7 @@ func (c *resourcesToIRConverter) convert(storage *storage) (providerir.ProviderI
 	if len(errorList) > 0 {
 		return providerir.ProviderIR{}, errorList
 	}
+	common.InspectTLSSecrets(c.notify, &ir, storage.Secrets)
 
 	tcpGatewayIR, errs := crds.TCPIngressToGatewayIR(c.notify, storage.TCPIngresses)
 	if len(errs) > 0 {
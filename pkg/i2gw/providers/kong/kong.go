@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubernetes-sigs/ingress2gateway/pkg/i2gw"
@@ -34,6 +35,12 @@ const KongIngressClass = "kong"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.ProviderDetectorByName[Name] = i2gw.DetectionRules{
+		IngressClasses:     []string{KongIngressClass},
+		Controllers:        []string{"ingress-controllers.konghq.com/kong"},
+		AnnotationPrefixes: []string{annotationPrefix + "/"},
+		Kinds:              []schema.GroupKind{tcpIngressGVK.GroupKind()},
+	}.Detect
 }
 
 // Provider implements the i2gw.Provider interface.
@@ -67,6 +67,12 @@ func (r *resourceReader) readResourcesFromCluster(ctx context.Context) (*storage
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
 
+	secrets, err := common.ReadTLSSecretsFromCluster(ctx, r.conf.Client, r.conf.Report.Notifier(Name))
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
+
 	return storage, nil
 }
 
@@ -91,6 +97,12 @@ func (r *resourceReader) readResourcesFromFile(reader io.Reader) (*storage, erro
 	}
 	storage.ServicePorts = common.GroupServicePortsByPortName(services)
 
+	secrets, err := common.ReadTLSSecretsFromFile(reader, r.conf.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	storage.Secrets = secrets
+
 	return storage, nil
 }
 
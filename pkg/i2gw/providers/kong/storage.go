@@ -18,6 +18,7 @@ package kong
 
 import (
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -26,6 +27,7 @@ type storage struct {
 	Ingresses    map[types.NamespacedName]*networkingv1.Ingress
 	TCPIngresses []kongv1beta1.TCPIngress
 	ServicePorts map[types.NamespacedName]map[string]int32
+	Secrets      map[types.NamespacedName]*apiv1.Secret
 }
 
 func newResourceStorage() *storage {
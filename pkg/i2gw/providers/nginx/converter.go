@@ -63,6 +63,7 @@ func (c *resourcesToIRConverter) convert(storage *storage) (providerir.ProviderI
 	if len(errorList) > 0 {
 		return providerir.ProviderIR{}, errorList
 	}
+	common.InspectTLSSecrets(c.notify, &ir, storage.Secrets)
 
 	for _, parseFeatureFunc := range c.featureParsers {
 		errs := parseFeatureFunc(c.notify, ingressList, storage.ServicePorts, &ir)
@@ -32,6 +32,11 @@ const Name = "nginx"
 
 func init() {
 	i2gw.ProviderConstructorByName[Name] = NewProvider
+	i2gw.ProviderDetectorByName[Name] = i2gw.DetectionRules{
+		IngressClasses:     NginxIngressClasses.UnsortedList(),
+		Controllers:        []string{"nginx.org/ingress-controller"},
+		AnnotationPrefixes: []string{"nginx.org/", "nginx.com/"},
+	}.Detect
 }
 
 type Provider struct {